@@ -1,25 +1,51 @@
 package routes
 
 import (
-	"log"
+	"proofofpeacemaking/internal/config"
+	"proofofpeacemaking/internal/core/credential"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/services"
 	"proofofpeacemaking/internal/handlers"
+	"proofofpeacemaking/internal/metrics"
 	"proofofpeacemaking/internal/middleware"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
-	// Add CORS middleware
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE",
-		AllowHeaders: "Origin, Content-Type, Accept",
-	}))
+func SetupRoutes(app *fiber.App, h *handlers.Handlers, cfg *config.Config) {
+	// Probes and metrics are registered first so they skip request logging
+	app.Get("/healthz", h.Health.Liveness)
+	app.Get("/readyz", h.Health.Readiness)
+	app.Get("/metrics", middleware.RequireBearer(cfg.Security.MetricsToken), adaptor.HTTPHandler(metrics.Handler()))
+
+	// Tag every request with an ID and a trace span and log it once it completes
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Tracing())
+	app.Use(middleware.AccessLog())
+	app.Use(middleware.HTTPMetrics())
+	app.Use(middleware.HandleErrors())
+
+	// Only allowlisted origins may call the API from another site. Without an
+	// allowlist no CORS headers are sent and browsers keep requests same-origin.
+	allowedOrigins := cfg.Security.AllowedOrigins
+	if len(allowedOrigins) > 0 {
+		app.Use(cors.New(cors.Config{
+			AllowOrigins:     strings.Join(allowedOrigins, ","),
+			AllowMethods:     "GET,POST,PUT,DELETE",
+			AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+			AllowCredentials: true,
+		}))
+	}
+
+	// Security headers and CSRF origin checks for cookie-authenticated requests.
+	// Attestations and credentials are verified by servers and scripts as well
+	// as browsers, so those checks are open to any origin.
+	security := middleware.NewSecurityMiddleware(allowedOrigins, cfg.Security.ContentSecurityPolicy, !cfg.Development())
+	app.Use(security.Headers())
+	app.Use(security.VerifyOrigin("/attestations/verify", "/credentials/verify"))
 
 	// [DEVELOPMENT PURPOSE] Add cache control headers for HTML templates
 	// app.Use(func(c *fiber.Ctx) error {
@@ -36,42 +62,10 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 	// 	return c.Next()
 	// })
 
-	// Add error handling middleware
-	app.Use(func(c *fiber.Ctx) error {
-		err := c.Next()
-		if err != nil {
-			log.Printf("[ERROR] Path: %s, Error: %v", c.Path(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return nil
-	})
-
-	// Add request logging middleware
-	app.Use(func(c *fiber.Ctx) error {
-		start := time.Now()
-		path := c.Path()
-		method := c.Method()
-		log.Printf("[REQUEST] %s %s", method, path)
-		if method == "GET" {
-			log.Printf("[QUERY] %s", c.Context().QueryArgs().String())
-		}
-
-		err := c.Next()
-
-		duration := time.Since(start)
-		status := c.Response().StatusCode()
-		log.Printf("[RESPONSE] %s %s - Status: %d - Duration: %v", method, path, status, duration)
-		if err != nil {
-			log.Printf("[ERROR] %s %s - Error: %v", method, path, err)
-		}
-
-		return err
-	})
-
 	// Create middleware using auth service from handlers
-	authMiddleware := middleware.NewAuthMiddleware(h.Auth.GetAuthService())
+	authMiddleware := middleware.NewAuthMiddleware(h.Auth.GetAuthService(), h.APIToken.GetAPITokenService())
+	roleMiddleware := middleware.NewRoleMiddleware(h.User.GetUserService())
+	rateLimit := middleware.NewRateLimitMiddleware(h.Auth.GetRateLimitService())
 
 	// Create feed handler with user service
 	feedHandler := handlers.NewFeedHandler(h.Feed.GetFeedService(), h.User.GetUserService())
@@ -100,9 +94,9 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 				var user *domain.User
 				var err error
 				if strings.Contains(identifier, "@") {
-					user, err = h.User.GetUserService().GetUserByEmail(c.Context(), identifier)
+					user, err = h.User.GetUserService().GetUserByEmail(c.UserContext(), identifier)
 				} else {
-					user, err = h.User.GetUserService().GetUserByAddress(c.Context(), identifier)
+					user, err = h.User.GetUserService().GetUserByAddress(c.UserContext(), identifier)
 				}
 				if err == nil && user != nil {
 					data["User"] = fiber.Map{"Email": user.Email, "Address": user.Address}
@@ -124,9 +118,9 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 				var user *domain.User
 				var err error
 				if strings.Contains(identifier, "@") {
-					user, err = h.User.GetUserService().GetUserByEmail(c.Context(), identifier)
+					user, err = h.User.GetUserService().GetUserByEmail(c.UserContext(), identifier)
 				} else {
-					user, err = h.User.GetUserService().GetUserByAddress(c.Context(), identifier)
+					user, err = h.User.GetUserService().GetUserByAddress(c.UserContext(), identifier)
 				}
 				if err == nil && user != nil {
 					data["User"] = fiber.Map{"Email": user.Email, "Address": user.Address}
@@ -137,30 +131,50 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 		return c.Render("learn", data, "")
 	})
 
+	// Public pages of expressions and proofs, linked from certificates and shared posts
+	app.Get("/e/:id", authMiddleware.Optional(), h.Verification.ServeExpressionPage)
+	app.Get("/verify/:id", authMiddleware.Optional(), h.Verification.ServeVerifyPage)
+
+	// Invite links walk people without an account through registration
+	app.Get("/invite/:token", authMiddleware.Optional(), h.Invitation.ServeInvitePage)
+
 	app.Post("/join-newsletter", newsletterHandler.HandleNewsletterRegistration)
 
 	// Public auth routes
-	app.Get("/auth/nonce", h.Auth.GenerateNonce)
+	app.Get("/auth/nonce", rateLimit.ByIP(domain.RateLimitNonce), h.Auth.GenerateNonce)
 	app.Get("/auth/session", h.Auth.GetSession)
-	app.Post("/auth/verify", h.Auth.VerifySignature)
-	app.Post("/auth/register", h.Auth.Register)
-	app.Post("/auth/register-email", h.Auth.RegisterWithEmail)
-	app.Post("/auth/login-email", h.Auth.LoginWithEmail)
+	app.Post("/auth/verify", rateLimit.ByIP(domain.RateLimitLogin), h.Auth.VerifySignature)
+	app.Post("/auth/register", rateLimit.ByIP(domain.RateLimitLogin), h.Auth.Register)
+	app.Post("/auth/register-email", rateLimit.ByIP(domain.RateLimitLogin), h.Auth.RegisterWithEmail)
+	app.Post("/auth/login-email", rateLimit.ByIP(domain.RateLimitLogin), h.Auth.LoginWithEmail)
 	app.Post("/auth/logout", h.Auth.Logout)
 
+	// Acknowledgement attestations are public so anyone can check them
+	attestations := app.Group("/attestations")
+	attestations.Get("/domain", h.Acknowledgement.GetAttestationDomain)
+	attestations.Post("/verify", rateLimit.ByIP(domain.RateLimitAttestationVerify), h.Acknowledgement.VerifyAttestation)
+	attestations.Get("/acknowledgements/:id", h.Acknowledgement.GetAttestation)
+
+	// Verifiable credentials are checked against the issuer's did:web document
+	app.Get("/.well-known/did.json", h.Credential.DIDDocument)
+	app.Get(credential.ContextPath, h.Credential.Context)
+	app.Post("/credentials/verify", rateLimit.ByIP(domain.RateLimitCredentialVerify), h.Credential.Verify)
+
 	// Statistics routes
 	app.Get("/statistics", h.Statistics.ServeStatisticsPage)
 	stats := app.Group("/statistics")
 	stats.Get("/", h.Statistics.GetStatistics)
 	stats.Get("/countries", h.Statistics.GetCountryList)
-	stats.Post("/update", h.Statistics.UpdateStatistics)
+	stats.Post("/update", authMiddleware.Authenticate(), roleMiddleware.Require(domain.RoleAdmin), h.Statistics.UpdateStatistics)
 
 	// WebAuthn routes
 	webauthn := app.Group("/auth/passkey")
 	webauthn.Post("/register/begin", h.WebAuthn.BeginRegistration)
 	webauthn.Post("/register/finish", h.WebAuthn.FinishRegistration)
 	webauthn.Post("/auth/begin", h.WebAuthn.BeginAuthentication)
-	webauthn.Post("/auth/finish", h.WebAuthn.FinishAuthentication)
+	webauthn.Post("/auth/finish", rateLimit.ByIP(domain.RateLimitLogin), h.WebAuthn.FinishAuthentication)
+	webauthn.Post("/login/begin", h.WebAuthn.BeginDiscoverableLogin)
+	webauthn.Post("/login/finish", rateLimit.ByIP(domain.RateLimitLogin), h.WebAuthn.FinishDiscoverableLogin)
 
 	// Protected routes
 
@@ -181,26 +195,94 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 	notifications.Get("/", h.Notification.GetUserNotifications)
 	notifications.Put("/:id/read", h.Notification.MarkAsRead)
 
+	// Passkey management routes
+	passkeys := api.Group("/passkeys")
+	passkeys.Get("/", h.WebAuthn.ListPasskeys)
+	passkeys.Put("/:id", h.WebAuthn.RenamePasskey)
+	passkeys.Delete("/:id", h.WebAuthn.RevokePasskey)
+
+	// Session management routes
+	sessions := api.Group("/sessions")
+	sessions.Get("/", h.Session.ListSessions)
+	sessions.Delete("/:id", h.Session.RevokeSession)
+
+	// Personal access token routes
+	tokens := api.Group("/tokens")
+	tokens.Get("/", h.APIToken.ListTokens)
+	tokens.Post("/", h.APIToken.CreateToken)
+	tokens.Delete("/:id", h.APIToken.RevokeToken)
+
 	// Expression routes
 	expressions := api.Group("/expressions")
-	expressions.Post("/", h.Expression.Create)
+	expressions.Post("/", rateLimit.ByUser(domain.RateLimitExpressionCreate), roleMiddleware.RequireNotSuspended(), h.Expression.Create)
 	expressions.Get("/", h.Expression.List)
 	expressions.Get("/:id", h.Expression.Get)
 
 	// Acknowledgement routes
 	acknowledgements := api.Group("/acknowledgements")
-	acknowledgements.Post("/", h.Acknowledgement.Create)
+	acknowledgements.Post("/", rateLimit.ByUser(domain.RateLimitAcknowledgement), roleMiddleware.RequireNotSuspended(), h.Acknowledgement.Create)
 	acknowledgements.Get("/expression/:id", h.Acknowledgement.ListByExpression)
 
+	// Dialogue between an expression's creator and an acknowledger
+	acknowledgements.Post("/:id/replies", rateLimit.ByUser(domain.RateLimitReply), roleMiddleware.RequireNotSuspended(), h.Reply.Create)
+	acknowledgements.Get("/:id/replies", h.Reply.List)
+
+	// Directed invitations to acknowledge an expression
+	invitations := api.Group("/invitations")
+	invitations.Post("/", rateLimit.ByUser(domain.RateLimitInvitation), roleMiddleware.RequireNotSuspended(), h.Invitation.Create)
+	invitations.Get("/sent", h.Invitation.ListSent)
+	invitations.Get("/received", h.Invitation.ListReceived)
+	invitations.Post("/accept", h.Invitation.Accept)
+
 	// ProofNFT routes
 	proofs := api.Group("/proofs")
 	proofs.Post("/request", h.ProofNFT.RequestProof)
 	proofs.Put("/approve/:id", h.ProofNFT.ApproveProof)
+	proofs.Put("/reject/:id", h.ProofNFT.RejectProof)
+	proofs.Put("/cancel/:id", h.ProofNFT.CancelProof)
 	proofs.Get("/user", h.ProofNFT.ListUserProofs)
 
 	// User profile routes
 	users := api.Group("/users")
 	users.Put("/profile", accountHandler.UpdateProfile)
 	users.Post("/connect-wallet", accountHandler.ConnectWallet)
-	users.Post("/wallet-nonce", accountHandler.GetWalletNonce)
+	users.Post("/wallet-nonce", rateLimit.ByUser(domain.RateLimitNonce), accountHandler.GetWalletNonce)
+
+	// Gasless relaying of signed contract calls
+	relay := api.Group("/relay")
+	relay.Get("/domain", h.Relayer.GetDomain)
+	relay.Get("/", h.Relayer.List)
+	relay.Post("/", rateLimit.ByUser(domain.RateLimitRelay), roleMiddleware.RequireNotSuspended(), h.Relayer.Submit)
+	relay.Get("/:id", h.Relayer.Get)
+
+	// Verifiable credentials of completed proofs
+	credentials := api.Group("/credentials")
+	credentials.Post("/", h.Credential.Issue)
+	credentials.Get("/", h.Credential.List)
+	credentials.Get("/:id/download", h.Credential.Download)
+
+	// Printable certificates of completed proofs, source is proofNft or proofRequest
+	api.Get("/certificates/:source/:id", h.Certificate.Download)
+
+	// Merkle inclusion proofs of anchored records
+	api.Get("/proofs/inclusion/:id", h.Anchor.GetInclusionProof)
+
+	// Reporting and moderation routes
+	api.Post("/reports", h.Moderation.CreateReport)
+	moderation := api.Group("/moderation", roleMiddleware.Require(domain.RoleModerator))
+	moderation.Get("/queue", h.Moderation.GetQueue)
+	moderation.Get("/cases/:id", h.Moderation.GetCase)
+	moderation.Post("/cases/:id/claim", h.Moderation.ClaimCase)
+	moderation.Post("/cases/:id/escalate", h.Moderation.EscalateCase)
+	moderation.Post("/cases/:id/resolve", h.Moderation.ResolveCase)
+	moderation.Post("/cases/:id/reopen", roleMiddleware.Require(domain.RoleAdmin), h.Moderation.ReopenCase)
+
+	// Admin routes
+	admin := api.Group("/admin", roleMiddleware.Require(domain.RoleAdmin))
+	admin.Put("/users/:id/role", h.Admin.SetUserRole)
+	admin.Put("/users/:id/subsidies", h.Subsidy.SetSubsidies)
+	admin.Get("/subsidies/changes", h.Subsidy.ListChanges)
+	admin.Get("/subsidies/usage", h.Subsidy.Report)
+	admin.Put("/operators/:address/budget", h.Subsidy.SetBudget)
+	admin.Delete("/operators/:address/budget", h.Subsidy.RemoveBudget)
 }
@@ -0,0 +1,72 @@
+// Command admin grants roles from the command line. It exists to bootstrap the
+// first admin account; after that, admins can manage roles through the API.
+//
+// Usage:
+//
+//	go run ./cmd/admin -user alice@example.com -role admin
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"proofofpeacemaking/internal/config"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/repositories/mongodb"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	identifier := flag.String("user", "", "email or wallet address of the user")
+	role := flag.String("role", string(domain.RoleAdmin), "role to grant: user, moderator or admin")
+	flag.Parse()
+
+	if *identifier == "" {
+		log.Fatal("-user is required")
+	}
+	if !domain.ValidRole(domain.Role(*role)) {
+		log.Fatalf("invalid role %q: must be one of user, moderator, admin", *role)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: .env file not found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"), nil)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	db, err := mongodb.Connect(cfg.Mongo.URI, cfg.Mongo.Database)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	userRepo := mongodb.NewUserRepository(db)
+
+	var user *domain.User
+	if strings.Contains(*identifier, "@") {
+		user, err = userRepo.GetByEmail(ctx, *identifier)
+	} else {
+		user, err = userRepo.GetByAddress(ctx, *identifier)
+	}
+	if err != nil {
+		log.Fatalf("Failed to look up user: %v", err)
+	}
+	if user == nil {
+		log.Fatalf("No user found for %s", *identifier)
+	}
+
+	if err := userRepo.SetRole(ctx, user.ID, domain.Role(*role)); err != nil {
+		log.Fatalf("Failed to grant role: %v", err)
+	}
+
+	log.Printf("Granted role %s to %s (%s)", *role, *identifier, user.ID.Hex())
+}
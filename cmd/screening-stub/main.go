@@ -0,0 +1,66 @@
+// Command screening-stub is a stand-in for the content screening webhook during
+// local development. It flags any text containing one of the words passed with
+// -flag and passes everything else.
+//
+// Usage:
+//
+//	go run ./cmd/screening-stub -addr :4010 -flag test-flag
+//	SCREENING_WEBHOOK_URL=http://localhost:4010/screen
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type screenRequest struct {
+	ContentID string `json:"contentId"`
+	Text      string `json:"text"`
+}
+
+type screenResponse struct {
+	Flagged bool     `json:"flagged"`
+	Reason  string   `json:"reason,omitempty"`
+	Matches []string `json:"matches,omitempty"`
+}
+
+func main() {
+	addr := flag.String("addr", ":4010", "address to listen on")
+	words := flag.String("flag", "", "comma-separated words that cause content to be flagged")
+	flag.Parse()
+
+	var flagged []string
+	for _, word := range strings.Split(*words, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			flagged = append(flagged, strings.ToLower(word))
+		}
+	}
+
+	http.HandleFunc("/screen", func(w http.ResponseWriter, r *http.Request) {
+		var req screenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		var resp screenResponse
+		text := strings.ToLower(req.Text)
+		for _, word := range flagged {
+			if strings.Contains(text, word) {
+				resp.Flagged = true
+				resp.Reason = "other"
+				resp.Matches = append(resp.Matches, word)
+			}
+		}
+
+		log.Printf("Screened %s: flagged=%v", req.ContentID, resp.Flagged)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	log.Printf("Screening stub listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -2,66 +2,337 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/big"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"proofofpeacemaking/api/routes"
-	"proofofpeacemaking/internal/core/config"
+	"proofofpeacemaking/internal/config"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/credential"
 	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/invite"
 	"proofofpeacemaking/internal/core/ports"
 	"proofofpeacemaking/internal/core/services"
 	"proofofpeacemaking/internal/core/storage"
 	"proofofpeacemaking/internal/handlers"
+	"proofofpeacemaking/internal/logging"
+	"proofofpeacemaking/internal/mailer"
+	"proofofpeacemaking/internal/ratelimit"
 	"proofofpeacemaking/internal/repositories/mongodb"
+	"proofofpeacemaking/internal/screening"
+	"proofofpeacemaking/internal/tracing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/template/html/v2"
 	"github.com/joho/godotenv"
-	"github.com/mailgun/mailgun-go/v4"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func initServices(db *mongo.Database, mailgunClient *mailgun.MailgunImpl, r2Storage *storage.R2Storage) (
+func initServices(cfg *config.Config, db *mongo.Database, mailer ports.Mailer, mediaStorage storage.Storage, screener ports.ContentScreener) (
 	ports.UserService,
 	ports.AuthService,
 	ports.ExpressionService,
 	ports.AcknowledgementService,
 	ports.ProofNFTService,
+	ports.ExpressionAcknowledgementService,
+	ports.InvitationService,
+	ports.ReplyService,
 	ports.FeedService,
 	ports.NewsletterService,
 	ports.WebAuthnService,
 	ports.SessionService,
 	ports.StatisticsService,
+	ports.NotificationService,
+	ports.APITokenService,
+	ports.ModerationService,
+	ports.RateLimitService,
 ) {
 	// Initialize repositories
 	userRepo := mongodb.NewUserRepository(db)
 	expressionRepo := mongodb.NewExpressionRepository(db)
 	acknowledgementRepo := mongodb.NewAcknowledgementRepository(db)
 	proofNFTRepo := mongodb.NewProofNFTRepository(db)
+	proofRequestRepo := mongodb.NewProofRequestRepository(db)
+	pairingRepo := mongodb.NewExpressionAcknowledgementRepository(db)
 	sessionRepo := mongodb.NewSessionRepository(db)
 	statsRepo := mongodb.NewStatisticsRepository(db)
 	passkeyRepo := mongodb.NewPasskeyRepository(db)
+	notificationRepo := mongodb.NewNotificationRepository(db)
+	securityEventRepo := mongodb.NewSecurityEventRepository(db)
+	apiTokenRepo := mongodb.NewAPITokenRepository(db)
+	moderationRepo := mongodb.NewModerationRepository(db)
+	invitationRepo := mongodb.NewInvitationRepository(db)
+	replyRepo := mongodb.NewReplyRepository(db)
 
 	// Initialize services
 	userService := services.NewUserService(userRepo)
-	authService := services.NewAuthService(userService, sessionRepo)
-	expressionService := services.NewExpressionService(expressionRepo, acknowledgementRepo, r2Storage)
-	acknowledgementService := services.NewAcknowledgementService(acknowledgementRepo)
-	proofNFTService := services.NewProofNFTService(userRepo, proofNFTRepo)
+	notificationService := services.NewNotificationService(notificationRepo, userRepo)
+	moderationService := services.NewModerationService(moderationRepo, expressionRepo, acknowledgementRepo, replyRepo, pairingRepo, userRepo, notificationService)
+	expressionService := services.NewExpressionService(expressionRepo, acknowledgementRepo, mediaStorage, screener, moderationService)
+	invitationService := services.NewInvitationService(invitationRepo, userRepo, expressionService, notificationService, mailer, initInviteSigner(cfg), cfg.SiteURL(), time.Duration(cfg.Invitation.Lifetime))
+	authService := services.NewAuthService(userService, sessionRepo, invitationService)
+	pairingService := services.NewExpressionAcknowledgementService(pairingRepo, expressionRepo, acknowledgementRepo, proofRequestRepo, proofNFTRepo)
+	acknowledgementService := services.NewAcknowledgementService(acknowledgementRepo, pairingService)
+	proofNFTService := services.NewProofNFTService(pairingRepo, proofRequestRepo, proofNFTRepo)
+	replyService := services.NewReplyService(replyRepo, pairingRepo, acknowledgementRepo, mediaStorage, screener, moderationService, notificationService)
 	feedService := services.NewFeedService(expressionService, userService, acknowledgementService)
-	newsletterService := services.NewNewsletterService(mailgunClient)
-	webAuthnService, err := services.NewWebAuthnService(passkeyRepo, userRepo)
+	newsletterService := services.NewNewsletterService(mailer, cfg.Mailer.ContactRecipient)
+	signCountPolicy := domain.ParseSignCountPolicy(cfg.WebAuthn.SignCountPolicy)
+	webAuthnService, err := services.NewWebAuthnService(passkeyRepo, userRepo, securityEventRepo, notificationService, cfg.WebAuthn.RelyingParty, signCountPolicy)
 	if err != nil {
-		log.Fatalf("Failed to initialize WebAuthn service: %v", err)
+		fatal("failed to initialize WebAuthn service", "error", err)
 	}
 	sessionService := services.NewSessionService(sessionRepo)
 	statsService := services.NewStatisticsService(statsRepo, userRepo, expressionRepo)
+	apiTokenService := services.NewAPITokenService(apiTokenRepo, userRepo)
+	rateLimitService := services.NewRateLimitService(initRateLimitStore(cfg.RateLimit.Store, db), domain.DefaultLockoutPolicy)
 
-	return userService, authService, expressionService, acknowledgementService, proofNFTService, feedService, newsletterService, webAuthnService, sessionService, statsService
+	return userService, authService, expressionService, acknowledgementService, proofNFTService, pairingService, invitationService, replyService, feedService, newsletterService, webAuthnService, sessionService, statsService, notificationService, apiTokenService, moderationService, rateLimitService
+}
+
+// initRateLimitStore picks where rate limit counters live. The in-memory store
+// is the default; use mongo when running more than one instance.
+func initRateLimitStore(store string, db *mongo.Database) ports.RateLimitStore {
+	if store == config.RateLimitMongo {
+		return mongodb.NewRateLimitStore(db)
+	}
+	return ratelimit.NewMemoryStore()
+}
+
+// initCredentials sets up the verifiable credential issuer. Without
+// CREDENTIAL_ISSUER_KEY a fresh key is made, so credentials issued before a
+// restart stop verifying; validation requires the key in production.
+func initCredentials(cfg *config.Config, db *mongo.Database) ports.CredentialService {
+	var seed []byte
+	if cfg.Credential.IssuerKey != "" {
+		var err error
+		seed, err = hex.DecodeString(strings.TrimPrefix(cfg.Credential.IssuerKey, "0x"))
+		if err != nil {
+			fatal("invalid credential issuer key", "error", err)
+		}
+	} else {
+		seed = make([]byte, ed25519.SeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			fatal("failed to generate credential issuer key", "error", err)
+		}
+		slog.Warn("CREDENTIAL_ISSUER_KEY is not set, using a temporary key; issued credentials will not verify after a restart")
+	}
+
+	host := cfg.Credential.IssuerHost
+	if host == "" {
+		host = cfg.WebAuthn.RelyingParty
+	}
+	issuer, err := credential.NewIssuer(host, seed)
+	if err != nil {
+		fatal("failed to initialize credential issuer", "error", err)
+	}
+	slog.Info("issuing verifiable credentials", "did", issuer.DID())
+
+	return services.NewCredentialService(
+		mongodb.NewCredentialRepository(db),
+		mongodb.NewProofNFTRepository(db),
+		mongodb.NewProofRequestRepository(db),
+		mongodb.NewExpressionAcknowledgementRepository(db),
+		mongodb.NewExpressionRepository(db),
+		mongodb.NewUserRepository(db),
+		issuer,
+	)
+}
+
+// initInviteSigner loads the key invite links are signed with
+func initInviteSigner(cfg *config.Config) *invite.Signer {
+	var key []byte
+	if cfg.Invitation.SigningKey != "" {
+		var err error
+		key, err = hex.DecodeString(strings.TrimPrefix(cfg.Invitation.SigningKey, "0x"))
+		if err != nil {
+			fatal("invalid invitation signing key", "error", err)
+		}
+	} else {
+		key = make([]byte, invite.KeySize)
+		if _, err := rand.Read(key); err != nil {
+			fatal("failed to generate invitation signing key", "error", err)
+		}
+		slog.Warn("INVITATION_SIGNING_KEY is not set, using a temporary key; invite links will stop working after a restart")
+	}
+
+	signer, err := invite.NewSigner(key)
+	if err != nil {
+		fatal("failed to initialize invite signer", "error", err)
+	}
+	return signer
+}
+
+// chainServices are the services that need a chain, and the client and
+// Diamond they use. All are nil without one.
+type chainServices struct {
+	relayer   ports.RelayerService
+	subsidies ports.SubsidyService
+	anchors   ports.AnchorService
+	health    ports.HealthCheck
+	client    chain.Client
+	diamond   common.Address
+}
+
+// initChain connects to the configured chain and starts the relayer that
+// sends subsidized calls for users, the job that keeps subsidies in step with
+// the chain, the job that anchors new records and the one that mints approved
+// proofs. Without a chain the routes of these services answer 503.
+func initChain(cfg config.ChainConfig, db *mongo.Database, acknowledgementService ports.AcknowledgementService, notificationService ports.NotificationService, screener ports.ContentScreener, siteURL string) chainServices {
+	if cfg.Client == config.ChainNone {
+		return chainServices{}
+	}
+
+	var operator *ecdsa.PrivateKey
+	var err error
+	if cfg.OperatorKey != "" {
+		operator, err = crypto.HexToECDSA(strings.TrimPrefix(cfg.OperatorKey, "0x"))
+	} else {
+		operator, err = crypto.GenerateKey()
+	}
+	if err != nil {
+		fatal("failed to load chain operator key", "error", err)
+	}
+	operatorAddress := crypto.PubkeyToAddress(operator.PublicKey)
+
+	var client chain.Client
+	chainID := int64(cfg.ChainID)
+	diamond := common.HexToAddress(cfg.DiamondAddress)
+	// deployed is set when the simulated chain runs the real contracts, whose
+	// operator then has to be switched on before it can relay
+	deployed := false
+	if cfg.Client == config.ChainSimulated {
+		// The operator owns the simulated Diamond, so it can also set subsidies
+		sim, err := chain.NewSimulatedDiamond(chain.DefaultArtifactsDir, operatorAddress, operatorAddress)
+		if err != nil {
+			slog.Warn("simulated chain runs without contracts, compile them with hardhat to deploy the Diamond", "error", err)
+			sim = chain.NewSimulated(operatorAddress)
+		} else {
+			deployed = true
+		}
+		sim.Start(time.Duration(cfg.SimulatedBlockTime))
+		client = sim.Client()
+		chainID = chain.SimulatedChainID
+		if deployed || cfg.DiamondAddress == "" {
+			diamond = chain.SimulatedDiamondAddress
+		}
+		slog.Warn("using a simulated chain, relayed transactions are not sent to any network", "operator", operatorAddress.Hex())
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		client, err = chain.Dial(ctx, cfg.RPCURL)
+		if err != nil {
+			fatal("failed to connect to chain node", "error", err)
+		}
+		// Requests signed for one chain must never be sent to another
+		nodeChainID, err := client.ChainID(ctx)
+		if err != nil {
+			fatal("failed to read chain ID from node", "error", err)
+		}
+		if nodeChainID.Int64() != chainID {
+			fatal("chain node is on a different chain than configured", "configured", chainID, "node", nodeChainID.Int64())
+		}
+	}
+
+	policy := domain.RelayPolicy{
+		MaxFeePerGas: new(big.Int).Mul(big.NewInt(int64(cfg.Relayer.MaxFeeGwei)), big.NewInt(params.GWei)),
+		MaxGasLimit:  uint64(cfg.Relayer.MaxGasLimit),
+		StuckAfter:   time.Duration(cfg.Relayer.StuckAfter),
+		MaxAttempts:  cfg.Relayer.MaxAttempts,
+	}
+	txRepo := mongodb.NewRelayedTransactionRepository(db)
+	budgetRepo := mongodb.NewOperatorBudgetRepository(db)
+	relayer := services.NewRelayerService(txRepo, budgetRepo, mongodb.NewExpressionRepository(db), acknowledgementService, screener, client, operator, chainID, diamond, policy)
+	go relayer.Run(context.Background(), time.Duration(cfg.Relayer.PollInterval))
+	if deployed {
+		if data, err := chain.PackSetOperatorStatus(operatorAddress, true); err == nil {
+			if _, err := relayer.SubmitOperatorCall(context.Background(), domain.RelaySetOperatorStatus, data); err != nil {
+				slog.Error("failed to activate the operator on the simulated chain", "error", err)
+			}
+		}
+	}
+
+	subsidies := services.NewSubsidyService(
+		mongodb.NewUserRepository(db),
+		mongodb.NewSubsidyChangeRepository(db),
+		budgetRepo,
+		mongodb.NewChainCursorRepository(db),
+		txRepo,
+		relayer,
+		client,
+		operatorAddress,
+		diamond,
+		uint64(cfg.Subsidies.StartBlock),
+		cfg.Subsidies.BatchSize,
+	)
+	go subsidies.Run(context.Background(), time.Duration(cfg.Subsidies.SyncInterval))
+
+	anchors := services.NewAnchorService(
+		mongodb.NewAnchorRepository(db),
+		mongodb.NewExpressionRepository(db),
+		mongodb.NewAcknowledgementRepository(db),
+		txRepo,
+		relayer,
+		chainID,
+		diamond,
+	)
+	go anchors.Run(context.Background(), time.Duration(cfg.AnchorInterval))
+
+	// Mints follow their relayed transactions, so they are checked as often
+	mints := services.NewProofMintService(
+		mongodb.NewExpressionAcknowledgementRepository(db),
+		mongodb.NewProofNFTRepository(db),
+		mongodb.NewExpressionRepository(db),
+		mongodb.NewAcknowledgementRepository(db),
+		mongodb.NewUserRepository(db),
+		txRepo,
+		relayer,
+		notificationService,
+		client,
+		diamond,
+		siteURL,
+	)
+	go mints.Run(context.Background(), time.Duration(cfg.Relayer.PollInterval))
+
+	return chainServices{
+		relayer:   relayer,
+		subsidies: subsidies,
+		anchors:   anchors,
+		health:    chain.NewHealthCheck(client),
+		client:    client,
+		diamond:   diamond,
+	}
+}
+
+// initContentScreener builds the screening chain run on new content: the
+// keyword lists first, then the webhook if one is configured
+func initContentScreener(cfg config.ScreeningConfig) ports.ContentScreener {
+	keywordScreener, err := screening.LoadKeywordScreener(cfg.KeywordDir)
+	if err != nil {
+		fatal("failed to load screening keyword lists", "error", err)
+	}
+	screeners := []ports.ContentScreener{keywordScreener}
+
+	if cfg.WebhookURL != "" {
+		screeners = append(screeners, screening.NewWebhookScreener(cfg.WebhookURL, cfg.WebhookSecret, time.Duration(cfg.WebhookTimeout)))
+	}
+
+	return screening.NewChain(screeners...)
 }
 
 func getProjectRoot() string {
@@ -69,19 +340,56 @@ func getProjectRoot() string {
 	return filepath.Join(filepath.Dir(filename), "../..")
 }
 
-func setupHandlers(app *fiber.App) *handlers.Handlers {
+func setupHandlers(app *fiber.App, cfg *config.Config) *handlers.Handlers {
 	// Setup MongoDB connection
-	db := mongodb.Connect()
-	mailgunClient := getMailgunClient()
+	db, err := mongodb.Connect(cfg.Mongo.URI, cfg.Mongo.Database)
+	if err != nil {
+		fatal("failed to connect to MongoDB", "error", err)
+	}
 
-	// Initialize R2 storage
-	expressionsR2Storage, err := initExpressionsR2Storage()
+	// Initialize media storage
+	mediaStorage, err := initStorage(app, cfg.Storage)
 	if err != nil {
-		log.Fatalf("Failed to initialize expressions R2 storage: %v", err)
+		fatal("failed to initialize media storage", "error", err)
 	}
 
+	// One screening chain serves every service that takes new content
+	screener := initContentScreener(cfg.Screening)
+
 	// Initialize services
-	userService, authService, expressionService, acknowledgementService, proofNFTService, feedService, newsletterService, webAuthnService, sessionService, statsService := initServices(db, mailgunClient, expressionsR2Storage)
+	userService, authService, expressionService, acknowledgementService, proofNFTService, pairingService, invitationService, replyService, feedService, newsletterService, webAuthnService, sessionService, statsService, notificationService, apiTokenService, moderationService, rateLimitService := initServices(cfg, db, initMailer(cfg.Mailer), mediaStorage, screener)
+
+	// Pair acknowledgements recorded before pairings existed, or whose sync failed
+	go func() {
+		if err := pairingService.Backfill(context.Background()); err != nil {
+			slog.Error("failed to backfill acknowledgement pairings", "error", err)
+		}
+	}()
+
+	healthChecks := []ports.HealthCheck{mongodb.NewHealthCheck(db), mediaStorage}
+	chainServices := initChain(cfg.Chain, db, acknowledgementService, notificationService, screener, cfg.SiteURL())
+	if chainServices.health != nil {
+		healthChecks = append(healthChecks, chainServices.health)
+	}
+
+	certificateService := services.NewCertificateService(
+		mongodb.NewProofNFTRepository(db),
+		mongodb.NewProofRequestRepository(db),
+		mongodb.NewExpressionRepository(db),
+		mongodb.NewUserRepository(db),
+		cfg.SiteURL(),
+	)
+
+	verificationService := services.NewVerificationService(
+		mongodb.NewProofNFTRepository(db),
+		mongodb.NewProofRequestRepository(db),
+		mongodb.NewExpressionRepository(db),
+		mongodb.NewAcknowledgementRepository(db),
+		mongodb.NewUserRepository(db),
+		chainServices.anchors,
+		chainServices.client,
+		chainServices.diamond,
+	)
 
 	// Initialize handlers
 	handlers := handlers.NewHandlers(
@@ -90,15 +398,30 @@ func setupHandlers(app *fiber.App) *handlers.Handlers {
 		expressionService,
 		acknowledgementService,
 		proofNFTService,
+		pairingService,
 		feedService,
 		statsService,
 		webAuthnService,
 		sessionService,
 		newsletterService,
+		notificationService,
+		apiTokenService,
+		moderationService,
+		chainServices.relayer,
+		chainServices.subsidies,
+		chainServices.anchors,
+		initCredentials(cfg, db),
+		certificateService,
+		verificationService,
+		invitationService,
+		replyService,
+		cfg.SiteURL(),
+		rateLimitService,
+		healthChecks,
 	)
 
 	// Setup routes with user service for feed handler
-	routes.SetupRoutes(app, handlers)
+	routes.SetupRoutes(app, handlers, cfg)
 
 	return handlers
 }
@@ -117,40 +440,90 @@ func initTemplateEngine() *html.Engine {
 	return engine
 }
 
-func initExpressionsR2Storage() (*storage.R2Storage, error) {
-	expressionsConfig, err := config.GetR2Config("EXPRESSIONS")
-	if err != nil {
-		log.Fatalf("Failed to get expressions R2 config: %v", err)
+// mediaStorage is a storage backend that can report whether it is reachable
+type mediaStorage interface {
+	storage.Storage
+	ports.HealthCheck
+}
+
+// initStorage opens the configured media storage backend. Files in local
+// storage are served by the app itself.
+func initStorage(app *fiber.App, cfg config.StorageConfig) (mediaStorage, error) {
+	if cfg.Backend == config.StorageLocal {
+		local, err := storage.NewLocalStorage(cfg.LocalDir)
+		if err != nil {
+			return nil, err
+		}
+		app.Static(storage.LocalURLPrefix, local.Dir())
+		return local, nil
 	}
-	expressionsR2Storage, err := storage.NewR2Storage(
-		expressionsConfig.S3AccessKeyID,
-		expressionsConfig.S3SecretKey,
-		expressionsConfig.AccountID,
-		expressionsConfig.Bucket,
+
+	return storage.NewR2Storage(
+		cfg.R2.AccessKeyID,
+		cfg.R2.SecretKey,
+		cfg.R2.AccountID,
+		cfg.R2.Bucket,
 	)
-	if err != nil {
-		log.Fatalf("Failed to initialize expressions R2 storage: %v", err)
-	}
+}
 
-	return expressionsR2Storage, nil
+// initMailer picks how email is sent
+func initMailer(cfg config.MailerConfig) ports.Mailer {
+	if cfg.Backend == config.MailerLog {
+		return mailer.NewLogMailer()
+	}
+	return mailer.NewMailgun(cfg.SenderDomain, cfg.MailgunAPIKey, cfg.MailgunAPIBase, cfg.SenderAddress)
 }
 
 func main() {
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Error loading .env file: %v", err)
-	}
-
-	// Load country data
-	if err := domain.LoadCountries("web/static/data/countries.json"); err != nil {
-		log.Fatalf("Failed to load country data: %v", err)
-	}
+	envErr := godotenv.Load()
 
 	// Get project root directory
 	projectRoot := getProjectRoot()
 
 	loadEnvironment(projectRoot)
 
+	// Settings come from defaults, the config file, the environment (including
+	// .env) and flags, in increasing priority
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "JSON configuration file (CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	overrides := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile, overrides)
+	if err != nil {
+		exitInvalidConfig(err)
+	}
+	if *printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			exitInvalidConfig(err)
+		}
+		if err := cfg.Validate(); err != nil {
+			exitInvalidConfig(err)
+		}
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		exitInvalidConfig(err)
+	}
+
+	// Switch to structured logging once the log settings are known
+	logging.Setup(cfg.Log.Level, cfg.Log.Format)
+	if envErr != nil {
+		slog.Debug("no .env file in working directory", "error", envErr)
+	}
+
+	// Tracing comes up before anything that opens spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName)
+	if err != nil {
+		fatal("failed to set up tracing", "error", err)
+	}
+
+	// Load country data
+	if err := domain.LoadCountries("web/static/data/countries.json"); err != nil {
+		fatal("failed to load country data", "error", err)
+	}
+
 	// Setup template engine
 	engine := initTemplateEngine()
 	engine.Reload(true)
@@ -163,30 +536,28 @@ func main() {
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		Views: engine,
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			log.Printf("Error handling request: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
+		// Rate limits key on the client IP, so behind a reverse proxy this must
+		// name the header the proxy sets
+		ProxyHeader: cfg.ProxyHeader,
+		// Errors returned by handlers are rendered as RFC 7807 problem details
+		ErrorHandler: handlers.ErrorHandler,
 	})
 
 	// Setup static files
 	app.Static("/static", filepath.Join(projectRoot, "web/static"))
 
 	// Setup handlers and routes
-	setupHandlers(app)
+	setupHandlers(app, cfg)
 
 	// Start server in a goroutine
 	go func() {
-		port := getPort()
-		if err := app.Listen(port); err != nil {
-			log.Printf("Server error: %v", err)
+		if err := app.Listen(cfg.Addr()); err != nil {
+			slog.Error("server error", "error", err)
 		}
 	}()
 
 	// Use graceful shutdown
-	gracefulShutdown(app)
+	gracefulShutdown(app, shutdownTracing)
 }
 
 func loadEnvironment(projectRoot string) {
@@ -197,41 +568,39 @@ func loadEnvironment(projectRoot string) {
 
 		// Load environment variables
 		if err := godotenv.Load(filepath.Join(projectRoot, ".env")); err != nil {
-			log.Printf("Warning: .env file not found")
+			slog.Warn(".env file not found")
 		}
 	}
 }
 
-func getPort() string {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = ":3000"
-	} else {
-		port = ":" + port
+// exitInvalidConfig lists every configuration problem on stderr and exits.
+// It runs before logging is set up, so it does not use slog.
+func exitInvalidConfig(err error) {
+	fmt.Fprintln(os.Stderr, "invalid configuration:")
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Fprintln(os.Stderr, "  -", line)
 	}
-	return port
+	os.Exit(2)
 }
 
-func startServer(app *fiber.App) {
-	port := getPort()
-	log.Fatal(app.Listen(port))
-}
-
-func getMailgunClient() *mailgun.MailgunImpl {
-	var domain string = os.Getenv("EMAIL_SENDER_DOMAIN")
-	var key string = os.Getenv("MAILGUN_APIKEY")
-	return mailgun.NewMailgun(domain, key)
+// fatal logs an error and exits, for failures the server cannot start without
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
 // add a graceful shutdown and use it in the main function
-func gracefulShutdown(app *fiber.App) {
+func gracefulShutdown(app *fiber.App, shutdownTracing func(context.Context) error) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := app.ShutdownWithContext(ctx); err != nil {
-		log.Fatalf("Failed to shutdown server: %v", err)
+		fatal("failed to shut down server", "error", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
 	}
 }
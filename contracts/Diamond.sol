@@ -2,7 +2,6 @@
 pragma solidity ^0.8.20;
 
 import "./interfaces/IDiamondCut.sol";
-import "./interfaces/IDiamondLoupe.sol";
 import "./libraries/LibDiamond.sol";
 
 contract Diamond {    
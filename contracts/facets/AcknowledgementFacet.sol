@@ -4,6 +4,7 @@ pragma solidity ^0.8.20;
 import "../libraries/LibDiamond.sol";
 import "../libraries/LibStorage.sol";
 import "../libraries/LibPermissions.sol";
+import "../libraries/LibRelay.sol";
 
 contract AcknowledgementFacet {
     event AcknowledgementCreated(
@@ -23,6 +24,24 @@ contract AcknowledgementFacet {
         string imageContent
     );
 
+    /// An acknowledge call signed by `from` for an operator to send
+    struct AcknowledgeRequest {
+        address from;
+        uint256 expressionId;
+        address creator;
+        string message;
+        string textContent;
+        string audioContent;
+        string videoContent;
+        string imageContent;
+        uint256 nonce;
+        uint256 deadline;
+    }
+
+    bytes32 constant ACKNOWLEDGE_TYPEHASH = keccak256(
+        "acknowledge(address from,uint256 expressionId,address creator,string message,string textContent,string audioContent,string videoContent,string imageContent,uint256 nonce,uint256 deadline)"
+    );
+
     function acknowledge(
         uint256 _expressionId,
         address _creator,
@@ -32,34 +51,82 @@ contract AcknowledgementFacet {
         string memory _videoContent,
         string memory _imageContent
     ) external payable {
-        LibStorage.ExpressionStorage storage es = LibStorage.expressionStorage();
         LibStorage.GasCostStorage storage gs = LibStorage.gasCostStorage();
 
-        require(_expressionId < es.expressionCount, "Expression does not exist");
-        LibStorage.Expression storage expression = es.expressions[_expressionId];
-        require(expression.creator == _creator, "Invalid creator address");
-
         // Check if user needs to pay gas
-        if (!LibPermissions.isSubsidized(msg.sender, LibPermissions.ACKNOWLEDGEMENT_PERMISSION)) {
+        if (!LibPermissions.isSubsidized(msg.sender, msg.sender, LibPermissions.ACKNOWLEDGEMENT_PERMISSION)) {
             require(msg.value >= gs.acknowledgementGasCost, "Insufficient gas payment");
         }
 
-        LibStorage.Acknowledgement storage ack = expression.acknowledgments[msg.sender];
-        ack.acknowledger = msg.sender;
-        ack.timestamp = block.timestamp;
-        ack.message = _message;
-        ack.content = LibStorage.MediaContent({
+        _acknowledge(msg.sender, _expressionId, _creator, _message, LibStorage.MediaContent({
             textContent: _textContent,
             audioContent: _audioContent,
             videoContent: _videoContent,
             imageContent: _imageContent
-        });
+        }));
+    }
+
+    /// Records an acknowledgement by the signer of req. Only an operator
+    /// subsidizing the signer's acknowledgements may send it.
+    function acknowledgeFor(AcknowledgeRequest calldata req, bytes calldata signature) external {
+        require(
+            LibPermissions.isSubsidized(msg.sender, req.from, LibPermissions.ACKNOWLEDGEMENT_PERMISSION),
+            "Not subsidized"
+        );
+
+        // Encoded in two halves to stay within the stack; the concatenation
+        // is the same as encoding all fields at once
+        bytes32 structHash = keccak256(bytes.concat(
+            abi.encode(
+                ACKNOWLEDGE_TYPEHASH,
+                req.from,
+                req.expressionId,
+                req.creator,
+                keccak256(bytes(req.message))
+            ),
+            abi.encode(
+                keccak256(bytes(req.textContent)),
+                keccak256(bytes(req.audioContent)),
+                keccak256(bytes(req.videoContent)),
+                keccak256(bytes(req.imageContent)),
+                req.nonce,
+                req.deadline
+            )
+        ));
+        LibRelay.useSignature(req.from, structHash, req.nonce, req.deadline, signature);
+
+        _acknowledge(req.from, req.expressionId, req.creator, req.message, LibStorage.MediaContent({
+            textContent: req.textContent,
+            audioContent: req.audioContent,
+            videoContent: req.videoContent,
+            imageContent: req.imageContent
+        }));
+    }
+
+    function _acknowledge(
+        address _acknowledger,
+        uint256 _expressionId,
+        address _creator,
+        string memory _message,
+        LibStorage.MediaContent memory _content
+    ) private {
+        LibStorage.ExpressionStorage storage es = LibStorage.expressionStorage();
+
+        require(_expressionId < es.expressionCount, "Expression does not exist");
+        LibStorage.Expression storage expression = es.expressions[_expressionId];
+        require(expression.creator == _creator, "Invalid creator address");
+
+        LibStorage.Acknowledgement storage ack = expression.acknowledgments[_acknowledger];
+        ack.acknowledger = _acknowledger;
+        ack.timestamp = block.timestamp;
+        ack.message = _message;
+        ack.content = _content;
 
-        expression.acknowledgers.push(msg.sender);
+        expression.acknowledgers.push(_acknowledger);
 
         emit AcknowledgementCreated(
             _expressionId,
-            msg.sender,
+            _acknowledger,
             _creator,
             _message,
             block.timestamp
@@ -67,11 +134,11 @@ contract AcknowledgementFacet {
 
         emit MediaContentAdded(
             _expressionId,
-            msg.sender,
-            _textContent,
-            _audioContent,
-            _videoContent,
-            _imageContent
+            _acknowledger,
+            _content.textContent,
+            _content.audioContent,
+            _content.videoContent,
+            _content.imageContent
         );
     }
 
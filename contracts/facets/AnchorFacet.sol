@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "../libraries/LibDiamond.sol";
+import "../libraries/LibPermissions.sol";
+import "../libraries/LibAnchor.sol";
+
+/// Commits Merkle roots over off-chain expressions and acknowledgements, so
+/// anyone holding an inclusion proof can check a record against the chain.
+/// Leaves are keccak256(keccak256(abi.encode(kind, id, contentHash, creator,
+/// timestamp))) and pairs are hashed in sorted order, as in OpenZeppelin's
+/// MerkleProof.
+contract AnchorFacet {
+    event RootAnchored(bytes32 indexed root, address operator, uint256 leafCount);
+
+    function anchorRoot(bytes32 root, uint256 leafCount) external {
+        require(
+            msg.sender == LibDiamond.contractOwner() ||
+                LibPermissions.permissionStorage().activeOperators[msg.sender],
+            "Not an operator"
+        );
+        require(root != bytes32(0) && leafCount > 0, "Empty batch");
+
+        LibAnchor.AnchorStorage storage s = LibAnchor.anchorStorage();
+        require(s.anchors[root].timestamp == 0, "Root already anchored");
+
+        s.anchors[root] = LibAnchor.Anchor({
+            operator: msg.sender,
+            leafCount: uint64(leafCount),
+            timestamp: uint64(block.timestamp)
+        });
+        s.anchorCount++;
+        emit RootAnchored(root, msg.sender, leafCount);
+    }
+
+    /// Returns when root was anchored, or 0 if it never was
+    function anchoredAt(bytes32 root) external view returns (uint256) {
+        return LibAnchor.anchorStorage().anchors[root].timestamp;
+    }
+
+    function verifyInclusion(bytes32 root, bytes32 leaf, bytes32[] calldata proof) external view returns (bool) {
+        if (LibAnchor.anchorStorage().anchors[root].timestamp == 0) {
+            return false;
+        }
+        bytes32 hash = leaf;
+        for (uint i = 0; i < proof.length; i++) {
+            bytes32 sibling = proof[i];
+            hash = hash < sibling
+                ? keccak256(abi.encodePacked(hash, sibling))
+                : keccak256(abi.encodePacked(sibling, hash));
+        }
+        return hash == root;
+    }
+}
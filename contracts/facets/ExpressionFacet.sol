@@ -4,6 +4,7 @@ pragma solidity ^0.8.20;
 import "../libraries/LibDiamond.sol";
 import "../libraries/LibStorage.sol";
 import "../libraries/LibPermissions.sol";
+import "../libraries/LibRelay.sol";
 
 contract ExpressionFacet {
     event ExpressionCreated(
@@ -16,24 +17,76 @@ contract ExpressionFacet {
         uint256 timestamp
     );
 
+    /// A createExpression call signed by `from` for an operator to send
+    struct CreateExpressionRequest {
+        address from;
+        string textContent;
+        string audioContent;
+        string videoContent;
+        string imageContent;
+        uint256 nonce;
+        uint256 deadline;
+    }
+
+    bytes32 constant CREATE_EXPRESSION_TYPEHASH = keccak256(
+        "createExpression(address from,string textContent,string audioContent,string videoContent,string imageContent,uint256 nonce,uint256 deadline)"
+    );
+
     function createExpression(
         string memory _textContent,
         string memory _audioContent,
         string memory _videoContent,
         string memory _imageContent
     ) external payable returns (uint256) {
-        LibStorage.ExpressionStorage storage es = LibStorage.expressionStorage();
         LibStorage.GasCostStorage storage gs = LibStorage.gasCostStorage();
         
         // Check if user needs to pay gas
-        if (!LibPermissions.isSubsidized(msg.sender, LibPermissions.EXPRESSION_PERMISSION)) {
+        if (!LibPermissions.isSubsidized(msg.sender, msg.sender, LibPermissions.EXPRESSION_PERMISSION)) {
             require(msg.value >= gs.expressionGasCost, "Insufficient gas payment");
         }
 
+        return _createExpression(msg.sender, _textContent, _audioContent, _videoContent, _imageContent);
+    }
+
+    /// Records an expression for the signer of req. Only an operator
+    /// subsidizing the signer's expressions may send it.
+    function createExpressionFor(
+        CreateExpressionRequest calldata req,
+        bytes calldata signature
+    ) external returns (uint256) {
+        require(
+            LibPermissions.isSubsidized(msg.sender, req.from, LibPermissions.EXPRESSION_PERMISSION),
+            "Not subsidized"
+        );
+
+        bytes32 structHash = keccak256(abi.encode(
+            CREATE_EXPRESSION_TYPEHASH,
+            req.from,
+            keccak256(bytes(req.textContent)),
+            keccak256(bytes(req.audioContent)),
+            keccak256(bytes(req.videoContent)),
+            keccak256(bytes(req.imageContent)),
+            req.nonce,
+            req.deadline
+        ));
+        LibRelay.useSignature(req.from, structHash, req.nonce, req.deadline, signature);
+
+        return _createExpression(req.from, req.textContent, req.audioContent, req.videoContent, req.imageContent);
+    }
+
+    function _createExpression(
+        address _creator,
+        string memory _textContent,
+        string memory _audioContent,
+        string memory _videoContent,
+        string memory _imageContent
+    ) private returns (uint256) {
+        LibStorage.ExpressionStorage storage es = LibStorage.expressionStorage();
+
         uint256 expressionId = es.expressionCount++;
         LibStorage.Expression storage expression = es.expressions[expressionId];
         
-        expression.creator = msg.sender;
+        expression.creator = _creator;
         expression.timestamp = block.timestamp;
         expression.content = LibStorage.MediaContent({
             textContent: _textContent,
@@ -44,7 +97,7 @@ contract ExpressionFacet {
 
         emit ExpressionCreated(
             expressionId,
-            msg.sender,
+            _creator,
             _textContent,
             _audioContent,
             _videoContent,
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+library LibAnchor {
+    bytes32 constant STORAGE_POSITION = keccak256("pop.v1.anchor.storage");
+
+    struct Anchor {
+        address operator;
+        uint64 leafCount;
+        uint64 timestamp;
+    }
+
+    struct AnchorStorage {
+        // Merkle root => when and by whom it was anchored
+        mapping(bytes32 => Anchor) anchors;
+        uint256 anchorCount;
+    }
+
+    function anchorStorage() internal pure returns (AnchorStorage storage s) {
+        bytes32 position = STORAGE_POSITION;
+        assembly {
+            s.slot := position
+        }
+    }
+}
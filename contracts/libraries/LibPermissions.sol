@@ -22,9 +22,10 @@ library LibPermissions {
         }
     }
     
-    function isSubsidized(address user, uint8 operation) internal view returns (bool) {
+    // isSubsidized reports whether operator, if active, pays for user's operation
+    function isSubsidized(address operator, address user, uint8 operation) internal view returns (bool) {
         PermissionStorage storage ps = permissionStorage();
-        return ps.activeOperators[msg.sender] && ps.operatorSubsidies[msg.sender][user][operation];
+        return ps.activeOperators[operator] && ps.operatorSubsidies[operator][user][operation];
     }
     
     function setOperatorSubsidy(
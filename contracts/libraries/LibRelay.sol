@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "@openzeppelin/contracts/utils/cryptography/ECDSA.sol";
+
+/// Checks the EIP-712 requests users sign for an operator to send on their
+/// behalf. The domain and types match internal/core/chain/typed_data.go, and
+/// the Diamond is the verifying contract, as facets run in its context.
+library LibRelay {
+    bytes32 constant STORAGE_POSITION = keccak256("pop.v1.relay.storage");
+
+    bytes32 constant DOMAIN_TYPEHASH =
+        keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)");
+    bytes32 constant NAME_HASH = keccak256("Proof of Peacemaking");
+    bytes32 constant VERSION_HASH = keccak256("1");
+
+    struct RelayStorage {
+        // Signer => Nonce => Used. Nonces need not be used in order on-chain;
+        // the relayer orders them, a used one is never accepted twice.
+        mapping(address => mapping(uint256 => bool)) usedNonces;
+    }
+
+    function relayStorage() internal pure returns (RelayStorage storage rs) {
+        bytes32 position = STORAGE_POSITION;
+        assembly {
+            rs.slot := position
+        }
+    }
+
+    function domainSeparator() internal view returns (bytes32) {
+        return keccak256(abi.encode(DOMAIN_TYPEHASH, NAME_HASH, VERSION_HASH, block.chainid, address(this)));
+    }
+
+    /// Checks that signer signed the request hashing to structHash before its
+    /// deadline, and spends its nonce. A deadline of 0 means none.
+    function useSignature(
+        address signer,
+        bytes32 structHash,
+        uint256 nonce,
+        uint256 deadline,
+        bytes memory signature
+    ) internal {
+        require(deadline == 0 || block.timestamp <= deadline, "Relay: request expired");
+        RelayStorage storage rs = relayStorage();
+        require(!rs.usedNonces[signer][nonce], "Relay: nonce already used");
+
+        bytes32 digest = ECDSA.toTypedDataHash(domainSeparator(), structHash);
+        require(ECDSA.recover(digest, signature) == signer, "Relay: invalid signature");
+        rs.usedNonces[signer][nonce] = true;
+    }
+
+    function nonceUsed(address signer, uint256 nonce) internal view returns (bool) {
+        return relayStorage().usedNonces[signer][nonce];
+    }
+}
@@ -25,6 +25,9 @@ library LibStorage {
         MediaContent content;
         uint256 timestamp;
         string ipfsHash;
+        address[] acknowledgers;
+        // acknowledger => Acknowledgement
+        mapping(address => Acknowledgement) acknowledgments;
     }
 
     struct Acknowledgement {
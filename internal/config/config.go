@@ -0,0 +1,197 @@
+// Package config holds the server's typed configuration. Values come from
+// built-in defaults, then an optional JSON file, then environment variables,
+// then command-line flags, each overriding the last. Load reads them and
+// Validate reports every problem at once, naming the variable to fix.
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is everything the server reads at startup. Each setting names its
+// environment variable; its flag is the same name in lower kebab case, so
+// MONGODB_URI is also --mongodb-uri.
+type Config struct {
+	Env         string `json:"env" env:"ENV" default:"development" usage:"deployment environment: development, test, staging or production"`
+	Port        int    `json:"port" env:"PORT" default:"3000" usage:"HTTP port to listen on"`
+	ProxyHeader string `json:"proxyHeader" env:"PROXY_HEADER" usage:"header carrying the client IP behind a reverse proxy, e.g. X-Forwarded-For"`
+	PublicURL   string `json:"publicUrl" env:"PUBLIC_URL" usage:"the site's public URL used in shared links, defaults to https://RELYING_PARTY"`
+
+	Log        LogConfig        `json:"log"`
+	Tracing    TracingConfig    `json:"tracing"`
+	Mongo      MongoConfig      `json:"mongo"`
+	Storage    StorageConfig    `json:"storage"`
+	Mailer     MailerConfig     `json:"mailer"`
+	Chain      ChainConfig      `json:"chain"`
+	WebAuthn   WebAuthnConfig   `json:"webauthn"`
+	Credential CredentialConfig `json:"credential"`
+	Invitation InvitationConfig `json:"invitation"`
+	Security   SecurityConfig   `json:"security"`
+	RateLimit  RateLimitConfig  `json:"rateLimit"`
+	Screening  ScreeningConfig  `json:"screening"`
+}
+
+type LogConfig struct {
+	Level  string `json:"level" env:"LOG_LEVEL" default:"info" usage:"minimum log level: debug, info, warn or error"`
+	Format string `json:"format" env:"LOG_FORMAT" default:"json" usage:"log output format: json or text"`
+}
+
+type TracingConfig struct {
+	Exporter    string `json:"exporter" env:"TRACING_EXPORTER" default:"none" usage:"trace exporter: none, stdout or otlp"`
+	ServiceName string `json:"serviceName" env:"TRACING_SERVICE_NAME" default:"proofofpeacemaking" usage:"service name reported on spans"`
+}
+
+type MongoConfig struct {
+	URI      string `json:"uri" env:"MONGODB_URI" secret:"true" usage:"MongoDB connection string"`
+	Database string `json:"database" env:"MONGODB_DATABASE" default:"proof-of-peacemaking" usage:"MongoDB database name"`
+}
+
+// Storage backends for expression media
+const (
+	StorageR2    = "r2"
+	StorageLocal = "local"
+)
+
+type StorageConfig struct {
+	Backend  string   `json:"backend" env:"STORAGE_BACKEND" default:"r2" usage:"where expression media is stored: r2, or local for development"`
+	LocalDir string   `json:"localDir" env:"STORAGE_LOCAL_DIR" default:"data/media" usage:"directory used by the local storage backend"`
+	R2       R2Config `json:"r2"`
+}
+
+// R2Config holds the credentials for the Cloudflare R2 bucket expression media
+// is kept in
+type R2Config struct {
+	// S3-compatible credentials
+	AccessKeyID string `json:"accessKeyId" env:"R2_EXPRESSIONS_ACCESS_KEY_ID" usage:"R2 access key ID"`
+	SecretKey   string `json:"secretKey" env:"R2_EXPRESSIONS_SECRET_KEY" secret:"true" usage:"R2 secret access key"`
+	// Cloudflare specific
+	AccountID string `json:"accountId" env:"R2_EXPRESSIONS_ACCOUNT_ID" usage:"Cloudflare account ID"`
+	Bucket    string `json:"bucket" env:"R2_EXPRESSIONS_BUCKET" usage:"R2 bucket name"`
+	APIToken  string `json:"apiToken" env:"R2_EXPRESSIONS_API_TOKEN" secret:"true" usage:"Cloudflare API token, if needed for additional operations"`
+}
+
+// Mailer backends
+const (
+	MailerMailgun = "mailgun"
+	MailerLog     = "log"
+)
+
+type MailerConfig struct {
+	Backend          string `json:"backend" env:"MAILER" default:"mailgun" usage:"how email is sent: mailgun, or log to only write it to the log"`
+	SenderDomain     string `json:"senderDomain" env:"EMAIL_SENDER_DOMAIN" usage:"Mailgun sending domain"`
+	MailgunAPIKey    string `json:"mailgunApiKey" env:"MAILGUN_APIKEY" secret:"true" usage:"Mailgun API key"`
+	MailgunAPIBase   string `json:"mailgunApiBase" env:"MAILGUN_API_BASE" default:"https://api.eu.mailgun.net/v3" usage:"Mailgun API base URL"`
+	SenderAddress    string `json:"senderAddress" env:"EMAIL_SENDER_ADDRESS" usage:"From address of outgoing email"`
+	ContactRecipient string `json:"contactRecipient" env:"CONTACT_EMAIL_RECIPIENT_ADDRESS" usage:"address newsletter sign-ups are reported to"`
+}
+
+// Chain clients
+const (
+	ChainNone      = "none"
+	ChainRPC       = "rpc"
+	ChainSimulated = "simulated"
+)
+
+type ChainConfig struct {
+	Client             string        `json:"client" env:"CHAIN_CLIENT" default:"none" usage:"chain client: none, rpc to talk to a JSON-RPC node, or simulated for an in-process development chain"`
+	RPCURL             string        `json:"rpcUrl" env:"CHAIN_RPC_URL" secret:"true" usage:"JSON-RPC endpoint of the node"`
+	ChainID            int           `json:"chainId" env:"CHAIN_ID" usage:"EIP-155 chain ID"`
+	DiamondAddress     string        `json:"diamondAddress" env:"DIAMOND_ADDRESS" usage:"address of the Diamond proxy contract"`
+	OperatorKey        string        `json:"operatorKey" env:"CHAIN_OPERATOR_KEY" secret:"true" usage:"hex private key of the operator account that sends transactions"`
+	SimulatedBlockTime Duration      `json:"simulatedBlockTime" env:"CHAIN_SIMULATED_BLOCK_TIME" default:"2s" usage:"how often the simulated chain seals a block"`
+	Relayer            RelayerConfig `json:"relayer"`
+	Subsidies          SubsidyConfig `json:"subsidies"`
+	AnchorInterval     Duration      `json:"anchorInterval" env:"ANCHOR_INTERVAL" default:"1h" usage:"how often new expressions and acknowledgements are batched and their Merkle root anchored"`
+}
+
+// RelayerConfig bounds what the operator spends sending transactions for users
+type RelayerConfig struct {
+	MaxFeeGwei   int      `json:"maxFeeGwei" env:"RELAYER_MAX_FEE_GWEI" default:"100" usage:"most the relayer pays per gas, in gwei"`
+	MaxGasLimit  int      `json:"maxGasLimit" env:"RELAYER_MAX_GAS_LIMIT" default:"1000000" usage:"most gas one relayed call may use"`
+	StuckAfter   Duration `json:"stuckAfter" env:"RELAYER_STUCK_AFTER" default:"3m" usage:"how long a transaction may stay unmined before it is replaced with higher fees"`
+	MaxAttempts  int      `json:"maxAttempts" env:"RELAYER_MAX_ATTEMPTS" default:"5" usage:"most transactions sent for one request, counting replacements"`
+	PollInterval Duration `json:"pollInterval" env:"RELAYER_POLL_INTERVAL" default:"15s" usage:"how often relayed transactions are checked for receipts"`
+}
+
+// SubsidyConfig controls how subsidy changes reach the PermissionsFacet
+type SubsidyConfig struct {
+	StartBlock   int      `json:"startBlock" env:"SUBSIDY_START_BLOCK" usage:"block to start reading SubsidyStatusChanged events from on first run"`
+	BatchSize    int      `json:"batchSize" env:"SUBSIDY_BATCH_SIZE" default:"100" usage:"most subsidy changes sent in one setOperatorSubsidies call"`
+	SyncInterval Duration `json:"syncInterval" env:"SUBSIDY_SYNC_INTERVAL" default:"30s" usage:"how often subsidy changes are sent and events read"`
+}
+
+type WebAuthnConfig struct {
+	RelyingParty    string `json:"relyingParty" env:"RELYING_PARTY" usage:"passkey relying party ID, the site's host name"`
+	SignCountPolicy string `json:"signCountPolicy" env:"WEBAUTHN_SIGN_COUNT_POLICY" default:"warn" usage:"on a passkey sign counter regression: warn, require_second_factor or deactivate"`
+}
+
+type CredentialConfig struct {
+	IssuerKey  string `json:"issuerKey" env:"CREDENTIAL_ISSUER_KEY" secret:"true" usage:"hex 32-byte Ed25519 seed verifiable credentials are signed with; random on each start if unset outside production"`
+	IssuerHost string `json:"issuerHost" env:"CREDENTIAL_ISSUER_HOST" usage:"host of the issuer's did:web identifier, defaults to RELYING_PARTY"`
+}
+
+type InvitationConfig struct {
+	SigningKey string   `json:"signingKey" env:"INVITATION_SIGNING_KEY" secret:"true" usage:"hex 32-byte key invite links are signed with; random on each start if unset outside production"`
+	Lifetime   Duration `json:"lifetime" env:"INVITATION_LIFETIME" default:"336h" usage:"how long an invite link stays valid"`
+}
+
+type SecurityConfig struct {
+	AllowedOrigins        []string `json:"allowedOrigins" env:"ALLOWED_ORIGINS" usage:"other origins allowed to call the API with cookies, comma-separated"`
+	ContentSecurityPolicy string   `json:"contentSecurityPolicy" env:"CONTENT_SECURITY_POLICY" usage:"overrides the default Content-Security-Policy header"`
+	MetricsToken          string   `json:"metricsToken" env:"METRICS_TOKEN" secret:"true" usage:"bearer token required to scrape /metrics"`
+}
+
+// Rate limit stores
+const (
+	RateLimitMemory = "memory"
+	RateLimitMongo  = "mongo"
+)
+
+type RateLimitConfig struct {
+	Store string `json:"store" env:"RATE_LIMIT_STORE" default:"memory" usage:"rate limit counters: memory (single instance) or mongo (shared between instances)"`
+}
+
+type ScreeningConfig struct {
+	KeywordDir     string   `json:"keywordDir" env:"SCREENING_KEYWORD_DIR" default:"config/screening" usage:"directory of per-locale keyword lists"`
+	WebhookURL     string   `json:"webhookUrl" env:"SCREENING_WEBHOOK_URL" usage:"HTTP screening service, if any"`
+	WebhookSecret  string   `json:"webhookSecret" env:"SCREENING_WEBHOOK_SECRET" secret:"true" usage:"secret screening webhook requests are signed with"`
+	WebhookTimeout Duration `json:"webhookTimeout" env:"SCREENING_WEBHOOK_TIMEOUT" default:"5s" usage:"how long to wait for the screening webhook"`
+}
+
+// Duration is a time.Duration written as "5s" or "1m30s" in files and flags
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Development reports whether the server runs on a developer machine, which
+// turns off HSTS
+func (c *Config) Development() bool {
+	return c.Env == "development"
+}
+
+// SiteURL is the public URL links to the site are made from, without a
+// trailing slash
+func (c *Config) SiteURL() string {
+	if c.PublicURL != "" {
+		return strings.TrimSuffix(c.PublicURL, "/")
+	}
+	return "https://" + c.WebAuthn.RelyingParty
+}
+
+// Addr is the address to listen on
+func (c *Config) Addr() string {
+	return ":" + strconv.Itoa(c.Port)
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// redacted replaces secrets in printed configuration
+const redacted = "[REDACTED]"
+
+// setting is one leaf of Config with the tags that describe it
+type setting struct {
+	value  reflect.Value
+	env    string
+	def    string
+	usage  string
+	secret bool
+}
+
+// settings walks cfg and returns every field that has an env tag
+func settings(cfg *Config) []setting {
+	var out []setting
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			env := field.Tag.Get("env")
+			if env == "" {
+				if field.Type.Kind() == reflect.Struct {
+					walk(v.Field(i))
+				}
+				continue
+			}
+			out = append(out, setting{
+				value:  v.Field(i),
+				env:    env,
+				def:    field.Tag.Get("default"),
+				usage:  field.Tag.Get("usage"),
+				secret: field.Tag.Get("secret") == "true",
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem())
+	return out
+}
+
+// set parses raw into the setting's field
+func (s setting) set(raw string) error {
+	if u, ok := s.value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New("not a whole number")
+		}
+		s.value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("not true or false")
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.value.Type())
+	}
+	return nil
+}
+
+// flagName is the command-line flag for an environment variable
+func flagName(env string) string {
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+// Flags are the command-line overrides registered by RegisterFlags
+type Flags struct {
+	fs *flag.FlagSet
+}
+
+// RegisterFlags defines a flag on fs for every setting. Only flags given on
+// the command line override other sources.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	for _, s := range settings(&Config{}) {
+		fs.String(flagName(s.env), "", fmt.Sprintf("%s (%s)", s.usage, s.env))
+	}
+	return &Flags{fs: fs}
+}
+
+// Load builds the configuration from defaults, the JSON file at path (if
+// any), the environment and flags (if any). It fails only on values that
+// cannot be parsed; call Validate to check the result is usable.
+func Load(path string, flags *Flags) (*Config, error) {
+	cfg := &Config{}
+	all := settings(cfg)
+
+	var errs []error
+	for _, s := range all {
+		if s.def == "" {
+			continue
+		}
+		if err := s.set(s.def); err != nil {
+			errs = append(errs, fmt.Errorf("default for %s: %w", s.env, err))
+		}
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	// Empty variables, as left by .env templates, do not override the file
+	for _, s := range all {
+		raw, ok := os.LookupEnv(s.env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := s.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", s.env, raw, err))
+		}
+	}
+
+	if flags != nil {
+		byFlag := make(map[string]setting, len(all))
+		for _, s := range all {
+			byFlag[flagName(s.env)] = s
+		}
+		flags.fs.Visit(func(f *flag.Flag) {
+			s, ok := byFlag[f.Name]
+			if !ok {
+				return
+			}
+			if err := s.set(f.Value.String()); err != nil {
+				errs = append(errs, fmt.Errorf("--%s=%q: %w", f.Name, f.Value.String(), err))
+			}
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// Redacted returns a copy with every secret that is set replaced by a marker
+func (c *Config) Redacted() *Config {
+	out := *c
+	for _, s := range settings(&out) {
+		if s.secret && s.value.Kind() == reflect.String && s.value.String() != "" {
+			s.value.SetString(redacted)
+		}
+	}
+	return &out
+}
+
+// Print writes the configuration as JSON, in the file format Load reads, with
+// secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	data, err := json.MarshalIndent(c.Redacted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Validate checks the configuration is complete and consistent, returning one
+// error per problem so they can all be fixed in one go
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.oneOf("ENV", c.Env, "development", "test", "staging", "production")
+	if c.Port < 1 || c.Port > 65535 {
+		v.fail("PORT must be between 1 and 65535, got %d", c.Port)
+	}
+
+	v.oneOf("LOG_LEVEL", strings.ToLower(c.Log.Level), "debug", "info", "warn", "warning", "error")
+	v.oneOf("LOG_FORMAT", strings.ToLower(c.Log.Format), "json", "text")
+	v.oneOf("TRACING_EXPORTER", strings.ToLower(c.Tracing.Exporter), "none", "stdout", "otlp")
+
+	v.required("MONGODB_URI", c.Mongo.URI)
+	v.required("MONGODB_DATABASE", c.Mongo.Database)
+
+	switch c.Storage.Backend {
+	case StorageR2:
+		v.required("R2_EXPRESSIONS_ACCESS_KEY_ID", c.Storage.R2.AccessKeyID)
+		v.required("R2_EXPRESSIONS_SECRET_KEY", c.Storage.R2.SecretKey)
+		v.required("R2_EXPRESSIONS_ACCOUNT_ID", c.Storage.R2.AccountID)
+		v.required("R2_EXPRESSIONS_BUCKET", c.Storage.R2.Bucket)
+	case StorageLocal:
+		v.required("STORAGE_LOCAL_DIR", c.Storage.LocalDir)
+	default:
+		v.oneOf("STORAGE_BACKEND", c.Storage.Backend, StorageR2, StorageLocal)
+	}
+
+	switch c.Mailer.Backend {
+	case MailerMailgun:
+		v.required("EMAIL_SENDER_DOMAIN", c.Mailer.SenderDomain)
+		v.required("MAILGUN_APIKEY", c.Mailer.MailgunAPIKey)
+		v.url("MAILGUN_API_BASE", c.Mailer.MailgunAPIBase)
+		v.required("EMAIL_SENDER_ADDRESS", c.Mailer.SenderAddress)
+		v.required("CONTACT_EMAIL_RECIPIENT_ADDRESS", c.Mailer.ContactRecipient)
+	case MailerLog:
+	default:
+		v.oneOf("MAILER", c.Mailer.Backend, MailerMailgun, MailerLog)
+	}
+
+	switch c.Chain.Client {
+	case ChainNone:
+	case ChainRPC:
+		v.url("CHAIN_RPC_URL", c.Chain.RPCURL)
+		if c.Chain.ChainID <= 0 {
+			v.fail("CHAIN_ID is required when CHAIN_CLIENT is rpc")
+		}
+		if !common.IsHexAddress(c.Chain.DiamondAddress) {
+			v.fail("DIAMOND_ADDRESS must be a 0x-prefixed contract address")
+		}
+		if _, err := crypto.HexToECDSA(strings.TrimPrefix(c.Chain.OperatorKey, "0x")); err != nil {
+			v.fail("CHAIN_OPERATOR_KEY must be a hex-encoded secp256k1 private key")
+		}
+	case ChainSimulated:
+		// A missing operator key or Diamond address is made up at startup
+		if c.Chain.OperatorKey != "" {
+			if _, err := crypto.HexToECDSA(strings.TrimPrefix(c.Chain.OperatorKey, "0x")); err != nil {
+				v.fail("CHAIN_OPERATOR_KEY must be a hex-encoded secp256k1 private key")
+			}
+		}
+		if c.Chain.DiamondAddress != "" && !common.IsHexAddress(c.Chain.DiamondAddress) {
+			v.fail("DIAMOND_ADDRESS must be a 0x-prefixed contract address")
+		}
+		if c.Chain.SimulatedBlockTime <= 0 {
+			v.fail("CHAIN_SIMULATED_BLOCK_TIME must be positive")
+		}
+	default:
+		v.oneOf("CHAIN_CLIENT", c.Chain.Client, ChainNone, ChainRPC, ChainSimulated)
+	}
+	if c.Chain.Client != ChainNone {
+		relayer := c.Chain.Relayer
+		if relayer.MaxFeeGwei <= 0 {
+			v.fail("RELAYER_MAX_FEE_GWEI must be positive")
+		}
+		if relayer.MaxGasLimit < 21000 {
+			v.fail("RELAYER_MAX_GAS_LIMIT must be at least 21000")
+		}
+		if relayer.StuckAfter <= 0 || relayer.PollInterval <= 0 {
+			v.fail("RELAYER_STUCK_AFTER and RELAYER_POLL_INTERVAL must be positive")
+		}
+		if relayer.MaxAttempts < 1 {
+			v.fail("RELAYER_MAX_ATTEMPTS must be at least 1")
+		}
+		subsidies := c.Chain.Subsidies
+		if subsidies.StartBlock < 0 {
+			v.fail("SUBSIDY_START_BLOCK must not be negative")
+		}
+		if subsidies.BatchSize < 1 {
+			v.fail("SUBSIDY_BATCH_SIZE must be at least 1")
+		}
+		if subsidies.SyncInterval <= 0 {
+			v.fail("SUBSIDY_SYNC_INTERVAL must be positive")
+		}
+		if c.Chain.AnchorInterval <= 0 {
+			v.fail("ANCHOR_INTERVAL must be positive")
+		}
+	}
+
+	v.required("RELYING_PARTY", c.WebAuthn.RelyingParty)
+	if c.PublicURL != "" {
+		v.url("PUBLIC_URL", c.PublicURL)
+	}
+	v.oneOf("WEBAUTHN_SIGN_COUNT_POLICY", c.WebAuthn.SignCountPolicy, "warn", "require_second_factor", "deactivate")
+
+	if c.Credential.IssuerKey != "" {
+		if seed, err := hex.DecodeString(strings.TrimPrefix(c.Credential.IssuerKey, "0x")); err != nil || len(seed) != 32 {
+			v.fail("CREDENTIAL_ISSUER_KEY must be a hex 32-byte seed")
+		}
+	} else if c.Env == "production" {
+		v.fail("CREDENTIAL_ISSUER_KEY is required in production")
+	}
+	if c.Invitation.SigningKey != "" {
+		if key, err := hex.DecodeString(strings.TrimPrefix(c.Invitation.SigningKey, "0x")); err != nil || len(key) != 32 {
+			v.fail("INVITATION_SIGNING_KEY must be a hex 32-byte key")
+		}
+	} else if c.Env == "production" {
+		v.fail("INVITATION_SIGNING_KEY is required in production")
+	}
+	if c.Invitation.Lifetime <= 0 {
+		v.fail("INVITATION_LIFETIME must be positive")
+	}
+
+	for _, origin := range c.Security.AllowedOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || strings.TrimSuffix(u.Path, "/") != "" {
+			v.fail("ALLOWED_ORIGINS entry %q must look like https://example.org", origin)
+		}
+	}
+
+	v.oneOf("RATE_LIMIT_STORE", c.RateLimit.Store, RateLimitMemory, RateLimitMongo)
+
+	if info, err := os.Stat(c.Screening.KeywordDir); c.Screening.KeywordDir != "" && (err != nil || !info.IsDir()) {
+		v.fail("SCREENING_KEYWORD_DIR %q is not a directory", c.Screening.KeywordDir)
+	}
+	if c.Screening.WebhookURL != "" {
+		v.url("SCREENING_WEBHOOK_URL", c.Screening.WebhookURL)
+	}
+	if c.Screening.WebhookTimeout <= 0 {
+		v.fail("SCREENING_WEBHOOK_TIMEOUT must be positive")
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects validation failures
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) required(env, value string) {
+	if value == "" {
+		v.fail("%s is required", env)
+	}
+}
+
+func (v *validator) oneOf(env, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.fail("%s must be one of %s, got %q", env, strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) url(env, value string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		// The value is not echoed as RPC URLs embed API keys
+		v.fail("%s must be an absolute URL", env)
+	}
+}
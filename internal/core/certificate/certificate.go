@@ -0,0 +1,239 @@
+// Package certificate renders printable Proof of Peacemaking certificates as
+// PDF or PNG, with a QR code linking to the public verification page.
+//
+// Both formats share one A4 landscape layout measured in millimetres and
+// drawn through a canvas. Text is set in the Go fonts, which cover Latin,
+// Greek and Cyrillic. Flag emoji need a colour emoji font neither format can
+// embed, so a flag is drawn as a badge of its country code.
+package certificate
+
+import (
+	"fmt"
+	"image/color"
+	"proofofpeacemaking/internal/core/domain"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	pageWidth  = 297.0
+	pageHeight = 210.0
+
+	// excerptRunes caps the expression text before it is wrapped
+	excerptRunes = 280
+	// excerptLines caps the wrapped expression text
+	excerptLines = 3
+	excerptWidth = 190.0
+
+	qrSize = 36.0
+)
+
+var (
+	ink    = color.RGBA{R: 0x22, G: 0x2b, B: 0x28, A: 0xff}
+	muted  = color.RGBA{R: 0x5f, G: 0x6b, B: 0x66, A: 0xff}
+	accent = color.RGBA{R: 0x2f, G: 0x5d, B: 0x50, A: 0xff}
+	paper  = color.RGBA{R: 0xfd, G: 0xfb, B: 0xf5, A: 0xff}
+	white  = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// align is where a text's x coordinate sits on it
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+)
+
+// canvas is a page drawn in millimetres from the top left corner. y is the
+// text baseline; sizes are in points.
+type canvas interface {
+	fillRect(x, y, w, h float64, c color.Color)
+	strokeRect(x, y, w, h, lineWidth float64, c color.Color)
+	text(x, y, size float64, bold bool, a align, c color.Color, s string)
+	textWidth(size float64, bold bool, s string) float64
+	// qr draws a QR code of the given side at x, y
+	qr(x, y, side float64, code *qrcode.QRCode) error
+}
+
+// Render draws cert in the given format
+func Render(cert *domain.Certificate, format domain.CertificateFormat) ([]byte, error) {
+	code, err := qrcode.New(cert.VerifyURL, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode verification link: %w", err)
+	}
+
+	switch format {
+	case domain.CertificatePNG:
+		c, err := newPNGCanvas()
+		if err != nil {
+			return nil, err
+		}
+		if err := layout(c, cert, code); err != nil {
+			return nil, err
+		}
+		return c.encode()
+	case domain.CertificatePDF:
+		c, err := newPDFCanvas()
+		if err != nil {
+			return nil, err
+		}
+		if err := layout(c, cert, code); err != nil {
+			return nil, err
+		}
+		return c.encode()
+	}
+	return nil, domain.Validation("unknown certificate format %q", format)
+}
+
+// layout draws the certificate on c
+func layout(c canvas, cert *domain.Certificate, code *qrcode.QRCode) error {
+	const center = pageWidth / 2
+
+	c.fillRect(0, 0, pageWidth, pageHeight, paper)
+	c.strokeRect(8, 8, pageWidth-16, pageHeight-16, 1.2, accent)
+	c.strokeRect(12, 12, pageWidth-24, pageHeight-24, 0.3, accent)
+
+	c.text(center, 38, 30, true, alignCenter, accent, "Proof of Peacemaking")
+	c.text(center, 50, 12, false, alignCenter, muted, "This certifies that")
+
+	y := 66.0
+	for i, party := range cert.Parties {
+		if i > 0 {
+			c.text(center, y-6, 12, false, alignCenter, muted, "and")
+			y += 6
+		}
+		name := party.Name
+		if name == "" {
+			name = "Anonymous peacemaker"
+		}
+		c.text(center, y, 22, true, alignCenter, ink, name)
+		drawAffiliation(c, center, y+7, party)
+		y += 22
+	}
+
+	c.text(center, y, 12, false, alignCenter, muted, "made peace through the expression")
+	y += 9
+	if cert.Excerpt != "" {
+		for _, line := range wrap(c, "“"+cert.Excerpt+"”", 12, excerptWidth, excerptLines) {
+			c.text(center, y, 12, false, alignCenter, ink, line)
+			y += 6
+		}
+	}
+
+	details := []string{"Date: " + cert.Date.UTC().Format("2 January 2006")}
+	if cert.TokenID > 0 {
+		details = append(details, fmt.Sprintf("Token ID: #%d", cert.TokenID))
+	}
+	if cert.IPFSHash != "" {
+		details = append(details, "IPFS CID: "+cert.IPFSHash)
+	}
+	details = append(details, "Record: "+cert.SourceID)
+	detailY := pageHeight - 22 - float64(len(details)-1)*5.5
+	for _, detail := range details {
+		c.text(22, detailY, 9, false, alignLeft, muted, detail)
+		detailY += 5.5
+	}
+
+	qrX, qrY := pageWidth-22-qrSize, pageHeight-28-qrSize
+	if err := c.qr(qrX, qrY, qrSize, code); err != nil {
+		return err
+	}
+	c.text(qrX+qrSize/2, qrY+qrSize+5, 9, false, alignCenter, muted, "Scan to verify")
+	return nil
+}
+
+// drawAffiliation writes a party's country and role under their name, after a
+// badge standing in for the flag
+func drawAffiliation(c canvas, center, y float64, party domain.CertificateParty) {
+	label := roleLabel(party.Role)
+	if party.CountryName != "" {
+		label = party.CountryName + " · " + label
+	}
+	code := flagCode(party.Flag, party.Country)
+	if code == "" {
+		c.text(center, y, 11, false, alignCenter, muted, label)
+		return
+	}
+
+	const badgeWidth, badgeHeight, gap = 10.0, 5.5, 2.5
+	width := badgeWidth + gap + c.textWidth(11, false, label)
+	x := center - width/2
+	c.fillRect(x, y-badgeHeight+1.2, badgeWidth, badgeHeight, accent)
+	c.text(x+badgeWidth/2, y, 9, true, alignCenter, white, code)
+	c.text(x+badgeWidth+gap, y, 11, false, alignLeft, muted, label)
+}
+
+func roleLabel(role string) string {
+	if role == "creator" {
+		return "expression creator"
+	}
+	return role
+}
+
+// flagCode reads the country code back from a flag emoji, whose two regional
+// indicator symbols spell it, falling back to country
+func flagCode(flag, country string) string {
+	var code strings.Builder
+	for _, r := range flag {
+		if r < 0x1F1E6 || r > 0x1F1FF {
+			return strings.ToUpper(country)
+		}
+		code.WriteRune('A' + (r - 0x1F1E6))
+	}
+	if code.Len() == 0 {
+		return strings.ToUpper(country)
+	}
+	return code.String()
+}
+
+// Excerpt shortens an expression's text for a certificate
+func Excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= excerptRunes {
+		return text
+	}
+	runes := []rune(text)
+	cut := string(runes[:excerptRunes])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// wrap breaks s into lines no wider than width, ending with an ellipsis if
+// it needs more than maxLines
+func wrap(c canvas, s string, size, width float64, maxLines int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line == "" || c.textWidth(size, false, candidate) <= width {
+			line = candidate
+			continue
+		}
+		lines = append(lines, line)
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1]
+		for c.textWidth(size, false, last+"…”") > width {
+			i := strings.LastIndex(last, " ")
+			if i < 0 {
+				break
+			}
+			last = last[:i]
+		}
+		lines[maxLines-1] = strings.TrimRight(last, " ,.;:…") + "…”"
+	}
+	return lines
+}
@@ -0,0 +1,89 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const pdfFont = "go"
+
+// pdfCanvas draws onto a single A4 landscape page
+type pdfCanvas struct {
+	pdf *gofpdf.Fpdf
+}
+
+func newPDFCanvas() (*pdfCanvas, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle("Proof of Peacemaking", true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	pdf.AddPage()
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("failed to start certificate: %w", err)
+	}
+	return &pdfCanvas{pdf: pdf}, nil
+}
+
+func rgb(c color.Color) (int, int, int) {
+	r, g, b, _ := c.RGBA()
+	return int(r >> 8), int(g >> 8), int(b >> 8)
+}
+
+func (c *pdfCanvas) fillRect(x, y, w, h float64, col color.Color) {
+	c.pdf.SetFillColor(rgb(col))
+	c.pdf.Rect(x, y, w, h, "F")
+}
+
+func (c *pdfCanvas) strokeRect(x, y, w, h, lineWidth float64, col color.Color) {
+	c.pdf.SetDrawColor(rgb(col))
+	c.pdf.SetLineWidth(lineWidth)
+	c.pdf.Rect(x, y, w, h, "D")
+}
+
+func (c *pdfCanvas) setFont(size float64, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	c.pdf.SetFont(pdfFont, style, size)
+}
+
+func (c *pdfCanvas) text(x, y, size float64, bold bool, a align, col color.Color, s string) {
+	c.setFont(size, bold)
+	if a == alignCenter {
+		x -= c.pdf.GetStringWidth(s) / 2
+	}
+	c.pdf.SetTextColor(rgb(col))
+	c.pdf.Text(x, y, s)
+}
+
+func (c *pdfCanvas) textWidth(size float64, bold bool, s string) float64 {
+	c.setFont(size, bold)
+	return c.pdf.GetStringWidth(s)
+}
+
+func (c *pdfCanvas) qr(x, y, side float64, code *qrcode.QRCode) error {
+	data, err := code.PNG(px(side))
+	if err != nil {
+		return fmt.Errorf("failed to render QR code: %w", err)
+	}
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+	c.pdf.RegisterImageOptionsReader("qr", options, bytes.NewReader(data))
+	c.pdf.ImageOptions("qr", x, y, side, side, false, options, 0, "")
+	return nil
+}
+
+func (c *pdfCanvas) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode certificate: %w", err)
+	}
+	return buf.Bytes(), nil
+}
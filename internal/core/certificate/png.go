@@ -0,0 +1,117 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// pngDPI renders A4 at 150 dots per inch, 1754 by 1240 pixels
+const pngDPI = 150.0
+
+const pxPerMM = pngDPI / 25.4
+
+// pngCanvas draws onto an RGBA image
+type pngCanvas struct {
+	img     *image.RGBA
+	regular *opentype.Font
+	bold    *opentype.Font
+	faces   map[faceKey]font.Face
+}
+
+type faceKey struct {
+	size float64
+	bold bool
+}
+
+func newPNGCanvas() (*pngCanvas, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate font: %w", err)
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate font: %w", err)
+	}
+	return &pngCanvas{
+		img:     image.NewRGBA(image.Rect(0, 0, px(pageWidth), px(pageHeight))),
+		regular: regular,
+		bold:    bold,
+		faces:   make(map[faceKey]font.Face),
+	}, nil
+}
+
+func px(mm float64) int {
+	return int(math.Round(mm * pxPerMM))
+}
+
+func (c *pngCanvas) face(size float64, bold bool) font.Face {
+	key := faceKey{size: size, bold: bold}
+	if face, ok := c.faces[key]; ok {
+		return face
+	}
+	f := c.regular
+	if bold {
+		f = c.bold
+	}
+	// The fonts were parsed above, so NewFace cannot fail on them
+	face, _ := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: pngDPI, Hinting: font.HintingFull})
+	c.faces[key] = face
+	return face
+}
+
+func (c *pngCanvas) fillRect(x, y, w, h float64, col color.Color) {
+	rect := image.Rect(px(x), px(y), px(x+w), px(y+h))
+	draw.Draw(c.img, rect, image.NewUniform(col), image.Point{}, draw.Over)
+}
+
+func (c *pngCanvas) strokeRect(x, y, w, h, lineWidth float64, col color.Color) {
+	half := lineWidth / 2
+	c.fillRect(x-half, y-half, w+lineWidth, lineWidth, col)
+	c.fillRect(x-half, y+h-half, w+lineWidth, lineWidth, col)
+	c.fillRect(x-half, y-half, lineWidth, h+lineWidth, col)
+	c.fillRect(x+w-half, y-half, lineWidth, h+lineWidth, col)
+}
+
+func (c *pngCanvas) text(x, y, size float64, bold bool, a align, col color.Color, s string) {
+	if a == alignCenter {
+		x -= c.textWidth(size, bold, s) / 2
+	}
+	d := font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: c.face(size, bold),
+		Dot:  fixed.P(px(x), px(y)),
+	}
+	d.DrawString(s)
+}
+
+func (c *pngCanvas) textWidth(size float64, bold bool, s string) float64 {
+	width := font.MeasureString(c.face(size, bold), s)
+	return float64(width) / 64 / pxPerMM
+}
+
+func (c *pngCanvas) qr(x, y, side float64, code *qrcode.QRCode) error {
+	img := code.Image(px(side))
+	draw.Draw(c.img, img.Bounds().Add(image.Pt(px(x), px(y))), img, img.Bounds().Min, draw.Src)
+	return nil
+}
+
+func (c *pngCanvas) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("failed to encode certificate: %w", err)
+	}
+	return buf.Bytes(), nil
+}
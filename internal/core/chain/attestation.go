@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// AttestationPrimaryType is the EIP-712 primary type of acknowledgement attestations
+const AttestationPrimaryType = "Acknowledgement"
+
+// AttestationTypes are the EIP-712 types acknowledgers sign with
+// eth_signTypedData_v4. The domain has only a name and version, so an
+// attestation does not depend on any chain.
+var AttestationTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+	},
+	AttestationPrimaryType: {
+		{Name: "expressionId", Type: "string"},
+		{Name: "acknowledger", Type: "address"},
+		{Name: "contentHash", Type: "bytes32"},
+		{Name: "status", Type: "string"},
+		{Name: "timestamp", Type: "uint256"},
+	},
+}
+
+// AttestationDomain is the signing domain of acknowledgement attestations
+var AttestationDomain = domain.AttestationDomain{
+	Name:    relayDomainName,
+	Version: relayDomainVersion,
+}
+
+// ContentHash is the keccak256 hash of an expression's or acknowledgement's
+// content as JSON with keys sorted and no HTML escaping, which is what
+// JavaScript's JSON.stringify gives for an object with sorted keys. No
+// content hashes as {}.
+func ContentHash(content map[string]string) (common.Hash, error) {
+	if content == nil {
+		content = map[string]string{}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(content); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode content: %w", err)
+	}
+	return crypto.Keccak256Hash(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+// HashAttestation returns the EIP-712 digest an acknowledger signs for msg
+func HashAttestation(msg domain.AttestationMessage) (common.Hash, error) {
+	digest, _, err := apitypes.TypedDataAndHash(apitypes.TypedData{
+		Types:       AttestationTypes,
+		PrimaryType: AttestationPrimaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:    AttestationDomain.Name,
+			Version: AttestationDomain.Version,
+		},
+		Message: apitypes.TypedDataMessage{
+			"expressionId": msg.ExpressionID,
+			"acknowledger": msg.Acknowledger,
+			"contentHash":  msg.ContentHash,
+			"status":       string(msg.Status),
+			"timestamp":    strconv.FormatInt(msg.Timestamp, 10),
+		},
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash attestation: %w", err)
+	}
+	return common.BytesToHash(digest), nil
+}
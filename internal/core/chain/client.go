@@ -0,0 +1,62 @@
+// Package chain connects the server to an EVM chain: the node client the
+// relayer sends operator transactions through, the Diamond contract's calls
+// and the EIP-712 requests users sign to have them relayed.
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Client is the part of a JSON-RPC node the server uses. Both a dialled node
+// and the simulated chain satisfy it.
+type Client interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Dial connects to the node at rawURL
+func Dial(ctx context.Context, rawURL string) (Client, error) {
+	client, err := ethclient.DialContext(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to chain node: %w", err)
+	}
+	return client, nil
+}
+
+// HealthCheck reports whether the chain node answers
+type HealthCheck struct {
+	client Client
+}
+
+// NewHealthCheck probes client for readiness checks
+func NewHealthCheck(client Client) *HealthCheck {
+	return &HealthCheck{client: client}
+}
+
+// Name identifies the chain node in readiness checks
+func (h *HealthCheck) Name() string {
+	return "chain"
+}
+
+// Check asks the node for the latest block number
+func (h *HealthCheck) Check(ctx context.Context) error {
+	if _, err := h.client.BlockNumber(ctx); err != nil {
+		return fmt.Errorf("failed to reach chain node: %w", err)
+	}
+	return nil
+}
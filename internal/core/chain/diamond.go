@@ -0,0 +1,370 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"proofofpeacemaking/internal/core/domain"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// diamondABI covers the facet functions the server calls through the Diamond
+// proxy. It is kept by hand in step with contracts/facets.
+const diamondABI = `[
+	{
+		"type": "function",
+		"name": "createExpressionFor",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{
+				"name": "req",
+				"type": "tuple",
+				"components": [
+					{"name": "from", "type": "address"},
+					{"name": "textContent", "type": "string"},
+					{"name": "audioContent", "type": "string"},
+					{"name": "videoContent", "type": "string"},
+					{"name": "imageContent", "type": "string"},
+					{"name": "nonce", "type": "uint256"},
+					{"name": "deadline", "type": "uint256"}
+				]
+			},
+			{"name": "signature", "type": "bytes"}
+		],
+		"outputs": [{"name": "", "type": "uint256"}]
+	},
+	{
+		"type": "function",
+		"name": "acknowledgeFor",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{
+				"name": "req",
+				"type": "tuple",
+				"components": [
+					{"name": "from", "type": "address"},
+					{"name": "expressionId", "type": "uint256"},
+					{"name": "creator", "type": "address"},
+					{"name": "message", "type": "string"},
+					{"name": "textContent", "type": "string"},
+					{"name": "audioContent", "type": "string"},
+					{"name": "videoContent", "type": "string"},
+					{"name": "imageContent", "type": "string"},
+					{"name": "nonce", "type": "uint256"},
+					{"name": "deadline", "type": "uint256"}
+				]
+			},
+			{"name": "signature", "type": "bytes"}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "setOperatorSubsidies",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{"name": "users", "type": "address[]"},
+			{"name": "operations", "type": "uint8[]"},
+			{"name": "statuses", "type": "bool[]"}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "setOperatorStatus",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{"name": "operator", "type": "address"},
+			{"name": "active", "type": "bool"}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "anchorRoot",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{"name": "root", "type": "bytes32"},
+			{"name": "leafCount", "type": "uint256"}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "mint",
+		"stateMutability": "nonpayable",
+		"inputs": [
+			{"name": "to", "type": "address"},
+			{"name": "expressionId", "type": "uint256"},
+			{"name": "acknowledgementId", "type": "uint256"},
+			{"name": "expressionCreator", "type": "address"},
+			{"name": "uri", "type": "string"}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "hasValidPOP",
+		"stateMutability": "view",
+		"inputs": [{"name": "account", "type": "address"}],
+		"outputs": [{"name": "", "type": "bool"}]
+	},
+	{
+		"type": "function",
+		"name": "getExpressionsByCreator",
+		"stateMutability": "view",
+		"inputs": [{"name": "_creator", "type": "address"}],
+		"outputs": [{"name": "", "type": "uint256[]"}]
+	},
+	{
+		"type": "event",
+		"name": "ExpressionCreated",
+		"anonymous": false,
+		"inputs": [
+			{"name": "expressionId", "type": "uint256", "indexed": true},
+			{"name": "creator", "type": "address", "indexed": true},
+			{"name": "textContent", "type": "string", "indexed": false},
+			{"name": "audioContent", "type": "string", "indexed": false},
+			{"name": "videoContent", "type": "string", "indexed": false},
+			{"name": "imageContent", "type": "string", "indexed": false},
+			{"name": "timestamp", "type": "uint256", "indexed": false}
+		]
+	},
+	{
+		"type": "event",
+		"name": "POPNFTMinted",
+		"anonymous": false,
+		"inputs": [
+			{"name": "to", "type": "address", "indexed": true},
+			{"name": "tokenId", "type": "uint256", "indexed": true},
+			{"name": "expressionId", "type": "uint256", "indexed": true},
+			{"name": "acknowledgementId", "type": "uint256", "indexed": false},
+			{"name": "uri", "type": "string", "indexed": false}
+		]
+	},
+	{
+		"type": "event",
+		"name": "SubsidyStatusChanged",
+		"anonymous": false,
+		"inputs": [
+			{"name": "operator", "type": "address", "indexed": false},
+			{"name": "user", "type": "address", "indexed": false},
+			{"name": "operation", "type": "uint8", "indexed": false},
+			{"name": "status", "type": "bool", "indexed": false}
+		]
+	}
+]`
+
+// Diamond is the parsed ABI of the Diamond proxy's facets
+var Diamond = mustParseABI(diamondABI)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid contract ABI: %v", err))
+	}
+	return parsed
+}
+
+// createExpressionRequest and acknowledgeRequest are the facets' request
+// structs, with fields named so the ABI encoder matches them up
+type createExpressionRequest struct {
+	From         common.Address
+	TextContent  string
+	AudioContent string
+	VideoContent string
+	ImageContent string
+	Nonce        *big.Int
+	Deadline     *big.Int
+}
+
+type acknowledgeRequest struct {
+	From         common.Address
+	ExpressionId *big.Int
+	Creator      common.Address
+	Message      string
+	TextContent  string
+	AudioContent string
+	VideoContent string
+	ImageContent string
+	Nonce        *big.Int
+	Deadline     *big.Int
+}
+
+// PackCreateExpressionFor encodes a createExpressionFor call carrying a
+// signed createExpression request
+func PackCreateExpressionFor(req *domain.RelayRequest) ([]byte, error) {
+	signature, err := relaySignature(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return Diamond.Pack("createExpressionFor", createExpressionRequest{
+		From:         common.HexToAddress(req.From),
+		TextContent:  req.TextContent,
+		AudioContent: req.AudioContent,
+		VideoContent: req.VideoContent,
+		ImageContent: req.ImageContent,
+		Nonce:        new(big.Int).SetUint64(req.Nonce),
+		Deadline:     big.NewInt(req.Deadline),
+	}, signature)
+}
+
+// PackAcknowledgeFor encodes an acknowledgeFor call carrying a signed
+// acknowledge request
+func PackAcknowledgeFor(req *domain.RelayRequest) ([]byte, error) {
+	expressionID, ok := new(big.Int).SetString(req.ExpressionID, 10)
+	if !ok || expressionID.Sign() < 0 {
+		return nil, errors.New("invalid expression ID")
+	}
+	if !common.IsHexAddress(req.Creator) {
+		return nil, errors.New("invalid creator address")
+	}
+	signature, err := relaySignature(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return Diamond.Pack("acknowledgeFor", acknowledgeRequest{
+		From:         common.HexToAddress(req.From),
+		ExpressionId: expressionID,
+		Creator:      common.HexToAddress(req.Creator),
+		Message:      req.Message,
+		TextContent:  req.TextContent,
+		AudioContent: req.AudioContent,
+		VideoContent: req.VideoContent,
+		ImageContent: req.ImageContent,
+		Nonce:        new(big.Int).SetUint64(req.Nonce),
+		Deadline:     big.NewInt(req.Deadline),
+	}, signature)
+}
+
+// relaySignature decodes a request's signature for the facets, which check
+// it with OpenZeppelin's ECDSA and so want v as 27 or 28
+func relaySignature(signature string) ([]byte, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, errors.New("invalid signature length")
+	}
+	if sig[crypto.RecoveryIDOffset] < 27 {
+		sig[crypto.RecoveryIDOffset] += 27
+	}
+	return sig, nil
+}
+
+// PackSetOperatorSubsidies encodes a setOperatorSubsidies call setting each
+// user's subsidy for the operation at the same index
+func PackSetOperatorSubsidies(users []common.Address, operations []uint8, statuses []bool) ([]byte, error) {
+	return Diamond.Pack("setOperatorSubsidies", users, operations, statuses)
+}
+
+// PackSetOperatorStatus encodes a setOperatorStatus call, which the Diamond's
+// owner sends to let operator pay for subsidized calls
+func PackSetOperatorStatus(operator common.Address, active bool) ([]byte, error) {
+	return Diamond.Pack("setOperatorStatus", operator, active)
+}
+
+// PackAnchorRoot encodes an AnchorFacet call committing a Merkle root over
+// leafCount records
+func PackAnchorRoot(root common.Hash, leafCount int) ([]byte, error) {
+	return Diamond.Pack("anchorRoot", root, big.NewInt(int64(leafCount)))
+}
+
+// PackMint encodes a POPNFTFacet mint of a proof NFT to the acknowledger at
+// to, which only the Diamond's owner may send
+func PackMint(to common.Address, expressionID, acknowledgementID int, creator common.Address, uri string) ([]byte, error) {
+	return Diamond.Pack("mint", to, big.NewInt(int64(expressionID)), big.NewInt(int64(acknowledgementID)), creator, uri)
+}
+
+// HasValidPOP asks the POPNFTFacet whether account holds a Proof of
+// Peacemaking NFT
+func HasValidPOP(ctx context.Context, client Client, diamond, account common.Address) (bool, error) {
+	data, err := Diamond.Pack("hasValidPOP", account)
+	if err != nil {
+		return false, err
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &diamond, Data: data}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call hasValidPOP: %w", err)
+	}
+	var valid bool
+	if err := Diamond.UnpackIntoInterface(&valid, "hasValidPOP", result); err != nil {
+		return false, fmt.Errorf("failed to decode hasValidPOP: %w", err)
+	}
+	return valid, nil
+}
+
+// ExpressionsByCreator asks the ExpressionFacet for the IDs of the
+// expressions recorded for creator
+func ExpressionsByCreator(ctx context.Context, client Client, diamond, creator common.Address) ([]*big.Int, error) {
+	data, err := Diamond.Pack("getExpressionsByCreator", creator)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &diamond, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getExpressionsByCreator: %w", err)
+	}
+	var ids []*big.Int
+	if err := Diamond.UnpackIntoInterface(&ids, "getExpressionsByCreator", result); err != nil {
+		return nil, fmt.Errorf("failed to decode getExpressionsByCreator: %w", err)
+	}
+	return ids, nil
+}
+
+// ExpressionCreatedTopic identifies ExpressionCreated logs
+var ExpressionCreatedTopic = Diamond.Events["ExpressionCreated"].ID
+
+// CreatedExpressionID returns the on-chain ID of the expression a mined
+// transaction created, from its ExpressionCreated log
+func CreatedExpressionID(receipt *types.Receipt, diamond common.Address) (*big.Int, bool) {
+	for _, log := range receipt.Logs {
+		if log.Address == diamond && len(log.Topics) > 1 && log.Topics[0] == ExpressionCreatedTopic {
+			return new(big.Int).SetBytes(log.Topics[1].Bytes()), true
+		}
+	}
+	return nil, false
+}
+
+// POPNFTMintedTopic identifies POPNFTMinted logs
+var POPNFTMintedTopic = Diamond.Events["POPNFTMinted"].ID
+
+// MintedTokenID returns the ID of the proof NFT a mined transaction minted,
+// from its POPNFTMinted log
+func MintedTokenID(receipt *types.Receipt, diamond common.Address) (*big.Int, bool) {
+	for _, log := range receipt.Logs {
+		if log.Address == diamond && len(log.Topics) > 2 && log.Topics[0] == POPNFTMintedTopic {
+			return new(big.Int).SetBytes(log.Topics[2].Bytes()), true
+		}
+	}
+	return nil, false
+}
+
+// SubsidyStatusChanged is emitted by the PermissionsFacet for every subsidy set
+type SubsidyStatusChanged struct {
+	Operator  common.Address
+	User      common.Address
+	Operation uint8
+	Status    bool
+}
+
+// SubsidyStatusChangedTopic identifies SubsidyStatusChanged logs
+var SubsidyStatusChangedTopic = Diamond.Events["SubsidyStatusChanged"].ID
+
+// ParseSubsidyStatusChanged decodes a SubsidyStatusChanged log
+func ParseSubsidyStatusChanged(log types.Log) (*SubsidyStatusChanged, error) {
+	var event SubsidyStatusChanged
+	if err := Diamond.UnpackIntoInterface(&event, "SubsidyStatusChanged", log.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode SubsidyStatusChanged log: %w", err)
+	}
+	return &event, nil
+}
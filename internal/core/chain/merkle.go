@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// anchorLeafArguments is the ABI layout of an anchored record, so leaves can
+// be rebuilt in Solidity with abi.encode
+var anchorLeafArguments = abi.Arguments{
+	{Type: mustNewType("string")},
+	{Type: mustNewType("string")},
+	{Type: mustNewType("bytes32")},
+	{Type: mustNewType("string")},
+	{Type: mustNewType("uint256")},
+}
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// AnchorLeaf is the Merkle leaf of one record:
+// keccak256(keccak256(abi.encode(kind, id, contentHash, creator, timestamp))).
+// Hashing twice keeps a leaf from passing as an inner node.
+func AnchorLeaf(kind, id string, contentHash common.Hash, creator string, timestamp int64) (common.Hash, error) {
+	encoded, err := anchorLeafArguments.Pack(kind, id, contentHash, creator, big.NewInt(timestamp))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode anchor leaf: %w", err)
+	}
+	return crypto.Keccak256Hash(crypto.Keccak256(encoded)), nil
+}
+
+// BuildMerkleTree returns the root over leaves and each leaf's proof, the
+// sibling hashes from the leaf up. Pairs are hashed in sorted order, as in
+// OpenZeppelin's MerkleProof, so a proof needs no left or right flags; an
+// odd node out moves up a level unchanged.
+func BuildMerkleTree(leaves []common.Hash) (common.Hash, [][]common.Hash) {
+	if len(leaves) == 0 {
+		return common.Hash{}, nil
+	}
+
+	proofs := make([][]common.Hash, len(leaves))
+	// positions[i] is where leaf i's ancestor sits in the current level
+	positions := make([]int, len(leaves))
+	for i := range positions {
+		positions[i] = i
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashPair(level[i], level[i+1]))
+		}
+		for leaf, pos := range positions {
+			sibling := pos ^ 1
+			if sibling < len(level) {
+				proofs[leaf] = append(proofs[leaf], level[sibling])
+			}
+			positions[leaf] = pos / 2
+		}
+		level = next
+	}
+	return level[0], proofs
+}
+
+// VerifyMerkleProof reports whether proof leads from leaf to root
+func VerifyMerkleProof(leaf common.Hash, proof []common.Hash, root common.Hash) bool {
+	hash := leaf
+	for _, sibling := range proof {
+		hash = hashPair(hash, sibling)
+	}
+	return hash == root
+}
+
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a.Bytes(), b.Bytes()) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a.Bytes(), b.Bytes())
+}
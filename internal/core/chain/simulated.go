@@ -0,0 +1,181 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// SimulatedChainID is the chain ID the simulated chain always uses
+const SimulatedChainID = 1337
+
+// DefaultArtifactsDir is where hardhat writes the compiled contracts,
+// relative to the repository root
+const DefaultArtifactsDir = "scripts/artifacts"
+
+// SimulatedDiamondAddress is where the Diamond sits on the simulated chain
+var SimulatedDiamondAddress = common.HexToAddress("0x000000000000000000000000000000000000d1a0")
+
+// DiamondFacets are the facets the deploy script cuts into the Diamond
+var DiamondFacets = []string{"ExpressionFacet", "AcknowledgementFacet", "POPNFTFacet", "PermissionsFacet", "AnchorFacet"}
+
+// simulatedBalance is what each funded account starts with
+var simulatedBalance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+
+// diamondStoragePosition is where LibDiamond keeps the Diamond's selector
+// table, facet lists and owner, in that order
+var diamondStoragePosition = crypto.Keccak256Hash([]byte("pop.v1.diamond.storage"))
+
+// Simulated is an in-process chain for development and for exercising the
+// relayer without a node. NewSimulated deploys no contracts, so calls to the
+// Diamond are mined as plain transactions that do nothing, which is enough to
+// see nonces, fees, replacements and receipts behave as they would on a real
+// chain; NewSimulatedDiamond deploys the Diamond and its facets so calls run
+// for real. Blocks are sealed on Commit, or every interval once Start is
+// called.
+type Simulated struct {
+	backend *simulated.Backend
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewSimulated starts a simulated chain on which each funded account holds
+// 1000 ether
+func NewSimulated(funded ...common.Address) *Simulated {
+	return newSimulated(fundedAlloc(funded))
+}
+
+// NewSimulatedDiamond starts a simulated chain with the Diamond at
+// SimulatedDiamondAddress, owned by owner, and each funded account holding
+// 1000 ether. The contracts are read from hardhat's artifacts in dir, written
+// by `npx hardhat compile` in scripts. Rather than running the deploy script,
+// the facets' code is placed in the genesis block and the Diamond's selector
+// table and owner are written straight into its storage.
+func NewSimulatedDiamond(dir string, owner common.Address, funded ...common.Address) (*Simulated, error) {
+	alloc := fundedAlloc(funded)
+
+	diamondCode, _, err := readArtifact(filepath.Join(dir, "contracts", "Diamond.sol", "Diamond.json"))
+	if err != nil {
+		return nil, err
+	}
+	ownerSlot := common.BigToHash(new(big.Int).Add(diamondStoragePosition.Big(), big.NewInt(3)))
+	storage := map[common.Hash]common.Hash{
+		ownerSlot: common.BytesToHash(owner.Bytes()),
+	}
+
+	for i, name := range DiamondFacets {
+		code, facetABI, err := readArtifact(filepath.Join(dir, "contracts", "facets", name+".sol", name+".json"))
+		if err != nil {
+			return nil, err
+		}
+		facet := common.BigToAddress(new(big.Int).Add(SimulatedDiamondAddress.Big(), big.NewInt(int64(i+1))))
+		alloc[facet] = types.Account{Code: code, Balance: new(big.Int)}
+		for _, method := range facetABI.Methods {
+			slot := selectorSlot(method.ID)
+			if _, taken := storage[slot]; taken {
+				return nil, fmt.Errorf("selector of %s.%s is already taken by another facet", name, method.Name)
+			}
+			storage[slot] = common.BytesToHash(facet.Bytes())
+		}
+	}
+	alloc[SimulatedDiamondAddress] = types.Account{Code: diamondCode, Storage: storage, Balance: new(big.Int)}
+	return newSimulated(alloc), nil
+}
+
+func newSimulated(alloc types.GenesisAlloc) *Simulated {
+	return &Simulated{
+		backend: simulated.NewBackend(alloc),
+		stop:    make(chan struct{}),
+	}
+}
+
+func fundedAlloc(funded []common.Address) types.GenesisAlloc {
+	alloc := types.GenesisAlloc{}
+	for _, account := range funded {
+		alloc[account] = types.Account{Balance: simulatedBalance}
+	}
+	return alloc
+}
+
+// selectorSlot is the storage slot of selector's entry in the Diamond's
+// selector-to-facet mapping. Solidity pads bytes4 keys on the right.
+func selectorSlot(selector []byte) common.Hash {
+	var key common.Hash
+	copy(key[:], selector)
+	return crypto.Keccak256Hash(key.Bytes(), diamondStoragePosition.Bytes())
+}
+
+// readArtifact returns the deployed code and ABI from a hardhat artifact
+func readArtifact(path string) ([]byte, abi.ABI, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, abi.ABI{}, fmt.Errorf("failed to read contract artifact: %w", err)
+	}
+	var artifact struct {
+		ABI              json.RawMessage `json:"abi"`
+		DeployedBytecode string          `json:"deployedBytecode"`
+	}
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		return nil, abi.ABI{}, fmt.Errorf("invalid contract artifact %s: %w", path, err)
+	}
+	code, err := hexutil.Decode(artifact.DeployedBytecode)
+	if err != nil || len(code) == 0 {
+		return nil, abi.ABI{}, fmt.Errorf("contract artifact %s has no deployed code", path)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	if err != nil {
+		return nil, abi.ABI{}, fmt.Errorf("invalid ABI in contract artifact %s: %w", path, err)
+	}
+	return code, parsed, nil
+}
+
+// Client talks to the simulated chain
+func (s *Simulated) Client() Client {
+	return s.backend.Client()
+}
+
+// Commit seals a block with the pending transactions
+func (s *Simulated) Commit() common.Hash {
+	return s.backend.Commit()
+}
+
+// Rollback drops the pending transactions, as a node that never mines them
+// would, to exercise replacing stuck transactions
+func (s *Simulated) Rollback() {
+	s.backend.Rollback()
+}
+
+// Start seals a block every interval until Close is called
+func (s *Simulated) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.backend.Commit()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops sealing blocks and shuts the chain down
+func (s *Simulated) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	return s.backend.Close()
+}
@@ -0,0 +1,122 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 domain name and version relay requests are signed under
+const (
+	relayDomainName    = "Proof of Peacemaking"
+	relayDomainVersion = "1"
+)
+
+// RelayTypes are the EIP-712 types of relay requests, one primary type per
+// operation. Clients sign with eth_signTypedData_v4 using these types.
+var RelayTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	string(domain.RelayCreateExpression): {
+		{Name: "from", Type: "address"},
+		{Name: "textContent", Type: "string"},
+		{Name: "audioContent", Type: "string"},
+		{Name: "videoContent", Type: "string"},
+		{Name: "imageContent", Type: "string"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	},
+	string(domain.RelayAcknowledge): {
+		{Name: "from", Type: "address"},
+		{Name: "expressionId", Type: "uint256"},
+		{Name: "creator", Type: "address"},
+		{Name: "message", Type: "string"},
+		{Name: "textContent", Type: "string"},
+		{Name: "audioContent", Type: "string"},
+		{Name: "videoContent", Type: "string"},
+		{Name: "imageContent", Type: "string"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	},
+}
+
+// NewRelayDomain is the signing domain for relay requests to the Diamond at
+// diamond on chain chainID
+func NewRelayDomain(chainID int64, diamond common.Address) domain.RelayDomain {
+	return domain.RelayDomain{
+		Name:              relayDomainName,
+		Version:           relayDomainVersion,
+		ChainID:           chainID,
+		VerifyingContract: diamond.Hex(),
+	}
+}
+
+// HashRelayRequest returns the EIP-712 digest a user signs for req
+func HashRelayRequest(d domain.RelayDomain, req *domain.RelayRequest) (common.Hash, error) {
+	message := apitypes.TypedDataMessage{
+		"from":         req.From,
+		"textContent":  req.TextContent,
+		"audioContent": req.AudioContent,
+		"videoContent": req.VideoContent,
+		"imageContent": req.ImageContent,
+		"nonce":        strconv.FormatUint(req.Nonce, 10),
+		"deadline":     strconv.FormatInt(req.Deadline, 10),
+	}
+	switch req.Operation {
+	case domain.RelayCreateExpression:
+	case domain.RelayAcknowledge:
+		message["expressionId"] = req.ExpressionID
+		message["creator"] = req.Creator
+		message["message"] = req.Message
+	default:
+		return common.Hash{}, fmt.Errorf("unknown relay operation %q", req.Operation)
+	}
+
+	digest, _, err := apitypes.TypedDataAndHash(apitypes.TypedData{
+		Types:       RelayTypes,
+		PrimaryType: string(req.Operation),
+		Domain: apitypes.TypedDataDomain{
+			Name:              d.Name,
+			Version:           d.Version,
+			ChainId:           math.NewHexOrDecimal256(d.ChainID),
+			VerifyingContract: d.VerifyingContract,
+		},
+		Message: message,
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash relay request: %w", err)
+	}
+	return common.BytesToHash(digest), nil
+}
+
+// RecoverSigner returns the address whose key produced signature over digest.
+// Wallets may give v as 27 or 28 rather than 0 or 1.
+func RecoverSigner(digest common.Hash, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, errors.New("invalid signature length")
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
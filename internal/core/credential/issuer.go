@@ -0,0 +1,205 @@
+// Package credential issues and verifies W3C Verifiable Credentials as
+// JWT-VCs signed with the platform's Ed25519 key, published as a did:web
+// document so anyone can verify them.
+package credential
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"strings"
+	"time"
+)
+
+const (
+	// ContextV1 is the JSON-LD context of the VC data model 1.1
+	ContextV1 = "https://www.w3.org/2018/credentials/v1"
+	// ContextPath is where the server publishes the context of its own terms
+	ContextPath = "/credentials/v1"
+
+	// keyFragment names the issuer's key within its DID document
+	keyFragment = "#key-1"
+	// clockSkew is how far ahead of our clock a credential may become valid
+	clockSkew = 5 * time.Minute
+)
+
+// Issuer signs credentials as did:web:<host>
+type Issuer struct {
+	host string
+	did  string
+	key  ed25519.PrivateKey
+}
+
+// NewIssuer uses the Ed25519 key with the given 32-byte seed to issue
+// credentials for the site at host
+func NewIssuer(host string, seed []byte) (*Issuer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("issuer key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	if host == "" {
+		return nil, errors.New("issuer host is required")
+	}
+	return &Issuer{
+		host: host,
+		// did:web percent-encodes a port's colon
+		did: "did:web:" + strings.ReplaceAll(host, ":", "%3A"),
+		key: ed25519.NewKeyFromSeed(seed),
+	}, nil
+}
+
+// DID is the issuer's decentralized identifier
+func (i *Issuer) DID() string {
+	return i.did
+}
+
+// KeyID is the verification method credentials are signed with
+func (i *Issuer) KeyID() string {
+	return i.did + keyFragment
+}
+
+// ContextURL is the JSON-LD context defining the issuer's credential terms
+func (i *Issuer) ContextURL() string {
+	return "https://" + i.host + ContextPath
+}
+
+// Context is the JSON-LD context served at ContextURL. Terms are mapped
+// under one vocabulary, which is all a JWT-VC needs.
+func (i *Issuer) Context() map[string]any {
+	return map[string]any{
+		"@context": map[string]any{
+			"@version": 1.1,
+			"@vocab":   i.ContextURL() + "#",
+		},
+	}
+}
+
+// DIDDocument is the document served at /.well-known/did.json, which
+// resolves did:web:<host> to the issuer's public key
+func (i *Issuer) DIDDocument() map[string]any {
+	return map[string]any{
+		"@context": []string{
+			"https://www.w3.org/ns/did/v1",
+			"https://w3id.org/security/suites/jws-2020/v1",
+		},
+		"id": i.did,
+		"verificationMethod": []map[string]any{{
+			"id":           i.KeyID(),
+			"type":         "JsonWebKey2020",
+			"controller":   i.did,
+			"publicKeyJwk": i.publicJWK(),
+		}},
+		"assertionMethod": []string{i.KeyID()},
+	}
+}
+
+func (i *Issuer) publicJWK() map[string]string {
+	return map[string]string{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"x":   base64.RawURLEncoding.EncodeToString(i.key.Public().(ed25519.PublicKey)),
+	}
+}
+
+// jwtHeader is the JOSE header of issued credentials
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims are the registered claims of a JWT-VC with the credential in vc
+type jwtClaims struct {
+	Issuer    string                      `json:"iss"`
+	ID        string                      `json:"jti"`
+	NotBefore int64                       `json:"nbf"`
+	IssuedAt  int64                       `json:"iat"`
+	VC        domain.VerifiableCredential `json:"vc"`
+}
+
+// Issue completes vc with the issuer and signs it, returning the JWT
+func (i *Issuer) Issue(vc *domain.VerifiableCredential) (string, error) {
+	vc.Issuer = i.did
+	if vc.IssuanceDate.IsZero() {
+		vc.IssuanceDate = time.Now().UTC().Truncate(time.Second)
+	}
+
+	header, err := json.Marshal(jwtHeader{Alg: "EdDSA", Typ: "JWT", Kid: i.KeyID()})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credential header: %w", err)
+	}
+	claims, err := json.Marshal(jwtClaims{
+		Issuer:    i.did,
+		ID:        vc.ID,
+		NotBefore: vc.IssuanceDate.Unix(),
+		IssuedAt:  vc.IssuanceDate.Unix(),
+		VC:        *vc,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credential: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature := ed25519.Sign(i.key, []byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks that token is a credential this issuer signed and that it is
+// already valid. Credentials from other issuers are reported as invalid. It
+// knows nothing of revocation; the credential service checks the stored record.
+func (i *Issuer) Verify(token string) *domain.CredentialVerification {
+	result := &domain.CredentialVerification{}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		result.Reason = "not a JWT"
+		return result
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		result.Reason = "malformed JWT header"
+		return result
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		result.Reason = "malformed JWT claims"
+		return result
+	}
+	result.Issuer = claims.Issuer
+	result.Credential = &claims.VC
+
+	if header.Alg != "EdDSA" {
+		result.Reason = "unsupported signature algorithm " + header.Alg
+		return result
+	}
+	if claims.Issuer != i.did || header.Kid != i.KeyID() {
+		result.Reason = "not issued by " + i.did
+		return result
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(i.key.Public().(ed25519.PublicKey), []byte(parts[0]+"."+parts[1]), signature) {
+		result.Reason = "the signature does not match"
+		return result
+	}
+	if time.Unix(claims.NotBefore, 0).After(time.Now().Add(clockSkew)) {
+		result.Reason = "the credential is not valid yet"
+		return result
+	}
+	if claims.VC.Issuer != claims.Issuer || claims.VC.ID != claims.ID {
+		result.Reason = "the credential does not match its JWT claims"
+		return result
+	}
+
+	result.Valid = true
+	return result
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
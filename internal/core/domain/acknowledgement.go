@@ -14,13 +14,16 @@ const (
 )
 
 type Acknowledgement struct {
-	ID           primitive.ObjectID    `bson:"_id,omitempty"`
-	ExpressionID string                `bson:"expressionId"`
-	Acknowledger string                `bson:"acknowledger"`
-	Content      map[string]string     `bson:"content"`
-	IPFSHash     string                `bson:"ipfsHash"`
-	OnChainID    int                   `bson:"onChainId"`
-	Status       AcknowledgementStatus `bson:"status"`
-	CreatedAt    time.Time             `bson:"createdAt"`
-	UpdatedAt    time.Time             `bson:"updatedAt"`
+	ID               primitive.ObjectID    `bson:"_id,omitempty"`
+	ExpressionID     string                `bson:"expressionId"`
+	Acknowledger     string                `bson:"acknowledger"`
+	Content          map[string]string     `bson:"content"`
+	IPFSHash         string                `bson:"ipfsHash"`
+	OnChainID        int                   `bson:"onChainId"`
+	Status           AcknowledgementStatus `bson:"status"`
+	ModerationStatus ModerationStatus      `bson:"moderationStatus,omitempty"`
+	// Attestation is the acknowledger's signature, if they signed
+	Attestation *AcknowledgementAttestation `bson:"attestation,omitempty"`
+	CreatedAt   time.Time                   `bson:"createdAt"`
+	UpdatedAt   time.Time                   `bson:"updatedAt"`
 }
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnchorKind names the collection an anchored record comes from
+type AnchorKind string
+
+const (
+	AnchorExpression      AnchorKind = "expression"
+	AnchorAcknowledgement AnchorKind = "acknowledgement"
+)
+
+// AnchorStatus tracks a batch's Merkle root on its way on-chain
+type AnchorStatus string
+
+const (
+	// AnchorStatusPending means the root waits to be sent, or to be sent again after a failure
+	AnchorStatusPending AnchorStatus = "pending"
+	// AnchorStatusSubmitted means the anchorRoot transaction has been sent
+	AnchorStatusSubmitted AnchorStatus = "submitted"
+	// AnchorStatusConfirmed means the root is on-chain
+	AnchorStatusConfirmed AnchorStatus = "confirmed"
+)
+
+// AnchorBatch is one Merkle tree over the expressions and acknowledgements
+// created in (WindowStart, WindowEnd]. Only its root goes on-chain; each
+// record keeps its own proof.
+type AnchorBatch struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Root        string              `bson:"root" json:"root"`
+	LeafCount   int                 `bson:"leafCount" json:"leafCount"`
+	WindowStart time.Time           `bson:"windowStart" json:"windowStart"`
+	WindowEnd   time.Time           `bson:"windowEnd" json:"windowEnd"`
+	Status      AnchorStatus        `bson:"status" json:"status"`
+	RelayID     *primitive.ObjectID `bson:"relayId,omitempty" json:"relayId,omitempty"`
+	TxHash      string              `bson:"txHash,omitempty" json:"txHash,omitempty"`
+	BlockNumber uint64              `bson:"blockNumber,omitempty" json:"blockNumber,omitempty"`
+	// Error is why the last attempt to anchor the root failed
+	Error     string    `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// AnchorProof is one record's leaf and its path to the batch root
+type AnchorProof struct {
+	DocumentID  string             `bson:"documentId" json:"documentId"`
+	Kind        AnchorKind         `bson:"kind" json:"kind"`
+	BatchID     primitive.ObjectID `bson:"batchId" json:"batchId"`
+	ContentHash string             `bson:"contentHash" json:"contentHash"`
+	Creator     string             `bson:"creator" json:"creator"`
+	// Timestamp is the record's creation time in Unix seconds
+	Timestamp int64    `bson:"timestamp" json:"timestamp"`
+	Leaf      string   `bson:"leaf" json:"leaf"`
+	Proof     []string `bson:"proof" json:"proof"`
+}
+
+// InclusionProof is what a client needs to check a record against the chain:
+// rebuild the leaf, follow Proof to Root, and look Root up on Contract
+type InclusionProof struct {
+	AnchorProof
+	Root        string       `json:"root"`
+	Status      AnchorStatus `json:"status"`
+	TxHash      string       `json:"txHash,omitempty"`
+	BlockNumber uint64       `json:"blockNumber,omitempty"`
+	ChainID     int64        `json:"chainId"`
+	Contract    string       `json:"contract"`
+	// Intact reports whether the record as stored now still hashes to Leaf
+	Intact bool `json:"intact"`
+}
+
+// ErrNotAnchored is returned for a record not yet in any batch
+var ErrNotAnchored = NotFound("this record has not been anchored yet")
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APITokenScope limits what a personal access token may do
+type APITokenScope string
+
+const (
+	// APITokenScopeRead allows read-only requests
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeExpressionsWrite allows creating expressions
+	APITokenScopeExpressionsWrite APITokenScope = "expressions:write"
+	// APITokenScopeAcknowledgementsWrite allows creating acknowledgements
+	APITokenScopeAcknowledgementsWrite APITokenScope = "acknowledgements:write"
+)
+
+// APITokenPrefix marks personal access tokens so they are recognisable in logs and secret scanners
+const APITokenPrefix = "pop_"
+
+var (
+	// ErrAPITokenNotFound is returned when a token does not exist or belongs to another user
+	ErrAPITokenNotFound = NotFound("api token not found")
+	// ErrInvalidAPITokenScope is returned when a token is requested with an unknown or empty scope list
+	ErrInvalidAPITokenScope = Validation("scopes must be one or more of read, expressions:write, acknowledgements:write")
+)
+
+// APIToken is a personal access token for programmatic access to the API.
+// Only the hash of the token is stored; the plaintext is shown once at creation.
+type APIToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
+	Name       string             `bson:"name" json:"name"`
+	TokenHash  string             `bson:"tokenHash" json:"-"`
+	Hint       string             `bson:"hint" json:"hint"` // Last characters of the token, for identification
+	Scopes     []APITokenScope    `bson:"scopes" json:"scopes"`
+	ExpiresAt  time.Time          `bson:"expiresAt" json:"expiresAt"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ValidAPITokenScope reports whether scope is one of the known scopes
+func ValidAPITokenScope(scope APITokenScope) bool {
+	switch scope {
+	case APITokenScopeRead, APITokenScopeExpressionsWrite, APITokenScopeAcknowledgementsWrite:
+		return true
+	}
+	return false
+}
+
+// HasScope reports whether the token grants scope
+func (t *APIToken) HasScope(scope APITokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the token is neither revoked nor expired
+func (t *APIToken) IsActive() bool {
+	return t.RevokedAt == nil && time.Now().Before(t.ExpiresAt)
+}
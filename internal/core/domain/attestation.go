@@ -0,0 +1,58 @@
+package domain
+
+// AcknowledgementAttestation is an acknowledger's EIP-712 signature over
+// their acknowledgement. It lets anyone check, without trusting this
+// platform, that the holder of Signer's key acknowledged the expression with
+// this content and status at Timestamp.
+type AcknowledgementAttestation struct {
+	// Signer is the acknowledger's wallet address
+	Signer string `bson:"signer" json:"signer"`
+	// ContentHash is ContentHash of the acknowledgement's content
+	ContentHash string `bson:"contentHash" json:"contentHash"`
+	// Timestamp is when the acknowledger signed, in Unix seconds
+	Timestamp int64  `bson:"timestamp" json:"timestamp"`
+	Signature string `bson:"signature" json:"signature"`
+}
+
+// AttestationMessage is the typed data an acknowledger signs
+type AttestationMessage struct {
+	ExpressionID string                `json:"expressionId"`
+	Acknowledger string                `json:"acknowledger"`
+	ContentHash  string                `json:"contentHash"`
+	Status       AcknowledgementStatus `json:"status"`
+	Timestamp    int64                 `json:"timestamp"`
+}
+
+// AttestationDomain is the EIP-712 domain attestations are signed under. It
+// names no chain or contract, as attestations are checked off-chain.
+type AttestationDomain struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// AttestationVerification is the outcome of checking a signed attestation
+type AttestationVerification struct {
+	Valid     bool               `json:"valid"`
+	Message   AttestationMessage `json:"message"`
+	Signature string             `json:"signature"`
+	// Digest is the EIP-712 hash that was signed
+	Digest string `json:"digest,omitempty"`
+	// Signer is the address recovered from the signature
+	Signer string `json:"signer,omitempty"`
+	// Reason says why an attestation is not valid
+	Reason string `json:"reason,omitempty"`
+	// AcknowledgementID is set when the attestation is checked against a
+	// stored acknowledgement
+	AcknowledgementID string `json:"acknowledgementId,omitempty"`
+}
+
+var (
+	// ErrAttestationNeedsWallet is returned when an account without a wallet sends a signed acknowledgement
+	ErrAttestationNeedsWallet = Validation("signing an acknowledgement requires a connected wallet")
+	// ErrAttestationInvalid is returned when a signature does not match the acknowledgement it came with
+	ErrAttestationInvalid = Validation("the signature does not match this acknowledgement")
+	// ErrAttestationFromFuture is returned for a signing time ahead of the server's clock
+	ErrAttestationFromFuture = Validation("the attestation timestamp is in the future")
+	// ErrAttestationNotFound is returned for an acknowledgement that was not signed
+	ErrAttestationNotFound = NotFound("acknowledgement has no signed attestation")
+)
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// CertificateFormat is the file type a certificate is rendered as
+type CertificateFormat string
+
+const (
+	CertificatePDF CertificateFormat = "pdf"
+	CertificatePNG CertificateFormat = "png"
+)
+
+// CertificateParty is one of the two people named on a certificate
+type CertificateParty struct {
+	Name string
+	// Role is "creator" or "acknowledger"
+	Role string
+	// Country is the party's citizenship as an ISO 3166 code, if known
+	Country     string
+	CountryName string
+	Flag        string
+}
+
+// Certificate is what a printable Proof of Peacemaking shows. It is made for
+// the same records a credential is, named by CredentialSource.
+type Certificate struct {
+	Source   CredentialSource
+	SourceID string
+	Parties  []CertificateParty
+	// Excerpt is the start of the expression's text, if it has any
+	Excerpt  string
+	TokenID  int
+	IPFSHash string
+	// Date is when the NFT was minted or the request accepted
+	Date time.Time
+	// VerifyURL is the public page the QR code links to
+	VerifyURL string
+}
+
+// ContentType is the MIME type of a rendered certificate
+func (f CertificateFormat) ContentType() string {
+	if f == CertificatePNG {
+		return "image/png"
+	}
+	return "application/pdf"
+}
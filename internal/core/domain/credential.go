@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CredentialSource is the kind of record a credential attests to
+type CredentialSource string
+
+const (
+	CredentialSourceProofNFT     CredentialSource = "proofNft"
+	CredentialSourceProofRequest CredentialSource = "proofRequest"
+)
+
+// CredentialParty is one of the two people a Proof of Peacemaking names
+type CredentialParty struct {
+	// ID is the party's did:pkh, for parties with a wallet
+	ID   string `bson:"id,omitempty" json:"id,omitempty"`
+	Name string `bson:"name,omitempty" json:"name,omitempty"`
+	// Role is "creator" for the expression's author and "acknowledger" for the other party
+	Role string `bson:"role" json:"role"`
+}
+
+// CredentialExpression identifies the expression a proof is about
+type CredentialExpression struct {
+	ID          string `bson:"id" json:"id"`
+	ContentHash string `bson:"contentHash" json:"contentHash"`
+}
+
+// PeacemakingSubject is the credentialSubject of a Proof of Peacemaking
+// credential
+type PeacemakingSubject struct {
+	Type       string               `bson:"type" json:"type"`
+	Parties    []CredentialParty    `bson:"parties" json:"parties"`
+	Expression CredentialExpression `bson:"expression" json:"expression"`
+	// Date is when the NFT was minted or the request accepted
+	Date    time.Time `bson:"date" json:"date"`
+	TokenID int       `bson:"tokenId,omitempty" json:"tokenId,omitempty"`
+}
+
+// VerifiableCredential is a W3C Verifiable Credential (data model 1.1) as
+// carried in the vc claim of a JWT-VC
+type VerifiableCredential struct {
+	Context           []string           `bson:"context" json:"@context"`
+	ID                string             `bson:"id" json:"id"`
+	Type              []string           `bson:"type" json:"type"`
+	Issuer            string             `bson:"issuer" json:"issuer"`
+	IssuanceDate      time.Time          `bson:"issuanceDate" json:"issuanceDate"`
+	CredentialSubject PeacemakingSubject `bson:"credentialSubject" json:"credentialSubject"`
+}
+
+// Credential is an issued credential with the JWT that proves it
+type Credential struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Source   CredentialSource   `bson:"source" json:"source"`
+	SourceID string             `bson:"sourceId" json:"sourceId"`
+	// HolderIDs are the user IDs of both parties, who may each download it
+	HolderIDs  []string             `bson:"holderIds" json:"-"`
+	Credential VerifiableCredential `bson:"credential" json:"credential"`
+	JWT        string               `bson:"jwt" json:"jwt"`
+	IssuedAt   time.Time            `bson:"issuedAt" json:"issuedAt"`
+}
+
+// HeldBy reports whether userID is one of the credential's parties
+func (c *Credential) HeldBy(userID string) bool {
+	for _, holder := range c.HolderIDs {
+		if holder == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CredentialVerification is the outcome of checking a JWT-VC
+type CredentialVerification struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	// Credential is the decoded credential, set whenever the JWT could be read
+	Credential *VerifiableCredential `json:"credential,omitempty"`
+}
+
+var (
+	// ErrCredentialNotEligible is returned for a proof that has not been minted or accepted
+	ErrCredentialNotEligible = Validation("credentials are only issued for minted proof NFTs and accepted proof requests")
+	// ErrCredentialNotParty is returned when someone other than the two parties asks for a credential
+	ErrCredentialNotParty = Forbidden("only the two parties of a proof can get its credential")
+	// ErrCredentialNotFound is returned for an unknown credential, or one the caller does not hold
+	ErrCredentialNotFound = NotFound("credential not found")
+)
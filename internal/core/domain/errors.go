@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Every domain error belongs to one kind, which adapters use to
+// choose a response, e.g. ErrNotFound becomes HTTP 404. Match them with
+// errors.Is; they survive wrapping with fmt.Errorf("...: %w", err).
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is a domain error of one kind. Its message is written for the person
+// making the request; the cause, if any, is for logs only.
+type Error struct {
+	kind    error
+	message string
+	cause   error
+}
+
+func newError(kind, cause error, format string, args ...any) *Error {
+	return &Error{kind: kind, message: fmt.Sprintf(format, args...), cause: cause}
+}
+
+// NotFound reports that the requested thing does not exist
+func NotFound(format string, args ...any) error {
+	return newError(ErrNotFound, nil, format, args...)
+}
+
+// Conflict reports that the request clashes with the current state
+func Conflict(format string, args ...any) error {
+	return newError(ErrConflict, nil, format, args...)
+}
+
+// Validation reports that the input is malformed or out of range
+func Validation(format string, args ...any) error {
+	return newError(ErrValidation, nil, format, args...)
+}
+
+// Forbidden reports that the caller may not do this
+func Forbidden(format string, args ...any) error {
+	return newError(ErrForbidden, nil, format, args...)
+}
+
+// Unauthorized reports that the caller could not be authenticated
+func Unauthorized(format string, args ...any) error {
+	return newError(ErrUnauthorized, nil, format, args...)
+}
+
+// WrapError gives cause a kind and a user-facing message, keeping cause
+// reachable through errors.Is and errors.As
+func WrapError(kind, cause error, format string, args ...any) error {
+	return newError(kind, cause, format, args...)
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.message + ": " + e.cause.Error()
+	}
+	return e.message
+}
+
+// Message is the description safe to show to users, without the cause
+func (e *Error) Message() string {
+	return e.message
+}
+
+// Kind returns the kind sentinel, such as ErrNotFound
+func (e *Error) Kind() error {
+	return e.kind
+}
+
+func (e *Error) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.kind}
+	}
+	return []error{e.kind, e.cause}
+}
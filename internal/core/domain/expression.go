@@ -22,6 +22,8 @@ type Expression struct {
 	IPFSHash                   string                   `bson:"ipfsHash"`
 	OnChainID                  int                      `bson:"onChainId"`
 	Status                     string                   `bson:"status"`
+	ModerationStatus           ModerationStatus         `bson:"moderationStatus,omitempty"`
+	Locale                     string                   `bson:"locale,omitempty"`
 	Acknowledgements           []*Acknowledgement       `bson:"-"`
 	IsAcknowledged             bool                     `bson:"-"`
 	ActiveAcknowledgementCount int                      `bson:"-"`
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NFTStatus is where a pairing is in the proof NFT flow
+type NFTStatus string
+
+const (
+	NFTStatusNone      NFTStatus = "NONE"
+	NFTStatusRequested NFTStatus = "REQUESTED"
+	NFTStatusApproved  NFTStatus = "APPROVED"
+	NFTStatusMinting   NFTStatus = "MINTING"
+	NFTStatusMinted    NFTStatus = "MINTED"
+	NFTStatusFailed    NFTStatus = "FAILED"
+)
+
+// ExpressionAcknowledgement pairs an expression with one acknowledgement of
+// it. It is the record proof requests and proof NFTs hang off, and the one
+// place to read where a pair of peacemakers stands.
+type ExpressionAcknowledgement struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ExpressionID      string             `bson:"expressionId" json:"expressionId"`
+	AcknowledgementID string             `bson:"acknowledgementId" json:"acknowledgementId"`
+	// CreatorID and AcknowledgerID are the two parties, copied from the
+	// expression and acknowledgement so pairings can be listed per user
+	CreatorID      string `bson:"creatorId" json:"creatorId"`
+	AcknowledgerID string `bson:"acknowledgerId" json:"acknowledgerId"`
+	// AcknowledgementStatus follows the acknowledgement as it is refuted and restored
+	AcknowledgementStatus AcknowledgementStatus `bson:"acknowledgementStatus" json:"acknowledgementStatus"`
+
+	NFTStatus      NFTStatus  `bson:"nftStatus" json:"nftStatus"`
+	NFTRequestedBy string     `bson:"nftRequestedBy,omitempty" json:"nftRequestedBy,omitempty"`
+	NFTRequestedAt *time.Time `bson:"nftRequestedAt,omitempty" json:"nftRequestedAt,omitempty"`
+	NFTApprovedBy  string     `bson:"nftApprovedBy,omitempty" json:"nftApprovedBy,omitempty"`
+	NFTApprovedAt  *time.Time `bson:"nftApprovedAt,omitempty" json:"nftApprovedAt,omitempty"`
+	// ProofRequestID is the open or accepted proof request, if any
+	ProofRequestID string `bson:"proofRequestId,omitempty" json:"proofRequestId,omitempty"`
+	// ProofNFTID is the NFT created once the request was accepted
+	ProofNFTID string `bson:"proofNftId,omitempty" json:"proofNftId,omitempty"`
+
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// HasParty reports whether userID is the expression's creator or its acknowledger
+func (p *ExpressionAcknowledgement) HasParty(userID string) bool {
+	return userID == p.CreatorID || userID == p.AcknowledgerID
+}
+
+// OtherParty returns the party who is not userID
+func (p *ExpressionAcknowledgement) OtherParty(userID string) string {
+	if userID == p.CreatorID {
+		return p.AcknowledgerID
+	}
+	return p.CreatorID
+}
+
+var (
+	// ErrPairingNotFound is returned for an acknowledgement with no pairing
+	ErrPairingNotFound = NotFound("acknowledgement not found")
+	// ErrPairingNotParty is returned when someone other than the two parties acts on a pairing
+	ErrPairingNotParty = Forbidden("only the expression's creator and its acknowledger can request a proof")
+	// ErrPairingRefuted is returned when a proof is requested for a refuted acknowledgement
+	ErrPairingRefuted = Validation("a proof cannot be requested for a refuted acknowledgement")
+	// ErrPairingNFTStatus is returned when a pairing is not in the NFT status an action needs,
+	// including when two requests race
+	ErrPairingNFTStatus = Conflict("the proof NFT is not in a state that allows this")
+)
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvitationStatus is where a directed invitation stands
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "PENDING"
+	InvitationAccepted InvitationStatus = "ACCEPTED"
+	InvitationExpired  InvitationStatus = "EXPIRED"
+)
+
+// InviteeKind says how the creator named the person they invited
+type InviteeKind string
+
+const (
+	InviteeUsername InviteeKind = "username"
+	InviteeEmail    InviteeKind = "email"
+	InviteeAddress  InviteeKind = "address"
+)
+
+// InvitationMessageMaxLength caps the creator's personal note
+const InvitationMessageMaxLength = 500
+
+// Invitation asks one person, for example the other side of a reconciliation,
+// to acknowledge an expression. It reaches them as a signed link, which walks
+// people without an account through registration.
+type Invitation struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ExpressionID string             `bson:"expressionId" json:"expressionId"`
+	CreatorID    string             `bson:"creatorId" json:"creatorId"`
+	// Invitee is the username, lower-cased email or lower-cased wallet address the creator gave
+	Invitee     string      `bson:"invitee" json:"invitee"`
+	InviteeKind InviteeKind `bson:"inviteeKind" json:"inviteeKind"`
+	// InviteeID is the invitee's account, once they have one
+	InviteeID string           `bson:"inviteeId,omitempty" json:"inviteeId,omitempty"`
+	Message   string           `bson:"message,omitempty" json:"message,omitempty"`
+	Status    InvitationStatus `bson:"status" json:"status"`
+	// Link is the signed invite link, filled in for the creator only
+	Link       string     `bson:"-" json:"link,omitempty"`
+	ExpiresAt  time.Time  `bson:"expiresAt" json:"expiresAt"`
+	AcceptedAt *time.Time `bson:"acceptedAt,omitempty" json:"acceptedAt,omitempty"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time  `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Lapsed reports whether a pending invitation is past its expiry
+func (i *Invitation) Lapsed(now time.Time) bool {
+	return i.Status == InvitationPending && !now.Before(i.ExpiresAt)
+}
+
+// IsFor reports whether user is the person the invitation was sent to.
+// Account emails are not verified, so an email invitation is only for the
+// account with its email when that account holds the emailed link; callers
+// check IsFor once the link is verified.
+func (i *Invitation) IsFor(user *User) bool {
+	if i.InviteeID != "" {
+		return i.InviteeID == user.ID.Hex()
+	}
+	switch i.InviteeKind {
+	case InviteeEmail:
+		return user.Email != "" && strings.EqualFold(user.Email, i.Invitee)
+	case InviteeAddress:
+		return user.Address != "" && strings.EqualFold(user.Address, i.Invitee)
+	case InviteeUsername:
+		return user.Username != "" && strings.EqualFold(user.Username, i.Invitee)
+	}
+	return false
+}
+
+var (
+	// ErrInvitationNotFound is returned for unknown invitations and links that fail verification
+	ErrInvitationNotFound = NotFound("invitation not found")
+	// ErrInvitationNotCreator is returned when someone invites people to another user's expression
+	ErrInvitationNotCreator = Forbidden("only the expression's creator can invite people to acknowledge it")
+	// ErrInvitationSelf is returned when creators invite themselves
+	ErrInvitationSelf = Validation("you cannot invite yourself to acknowledge your own expression")
+	// ErrInvitationDuplicate is returned when the invitee already has a pending invitation to the expression
+	ErrInvitationDuplicate = Conflict("this person already has a pending invitation to the expression")
+	// ErrInvitationNotInvitee is returned when someone other than the invitee opens the link
+	ErrInvitationNotInvitee = Forbidden("this invitation was sent to someone else")
+	// ErrInvitationExpired is returned when a lapsed invitation is accepted
+	ErrInvitationExpired = Conflict("this invitation has expired")
+	// ErrInvitationNotPending is returned when an invitation was accepted or expired in the meantime
+	ErrInvitationNotPending = Conflict("this invitation is no longer pending")
+)
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModerationStatus controls whether a piece of content is publicly visible.
+// Content without a status predates moderation and is visible.
+type ModerationStatus string
+
+const (
+	ModerationStatusVisible       ModerationStatus = "VISIBLE"
+	ModerationStatusHidden        ModerationStatus = "HIDDEN"
+	ModerationStatusPendingReview ModerationStatus = "PENDING_REVIEW"
+)
+
+// IsPubliclyVisible reports whether content with this status may be shown in public listings
+func (s ModerationStatus) IsPubliclyVisible() bool {
+	return s == "" || s == ModerationStatusVisible
+}
+
+// ContentType identifies the kind of content a report or case refers to
+type ContentType string
+
+const (
+	ContentTypeExpression      ContentType = "expression"
+	ContentTypeAcknowledgement ContentType = "acknowledgement"
+	ContentTypeReply           ContentType = "reply"
+)
+
+// ReportReason is the taxonomy users pick from when reporting content
+type ReportReason string
+
+const (
+	ReportReasonHateSpeech     ReportReason = "hate_speech"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonViolence       ReportReason = "violence"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonPrivacy        ReportReason = "privacy"
+	ReportReasonOther          ReportReason = "other"
+)
+
+// ValidReportReason reports whether reason is part of the taxonomy
+func ValidReportReason(reason ReportReason) bool {
+	switch reason {
+	case ReportReasonHateSpeech, ReportReasonHarassment, ReportReasonViolence,
+		ReportReasonMisinformation, ReportReasonSpam, ReportReasonPrivacy, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// Report is a single user's complaint about a piece of content
+type Report struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CaseID      primitive.ObjectID `bson:"caseId" json:"caseId"`
+	ContentType ContentType        `bson:"contentType" json:"contentType"`
+	ContentID   string             `bson:"contentId" json:"contentId"`
+	ReporterID  primitive.ObjectID `bson:"reporterId" json:"reporterId"`
+	Reason      ReportReason       `bson:"reason" json:"reason"`
+	Details     string             `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// CaseStatus is the position of a moderation case in the queue
+type CaseStatus string
+
+const (
+	CaseStatusOpen      CaseStatus = "OPEN"
+	CaseStatusClaimed   CaseStatus = "CLAIMED"
+	CaseStatusEscalated CaseStatus = "ESCALATED"
+	CaseStatusResolved  CaseStatus = "RESOLVED"
+)
+
+// ModerationCase groups all open reports about one piece of content so it is reviewed once
+type ModerationCase struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ContentType ContentType         `bson:"contentType" json:"contentType"`
+	ContentID   string              `bson:"contentId" json:"contentId"`
+	AuthorID    string              `bson:"authorId" json:"authorId"`
+	Status      CaseStatus          `bson:"status" json:"status"`
+	Reasons     []ReportReason      `bson:"reasons" json:"reasons"`
+	ReportCount int                 `bson:"reportCount" json:"reportCount"`
+	Source      string              `bson:"source,omitempty" json:"source,omitempty"` // Who opened the case when it was not a user report
+	ClaimedBy   *primitive.ObjectID `bson:"claimedBy,omitempty" json:"claimedBy,omitempty"`
+	Resolution  ModerationAction    `bson:"resolution,omitempty" json:"resolution,omitempty"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt"`
+	ResolvedAt  *time.Time          `bson:"resolvedAt,omitempty" json:"resolvedAt,omitempty"`
+}
+
+// ModerationAction is something a moderator does to content or its author
+type ModerationAction string
+
+const (
+	ModerationActionReport   ModerationAction = "report"
+	ModerationActionClaim    ModerationAction = "claim"
+	ModerationActionEscalate ModerationAction = "escalate"
+	ModerationActionHide     ModerationAction = "hide"
+	ModerationActionRestore  ModerationAction = "restore"
+	ModerationActionWarn     ModerationAction = "warn_user"
+	ModerationActionSuspend  ModerationAction = "suspend_user"
+	ModerationActionDismiss  ModerationAction = "dismiss"
+	ModerationActionReopen   ModerationAction = "reopen"
+)
+
+// ModerationLogEntry is an immutable record of a moderation event. Entries are
+// only ever appended, never updated or deleted.
+type ModerationLogEntry struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CaseID      primitive.ObjectID `bson:"caseId" json:"caseId"`
+	ContentType ContentType        `bson:"contentType" json:"contentType"`
+	ContentID   string             `bson:"contentId" json:"contentId"`
+	ActorID     primitive.ObjectID `bson:"actorId,omitempty" json:"actorId,omitempty"`
+	Action      ModerationAction   `bson:"action" json:"action"`
+	Note        string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+var (
+	// ErrContentNotFound is returned when reported content does not exist
+	ErrContentNotFound = NotFound("content not found")
+	// ErrInvalidReportReason is returned when a report uses a reason outside the taxonomy
+	ErrInvalidReportReason = Validation("invalid report reason")
+	// ErrAlreadyReported is returned when a user reports the same content twice
+	ErrAlreadyReported = Conflict("you have already reported this content")
+	// ErrCaseNotFound is returned when a moderation case does not exist
+	ErrCaseNotFound = NotFound("moderation case not found")
+	// ErrInvalidCaseTransition is returned when a case cannot move to the requested state
+	ErrInvalidCaseTransition = Conflict("invalid moderation case transition")
+	// ErrCaseNotClaimed is returned when a moderator acts on a case they have not claimed
+	ErrCaseNotClaimed = Forbidden("moderation case is not claimed by you")
+	// ErrUserSuspended is returned when a suspended user tries to post content
+	ErrUserSuspended = Forbidden("account is suspended")
+)
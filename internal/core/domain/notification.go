@@ -17,6 +17,11 @@ const (
 	NotificationAcknowledgementConfirmed NotificationType = "ACKNOWLEDGEMENT_CONFIRMED"
 	NotificationProofRequestAccepted     NotificationType = "PROOF_REQUEST_ACCEPTED"
 	NotificationProofRequestRejected     NotificationType = "PROOF_REQUEST_REJECTED"
+	NotificationSecurityAlert            NotificationType = "SECURITY_ALERT"
+	NotificationModerationNotice         NotificationType = "MODERATION_NOTICE"
+	NotificationInvitationReceived       NotificationType = "INVITATION_RECEIVED"
+	NotificationInvitationAccepted       NotificationType = "INVITATION_ACCEPTED"
+	NotificationNewReply                 NotificationType = "NEW_REPLY"
 )
 
 type Notification struct {
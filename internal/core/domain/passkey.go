@@ -29,3 +29,36 @@ type UserPasskey struct {
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
+
+var (
+	// ErrPasskeyNotFound is returned when a passkey does not exist or belongs to another user
+	ErrPasskeyNotFound = NotFound("passkey not found")
+	// ErrLastLoginMethod is returned when revoking a passkey would leave the user unable to log in
+	ErrLastLoginMethod = Conflict("cannot remove the last login method")
+	// ErrSecondFactorRequired is returned when a passkey login is refused pending another login method
+	ErrSecondFactorRequired = Forbidden("passkey flagged, please sign in with another method")
+	// ErrPasskeyDeactivated is returned when a passkey was deactivated during login
+	ErrPasskeyDeactivated = Forbidden("passkey has been deactivated")
+)
+
+// SignCountPolicy decides what happens when a passkey's signature counter fails to increase
+type SignCountPolicy string
+
+const (
+	// SignCountPolicyWarn records the event and notifies the user but allows the login
+	SignCountPolicyWarn SignCountPolicy = "warn"
+	// SignCountPolicyRequireSecondFactor refuses the passkey login so the user must use a password or wallet
+	SignCountPolicyRequireSecondFactor SignCountPolicy = "require_second_factor"
+	// SignCountPolicyDeactivate deactivates the passkey and refuses the login
+	SignCountPolicyDeactivate SignCountPolicy = "deactivate"
+)
+
+// ParseSignCountPolicy converts a configuration value to a SignCountPolicy, defaulting to warn
+func ParseSignCountPolicy(value string) SignCountPolicy {
+	switch SignCountPolicy(value) {
+	case SignCountPolicyRequireSecondFactor, SignCountPolicyDeactivate:
+		return SignCountPolicy(value)
+	default:
+		return SignCountPolicyWarn
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 type ProofNFT struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	PairingID    string             `bson:"expressionAcknowledgementId,omitempty"`
 	TokenID      int                `bson:"tokenId"`
 	Expression   string             `bson:"expressionId"`
 	Acknowledger string             `bson:"acknowledgerId"`
@@ -15,22 +16,47 @@ type ProofNFT struct {
 	Status       string             `bson:"status"`
 	CreatedAt    time.Time          `bson:"createdAt"`
 	MintedAt     *time.Time         `bson:"mintedAt,omitempty"`
+	// RelayID is the relayed transaction minting the NFT, once sent
+	RelayID *primitive.ObjectID `bson:"relayId,omitempty"`
+	TxHash  string              `bson:"txHash,omitempty"`
+	// Error is why minting failed
+	Error string `bson:"error,omitempty"`
 }
 
+// Proof NFT statuses
+const (
+	ProofNFTStatusPending = "PENDING"
+	ProofNFTStatusMinted  = "MINTED"
+	ProofNFTStatusFailed  = "FAILED"
+)
+
 type ProofRequestStatus string
 
 const (
-	ProofRequestPending  ProofRequestStatus = "PENDING"
-	ProofRequestAccepted ProofRequestStatus = "ACCEPTED"
-	ProofRequestRejected ProofRequestStatus = "REJECTED"
+	ProofRequestPending   ProofRequestStatus = "PENDING"
+	ProofRequestAccepted  ProofRequestStatus = "ACCEPTED"
+	ProofRequestRejected  ProofRequestStatus = "REJECTED"
+	ProofRequestCancelled ProofRequestStatus = "CANCELLED"
 )
 
 type ProofRequest struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty"`
-	ExpressionID string             `bson:"expressionId"`
-	InitiatorID  string             `bson:"initiatorId"`
-	PeerID       string             `bson:"peerId"`
-	Status       ProofRequestStatus `bson:"status"`
-	CreatedAt    time.Time          `bson:"createdAt"`
-	UpdatedAt    time.Time          `bson:"updatedAt"`
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PairingID    string             `bson:"expressionAcknowledgementId" json:"expressionAcknowledgementId"`
+	ExpressionID string             `bson:"expressionId" json:"expressionId"`
+	InitiatorID  string             `bson:"initiatorId" json:"initiatorId"`
+	PeerID       string             `bson:"peerId" json:"peerId"`
+	Status       ProofRequestStatus `bson:"status" json:"status"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
+
+var (
+	// ErrProofRequestNotFound is returned for an unknown proof request
+	ErrProofRequestNotFound = NotFound("proof request not found")
+	// ErrProofRequestNotPending is returned when a request was already answered or withdrawn
+	ErrProofRequestNotPending = Conflict("the proof request is no longer pending")
+	// ErrProofRequestNotPeer is returned when someone other than the requested party answers a request
+	ErrProofRequestNotPeer = Forbidden("only the other party can answer a proof request")
+	// ErrProofRequestNotInitiator is returned when someone other than the requester withdraws a request
+	ErrProofRequestNotInitiator = Forbidden("only the requester can cancel a proof request")
+)
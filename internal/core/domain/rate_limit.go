@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// RateLimitPolicy caps how often one subject (an IP or a user) may hit a group of routes
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+var (
+	// RateLimitLogin covers password, signature and passkey sign-ins and email sign-ups
+	RateLimitLogin = RateLimitPolicy{Name: "login", Limit: 10, Window: 15 * time.Minute}
+	// RateLimitNonce covers nonce generation, which creates a user record per new address
+	RateLimitNonce = RateLimitPolicy{Name: "nonce", Limit: 20, Window: time.Minute}
+	// RateLimitExpressionCreate covers publishing new expressions
+	RateLimitExpressionCreate = RateLimitPolicy{Name: "expression_create", Limit: 10, Window: time.Hour}
+	// RateLimitAcknowledgement covers acknowledging expressions
+	RateLimitAcknowledgement = RateLimitPolicy{Name: "acknowledgement", Limit: 60, Window: time.Hour}
+	// RateLimitRelay covers relay requests, each of which the operator pays gas for
+	RateLimitRelay = RateLimitPolicy{Name: "relay", Limit: 30, Window: time.Hour}
+	// RateLimitAttestationVerify covers the public attestation check, which recovers a signature per call
+	RateLimitAttestationVerify = RateLimitPolicy{Name: "attestation_verify", Limit: 120, Window: time.Minute}
+	// RateLimitCredentialVerify covers the public credential check, which verifies a signature per call
+	RateLimitCredentialVerify = RateLimitPolicy{Name: "credential_verify", Limit: 120, Window: time.Minute}
+	// RateLimitInvitation covers invitations, each of which may send an email
+	RateLimitInvitation = RateLimitPolicy{Name: "invitation", Limit: 20, Window: time.Hour}
+	// RateLimitReply covers replies in acknowledgement dialogues
+	RateLimitReply = RateLimitPolicy{Name: "reply", Limit: 60, Window: time.Hour}
+)
+
+// RateLimitDecision is the outcome of counting one request against a policy
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RetryAfter returns how long the caller should wait before the window resets
+func (d *RateLimitDecision) RetryAfter() time.Duration {
+	wait := time.Until(d.ResetAt)
+	if wait < time.Second {
+		return time.Second
+	}
+	return wait
+}
+
+// LockoutPolicy locks an account after repeated failed sign-ins. Each failure
+// past the threshold doubles the lock, up to MaxDelay. Failures are counted
+// over FailureWindow and cleared when the user signs in.
+type LockoutPolicy struct {
+	Threshold     int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	FailureWindow time.Duration
+}
+
+// DefaultLockoutPolicy locks for 1 minute after 5 failures, growing to at most an hour
+var DefaultLockoutPolicy = LockoutPolicy{
+	Threshold:     5,
+	BaseDelay:     time.Minute,
+	MaxDelay:      time.Hour,
+	FailureWindow: 24 * time.Hour,
+}
+
+// Delay returns how long to lock the account after its nth consecutive failure
+func (p LockoutPolicy) Delay(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.Threshold; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// ErrAccountLocked is returned when sign-in is attempted on a locked account
+var ErrAccountLocked = errors.New("too many failed attempts, account temporarily locked")
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelayOperation is a Diamond function the relayer sends for users
+type RelayOperation string
+
+const (
+	RelayCreateExpression RelayOperation = "createExpression"
+	RelayAcknowledge      RelayOperation = "acknowledge"
+	// RelaySetOperatorSubsidies is sent by the operator itself, not for a user
+	RelaySetOperatorSubsidies RelayOperation = "setOperatorSubsidies"
+	// RelayAnchorRoot commits a Merkle root over off-chain records, also sent by the operator
+	RelayAnchorRoot RelayOperation = "anchorRoot"
+	// RelayMintProof mints the proof NFT of an approved proof request, also sent by the operator
+	RelayMintProof RelayOperation = "mint"
+	// RelaySetOperatorStatus switches an operator on or off, sent by the Diamond's owner
+	RelaySetOperatorStatus RelayOperation = "setOperatorStatus"
+)
+
+// Subsidy returns the entry in User.SubsidizedOps that entitles a user to
+// have op relayed, or "" for an operation users cannot request
+func (op RelayOperation) Subsidy() string {
+	switch op {
+	case RelayCreateExpression:
+		return SubsidyExpression
+	case RelayAcknowledge:
+		return SubsidyAcknowledgement
+	}
+	return ""
+}
+
+var (
+	// ErrRelayNotSubsidized is returned when the user is not entitled to have the operation paid for
+	ErrRelayNotSubsidized = Forbidden("operation is not subsidized for this account")
+	// ErrRelaySignatureInvalid is returned when the request was not signed by its sender
+	ErrRelaySignatureInvalid = Unauthorized("relay request signature does not match the sender")
+	// ErrRelayRequestExpired is returned when a request arrives after its deadline
+	ErrRelayRequestExpired = Validation("relay request has expired")
+	// ErrRelayNonceUsed is returned when a request reuses or skips a nonce
+	ErrRelayNonceUsed = Conflict("relay request nonce is not the next one for this sender")
+	// ErrRelayedTransactionNotFound is returned when a relayed transaction does not exist or belongs to another user
+	ErrRelayedTransactionNotFound = NotFound("relayed transaction not found")
+	// ErrRelayContentFlagged is returned when screening flags a request's content, which is then never written on-chain
+	ErrRelayContentFlagged = Validation("content was flagged by screening and cannot be relayed")
+)
+
+// RelayRequest is a Diamond call a user signs with EIP-712 for the operator to
+// send and pay for. Which content fields apply depends on the operation.
+type RelayRequest struct {
+	Operation RelayOperation `bson:"operation" json:"operation"`
+	From      string         `bson:"from" json:"from"`
+	// Only for acknowledge: the on-chain expression ID and its creator
+	ExpressionID string `bson:"expressionId,omitempty" json:"expressionId,omitempty"`
+	Creator      string `bson:"creator,omitempty" json:"creator,omitempty"`
+	// ExpressionRecordID is, for acknowledge, the app's ID of the expression.
+	// It is not signed; the expression's creator must match Creator.
+	ExpressionRecordID string `bson:"expressionRecordId,omitempty" json:"expressionRecordId,omitempty"`
+	Message            string `bson:"message,omitempty" json:"message,omitempty"`
+	TextContent        string `bson:"textContent" json:"textContent"`
+	AudioContent       string `bson:"audioContent" json:"audioContent"`
+	VideoContent       string `bson:"videoContent" json:"videoContent"`
+	ImageContent       string `bson:"imageContent" json:"imageContent"`
+	// Nonce orders a sender's requests; each is relayed once
+	Nonce uint64 `bson:"nonce" json:"nonce"`
+	// Deadline is the Unix time after which the request is refused; zero means none
+	Deadline  int64  `bson:"deadline" json:"deadline"`
+	Signature string `bson:"signature" json:"signature"`
+}
+
+// Expired reports whether the request's deadline has passed
+func (r *RelayRequest) Expired(now time.Time) bool {
+	return r.Deadline != 0 && now.Unix() > r.Deadline
+}
+
+// RelayDomain is the EIP-712 domain relay requests are signed under
+type RelayDomain struct {
+	Name              string `json:"name"`
+	Version           string `json:"version"`
+	ChainID           int64  `json:"chainId"`
+	VerifyingContract string `json:"verifyingContract"`
+}
+
+// RelayPolicy bounds what the relayer spends and how it treats transactions
+// that are not mined
+type RelayPolicy struct {
+	// MaxFeePerGas is the most the operator pays per gas, in wei
+	MaxFeePerGas *big.Int
+	// MaxGasLimit is the most gas one relayed call may use
+	MaxGasLimit uint64
+	// StuckAfter is how long a transaction may wait unmined before it is replaced
+	StuckAfter time.Duration
+	// MaxAttempts is the most transactions sent for one request, counting replacements
+	MaxAttempts int
+}
+
+// RelayStatus tracks a relayed transaction from acceptance to inclusion
+type RelayStatus string
+
+const (
+	// RelayStatusPending means the request was accepted but is not on the network yet
+	RelayStatusPending RelayStatus = "pending"
+	// RelayStatusSubmitted means a transaction was sent and is waiting to be mined
+	RelayStatusSubmitted RelayStatus = "submitted"
+	// RelayStatusConfirmed means the transaction was mined and succeeded
+	RelayStatusConfirmed RelayStatus = "confirmed"
+	// RelayStatusFailed means the transaction reverted or could not be sent
+	RelayStatusFailed RelayStatus = "failed"
+)
+
+// RelayAttempt is one transaction sent for a request. Replacing a stuck
+// transaction adds an attempt with the same operator nonce and higher fees.
+type RelayAttempt struct {
+	TxHash string `bson:"txHash" json:"txHash"`
+	// Fees in wei, as decimal strings
+	GasTipCap string    `bson:"gasTipCap" json:"gasTipCap"`
+	GasFeeCap string    `bson:"gasFeeCap" json:"gasFeeCap"`
+	SentAt    time.Time `bson:"sentAt" json:"sentAt"`
+}
+
+// RelayedTransaction records a relay request and the transactions sent for it
+type RelayedTransaction struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID  primitive.ObjectID `bson:"userId" json:"userId"`
+	Request RelayRequest       `bson:"request" json:"request"`
+	// RequestKey is the sender and nonce, unique so a request is relayed once
+	RequestKey  string `bson:"requestKey" json:"-"`
+	RequestHash string `bson:"requestHash" json:"requestHash"`
+	// RecordID is the expression or acknowledgement the request added to the app
+	RecordID      string         `bson:"recordId,omitempty" json:"recordId,omitempty"`
+	Operator      string         `bson:"operator" json:"operator"`
+	OperatorNonce uint64         `bson:"operatorNonce" json:"operatorNonce"`
+	GasLimit      uint64         `bson:"gasLimit" json:"gasLimit"`
+	Status        RelayStatus    `bson:"status" json:"status"`
+	Attempts      []RelayAttempt `bson:"attempts" json:"attempts"`
+	// Calldata is set for operator calls, which have no signed request to encode
+	Calldata string `bson:"calldata,omitempty" json:"-"`
+	// TxHash is the mined transaction once confirmed or failed, else the latest attempt
+	TxHash      string `bson:"txHash,omitempty" json:"txHash,omitempty"`
+	BlockNumber uint64 `bson:"blockNumber,omitempty" json:"blockNumber,omitempty"`
+	GasUsed     uint64 `bson:"gasUsed,omitempty" json:"gasUsed,omitempty"`
+	// FeeGwei is what the operator paid once mined, rounded up to whole gwei
+	FeeGwei   int64     `bson:"feeGwei,omitempty" json:"feeGwei,omitempty"`
+	Error     string    `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// RelayRequestKey identifies a sender's request by nonce
+func RelayRequestKey(from string, nonce uint64) string {
+	return strings.ToLower(from) + ":" + strconv.FormatUint(nonce, 10)
+}
+
+// LastAttempt returns the most recent transaction sent, or nil if none was
+func (t *RelayedTransaction) LastAttempt() *RelayAttempt {
+	if len(t.Attempts) == 0 {
+		return nil
+	}
+	return &t.Attempts[len(t.Attempts)-1]
+}
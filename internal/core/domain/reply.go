@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ReplyTextMaxLength caps the text of one reply
+	ReplyTextMaxLength = 2000
+	// ReplyPageSize is how many replies a page holds unless the caller asks for fewer
+	ReplyPageSize = 20
+	// ReplyPageMaxSize is the largest page a caller may ask for
+	ReplyPageMaxSize = 100
+)
+
+// ReplyMediaTypes are the kinds of media a reply may carry besides text
+var ReplyMediaTypes = []string{"image", "audio", "video"}
+
+// Reply is one message in the dialogue an expression's creator and an
+// acknowledger hold under the acknowledgement before they request a proof
+type Reply struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AcknowledgementID string             `bson:"acknowledgementId" json:"acknowledgementId"`
+	ExpressionID      string             `bson:"expressionId" json:"expressionId"`
+	// ParentID is the reply this one answers, empty when it answers the acknowledgement
+	ParentID string `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	AuthorID string `bson:"authorId" json:"authorId"`
+	// Content holds the text and the storage keys of any media, which are
+	// replaced by presigned URLs when replies are listed
+	Content          map[string]string        `bson:"content" json:"content"`
+	MediaContent     map[string]*MediaContent `bson:"-" json:"-"`
+	ModerationStatus ModerationStatus         `bson:"moderationStatus,omitempty" json:"moderationStatus,omitempty"`
+	CreatedAt        time.Time                `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time                `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ReplyPage is one page of an acknowledgement's replies, oldest first.
+// Clients build the threads from ParentID.
+type ReplyPage struct {
+	Replies []*Reply `json:"replies"`
+	// NextCursor fetches the following page; it is empty on the last one
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+var (
+	// ErrReplyNotParty is returned when someone other than the two parties reads or writes a dialogue
+	ErrReplyNotParty = Forbidden("only the expression's creator and its acknowledger can take part in this dialogue")
+	// ErrReplyEmpty is returned for a reply with neither text nor media
+	ErrReplyEmpty = Validation("a reply needs text or media")
+	// ErrReplyParentNotFound is returned when a reply answers a reply outside the dialogue
+	ErrReplyParentNotFound = NotFound("the reply being answered was not found")
+)
@@ -0,0 +1,26 @@
+package domain
+
+// Expression statuses set when an expression is created
+const (
+	// ExpressionStatusPending is an expression that passed screening and awaits confirmation
+	ExpressionStatusPending = "pending"
+	// ExpressionStatusPendingReview is an expression a screener flagged; it stays hidden until a moderator decides
+	ExpressionStatusPendingReview = "pending_review"
+)
+
+// ScreeningInput is the content handed to automated screeners before an expression is saved
+type ScreeningInput struct {
+	ContentType ContentType
+	ContentID   string
+	Text        string
+	Media       map[string]string // Media type to storage key, e.g. "image" -> "expressions/<id>/image.png"
+	Locale      string            // BCP 47 language tag of the author, if known
+}
+
+// ScreeningResult is a screener's verdict on a piece of content
+type ScreeningResult struct {
+	Flagged  bool         `json:"flagged"`
+	Screener string       `json:"screener"`
+	Reason   ReportReason `json:"reason"`
+	Matches  []string     `json:"matches,omitempty"`
+}
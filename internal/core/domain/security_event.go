@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SecurityEventType string
+
+const (
+	// SecurityEventSignCountRegression is recorded when a passkey presents a
+	// signature counter that did not increase, which may mean it was cloned
+	SecurityEventSignCountRegression SecurityEventType = "SIGN_COUNT_REGRESSION"
+)
+
+// SecurityEvent is an append-only record of a security-relevant occurrence on an account
+type SecurityEvent struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID     `bson:"userId" json:"userId"`
+	Type      SecurityEventType      `bson:"type" json:"type"`
+	Action    string                 `bson:"action" json:"action"` // What the system did in response
+	Details   map[string]interface{} `bson:"details" json:"details"`
+	CreatedAt time.Time              `bson:"createdAt" json:"createdAt"`
+}
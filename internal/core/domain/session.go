@@ -1,19 +1,54 @@
 package domain
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrSessionNotFound is returned when a session does not exist or belongs to another user
+var ErrSessionNotFound = NotFound("session not found")
+
 type Session struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty"`
-	Token          string             `bson:"token"`
+	Token          string             `bson:"-"`         // Plaintext token, only held in memory when issued or presented
+	TokenHash      string             `bson:"tokenHash"` // SHA-256 of the token, the only form that is stored
 	UserID         string             `bson:"userId"`
 	WebAuthnData   string             `bson:"webauthnData"` // For storing WebAuthn session data
 	Address        string             `bson:"address"`
+	UserAgent      string             `bson:"userAgent"`
+	IPAddress      string             `bson:"ipAddress"`
+	LastSeenAt     time.Time          `bson:"lastSeenAt"`
 	ExpiresAt      time.Time          `bson:"expiresAt"`
 	CreatedAt      time.Time          `bson:"createdAt"`
 	UpdatedAt      time.Time          `bson:"updatedAt"`
 	IsRegistration bool               `bson:"isRegistration"` // Indicates if this is a registration session
 }
+
+// HashToken returns the hex-encoded SHA-256 digest under which a bearer token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ClientInfo describes the device a request came from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying the requesting client's details
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client details stored by WithClientInfo, if any
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
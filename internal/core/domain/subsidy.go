@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Operations a user can be subsidized for, as kept in User.SubsidizedOps
+const (
+	SubsidyExpression      = "expression"
+	SubsidyAcknowledgement = "acknowledgement"
+	SubsidyNFT             = "nft"
+)
+
+// subsidyFlags are the operation flags LibPermissions stores subsidies under
+var subsidyFlags = map[string]uint8{
+	SubsidyExpression:      1,
+	SubsidyAcknowledgement: 2,
+	SubsidyNFT:             4,
+}
+
+// SubsidyFlag returns the on-chain flag for op, and false for an unknown op
+func SubsidyFlag(op string) (uint8, bool) {
+	flag, ok := subsidyFlags[op]
+	return flag, ok
+}
+
+// SubsidyOperation returns the operation stored under an on-chain flag, or ""
+func SubsidyOperation(flag uint8) string {
+	for op, f := range subsidyFlags {
+		if f == flag {
+			return op
+		}
+	}
+	return ""
+}
+
+// IsSubsidized reports whether the platform pays gas for op on the user's behalf
+func (u *User) IsSubsidized(op string) bool {
+	for _, subsidized := range u.SubsidizedOps {
+		if subsidized == op {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrInvalidSubsidy is returned for an operation that cannot be subsidized
+	ErrInvalidSubsidy = Validation("operations must be expression, acknowledgement or nft")
+	// ErrSubsidyNeedsWallet is returned when granting subsidies to a user without a wallet, as they are kept per address on-chain
+	ErrSubsidyNeedsWallet = Validation("user has no wallet connected")
+	// ErrOperatorBudgetExhausted is returned when the operator has spent its gas budget for the month
+	ErrOperatorBudgetExhausted = Forbidden("the operator's gas budget for this month is used up")
+)
+
+// SubsidyChangeStatus tracks a grant or revocation until it is on-chain
+type SubsidyChangeStatus string
+
+const (
+	// SubsidyChangeQueued means the change waits for the next batch
+	SubsidyChangeQueued SubsidyChangeStatus = "queued"
+	// SubsidyChangeSent means the change is in a setOperatorSubsidies transaction
+	SubsidyChangeSent SubsidyChangeStatus = "sent"
+	// SubsidyChangeConfirmed means its SubsidyStatusChanged event was seen
+	SubsidyChangeConfirmed SubsidyChangeStatus = "confirmed"
+	// SubsidyChangeFailed means the transaction failed and the user's subsidies were restored
+	SubsidyChangeFailed SubsidyChangeStatus = "failed"
+)
+
+// SubsidyChange is one grant or revocation on its way to the PermissionsFacet.
+// User.SubsidizedOps is written straight away; changes are then sent in
+// batches with setOperatorSubsidies and confirmed by the chain's events.
+type SubsidyChange struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"userId" json:"userId"`
+	Address     string              `bson:"address" json:"address"`
+	Operation   string              `bson:"operation" json:"operation"`
+	Granted     bool                `bson:"granted" json:"granted"`
+	Status      SubsidyChangeStatus `bson:"status" json:"status"`
+	RequestedBy primitive.ObjectID  `bson:"requestedBy" json:"requestedBy"`
+	// RelayID is the relayed transaction carrying the batch, once sent
+	RelayID   *primitive.ObjectID `bson:"relayId,omitempty" json:"relayId,omitempty"`
+	TxHash    string              `bson:"txHash,omitempty" json:"txHash,omitempty"`
+	Error     string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// OperatorBudget caps what one operator account spends on gas per calendar
+// month. Relaying for users stops once it is used up; operators without a
+// budget are not capped.
+type OperatorBudget struct {
+	Operator         string             `bson:"_id" json:"operator"`
+	MonthlyLimitGwei int64              `bson:"monthlyLimitGwei" json:"monthlyLimitGwei"`
+	UpdatedBy        primitive.ObjectID `bson:"updatedBy" json:"updatedBy"`
+	UpdatedAt        time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// BudgetPeriodStart is the start of the calendar month, in UTC, that budgets
+// count spending over
+func BudgetPeriodStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// OperationUsage is what one kind of relayed call cost
+type OperationUsage struct {
+	Transactions int   `json:"transactions"`
+	SpentGwei    int64 `json:"spentGwei"`
+}
+
+// OperatorUsage reports one operator's gas spending in the current budget period
+type OperatorUsage struct {
+	Operator     string                            `json:"operator"`
+	Transactions int                               `json:"transactions"`
+	SpentGwei    int64                             `json:"spentGwei"`
+	ByOperation  map[RelayOperation]OperationUsage `json:"byOperation"`
+	// Budget is nil for operators without one
+	Budget        *OperatorBudget `json:"budget,omitempty"`
+	RemainingGwei *int64          `json:"remainingGwei,omitempty"`
+}
+
+// SubsidyReport summarises subsidies and what they cost this period
+type SubsidyReport struct {
+	PeriodStart time.Time       `json:"periodStart"`
+	Operators   []OperatorUsage `json:"operators"`
+	// SubsidizedUsers counts users entitled to each operation
+	SubsidizedUsers map[string]int `json:"subsidizedUsers"`
+	// PendingChanges counts grants and revocations not yet confirmed on-chain
+	PendingChanges int `json:"pendingChanges"`
+}
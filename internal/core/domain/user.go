@@ -7,16 +7,58 @@ import (
 )
 
 type User struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	Username      string             `bson:"username,omitempty" validate:"omitempty,min=3,max=30"`
-	DisplayName   string             `bson:"displayName,omitempty"`
-	Address       string             `bson:"address,omitempty"`
-	Email         string             `bson:"email,omitempty" validate:"omitempty,email"`
-	Password      string             `bson:"password,omitempty"`
-	Citizenship   string             `bson:"citizenship,omitempty"`
-	City          string             `bson:"city,omitempty"`
-	Nonce         int                `bson:"nonce"`
-	SubsidizedOps []string           `bson:"subsidizedOperations"`
-	CreatedAt     time.Time          `bson:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt"`
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Username       string             `bson:"username,omitempty" validate:"omitempty,min=3,max=30"`
+	DisplayName    string             `bson:"displayName,omitempty"`
+	Address        string             `bson:"address,omitempty"`
+	Email          string             `bson:"email,omitempty" validate:"omitempty,email"`
+	Password       string             `bson:"password,omitempty"`
+	Citizenship    string             `bson:"citizenship,omitempty"`
+	City           string             `bson:"city,omitempty"`
+	Nonce          int                `bson:"nonce"`
+	SubsidizedOps  []string           `bson:"subsidizedOperations"`
+	Role           Role               `bson:"role,omitempty"`
+	SuspendedUntil *time.Time         `bson:"suspendedUntil,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt"`
+}
+
+// IsSuspended reports whether a moderator has suspended the user from posting
+func (u *User) IsSuspended() bool {
+	return u.SuspendedUntil != nil && time.Now().Before(*u.SuspendedUntil)
+}
+
+// Role grants a user access to privileged parts of the application
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// roleRank orders roles so that each role includes the permissions of those below it
+var roleRank = map[Role]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role Role) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// EffectiveRole returns the user's role, treating accounts without one as regular users
+func (u *User) EffectiveRole() Role {
+	if ValidRole(u.Role) {
+		return u.Role
+	}
+	return RoleUser
+}
+
+// HasRole reports whether the user holds the given role or a more privileged one
+func (u *User) HasRole(role Role) bool {
+	return roleRank[u.EffectiveRole()] >= roleRank[role]
 }
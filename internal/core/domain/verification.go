@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+// PublicRecordKind is what a public verification page shows
+type PublicRecordKind string
+
+const (
+	PublicRecordExpression PublicRecordKind = "expression"
+	PublicRecordProof      PublicRecordKind = "proof"
+)
+
+// PublicRecord is everything the public verification page shows about an
+// expression or a proof of peacemaking
+type PublicRecord struct {
+	Kind       PublicRecordKind
+	Expression *PublicExpression
+	// Proof is set for proof NFTs and proof requests
+	Proof  *PublicProof
+	Checks []IntegrityCheck
+}
+
+// PublicExpression is the publicly visible part of an expression
+type PublicExpression struct {
+	ID        string
+	Text      string
+	Creator   PublicParty
+	IPFSHash  string
+	OnChainID int
+	// ContentHash is the hash anchors and attestations commit to
+	ContentHash          string
+	AcknowledgementCount int
+	// TokenIDs are the proof NFTs minted for the expression
+	TokenIDs  []int
+	CreatedAt time.Time
+}
+
+// PublicProof is the publicly visible part of a proof NFT or proof request
+type PublicProof struct {
+	ID       string
+	Source   CredentialSource
+	Status   string
+	TokenID  int
+	IPFSHash string
+	Parties  []PublicParty
+	// Date is when the NFT was minted or the request last changed
+	Date time.Time
+}
+
+// PublicParty is a person named on a public page
+type PublicParty struct {
+	Name    string
+	Role    string
+	Address string
+	// HasValidPOP is the POPNFTFacet's answer for Address, nil when it could
+	// not be asked
+	HasValidPOP *bool
+}
+
+// IntegrityCheckStatus is the outcome of one integrity check
+type IntegrityCheckStatus string
+
+const (
+	IntegrityPassed IntegrityCheckStatus = "passed"
+	IntegrityFailed IntegrityCheckStatus = "failed"
+	// IntegrityPending is a check that cannot pass yet, such as an anchor batch not sent
+	IntegrityPending IntegrityCheckStatus = "pending"
+	// IntegrityUnavailable is a check the server could not run, such as without a chain
+	IntegrityUnavailable IntegrityCheckStatus = "unavailable"
+)
+
+// IntegrityCheck is one line of a verification page's integrity report
+type IntegrityCheck struct {
+	Name   string
+	Status IntegrityCheckStatus
+	Detail string
+}
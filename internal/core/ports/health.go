@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// HealthCheck probes one dependency the server needs to serve traffic
+type HealthCheck interface {
+	// Name identifies the dependency in readiness responses
+	Name() string
+	Check(ctx context.Context) error
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// Mailer sends HTML email from the configured sender address
+type Mailer interface {
+	Send(ctx context.Context, to, subject, html string) error
+}
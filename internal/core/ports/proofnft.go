@@ -10,4 +10,31 @@ type ProofNFTRepository interface {
 	Update(ctx context.Context, proofNFT *domain.ProofNFT) error
 	FindByID(ctx context.Context, id string) (*domain.ProofNFT, error)
 	FindByAcknowledger(ctx context.Context, acknowledgerID string) ([]*domain.ProofNFT, error)
+	FindByExpression(ctx context.Context, expressionID string) ([]*domain.ProofNFT, error)
+}
+
+type ProofRequestRepository interface {
+	Create(ctx context.Context, request *domain.ProofRequest) error
+	// Update saves request if its status is still from, or returns domain.ErrProofRequestNotPending
+	Update(ctx context.Context, request *domain.ProofRequest, from domain.ProofRequestStatus) error
+	FindByID(ctx context.Context, id string) (*domain.ProofRequest, error)
+	// FindUnpaired lists the requests made before pairings existed
+	FindUnpaired(ctx context.Context) ([]*domain.ProofRequest, error)
+}
+
+// ExpressionAcknowledgementRepository stores the pairings of expressions and
+// their acknowledgements
+type ExpressionAcknowledgementRepository interface {
+	// Create stores a pairing, or returns domain.ErrConflict if the acknowledgement already has one
+	Create(ctx context.Context, pairing *domain.ExpressionAcknowledgement) error
+	// Update saves pairing if its NFT status is still from, so concurrent
+	// changes to the NFT flow cannot both win. It returns
+	// domain.ErrPairingNFTStatus otherwise.
+	Update(ctx context.Context, pairing *domain.ExpressionAcknowledgement, from domain.NFTStatus) error
+	FindByID(ctx context.Context, id string) (*domain.ExpressionAcknowledgement, error)
+	FindByAcknowledgement(ctx context.Context, acknowledgementID string) (*domain.ExpressionAcknowledgement, error)
+	// FindByUser lists the pairings a user is either party of, newest first
+	FindByUser(ctx context.Context, userID string) ([]*domain.ExpressionAcknowledgement, error)
+	// FindByNFTStatus lists the pairings in status, oldest first
+	FindByNFTStatus(ctx context.Context, status domain.NFTStatus) ([]*domain.ExpressionAcknowledgement, error)
 }
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore keeps fixed-window hit counters and account locks. The
+// in-memory store suits a single instance; the MongoDB store is shared
+// between instances.
+type RateLimitStore interface {
+	// Increment counts a hit on key in the current window and returns the
+	// count so far and when the window ends
+	Increment(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+	// Lock marks key as locked until the given time
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns when the lock on key ends, or the zero time if it is not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset clears every counter and lock on key
+	Reset(ctx context.Context, key string) error
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"proofofpeacemaking/internal/core/domain"
 
@@ -17,6 +18,14 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 	UpdateNonce(ctx context.Context, id primitive.ObjectID, nonce int) error
 	ConnectWallet(ctx context.Context, userID primitive.ObjectID, address string) error
+	SetRole(ctx context.Context, userID primitive.ObjectID, role domain.Role) error
+	SetSuspendedUntil(ctx context.Context, userID primitive.ObjectID, until *time.Time) error
+	// GetByAddressIgnoreCase finds a user whatever case their wallet address was stored in
+	GetByAddressIgnoreCase(ctx context.Context, address string) (*domain.User, error)
+	// SetSubsidy adds operation to or removes it from the user's subsidized operations
+	SetSubsidy(ctx context.Context, userID primitive.ObjectID, operation string, granted bool) error
+	// GetSubsidyCounts returns how many users are subsidized for each operation
+	GetSubsidyCounts(ctx context.Context) (map[string]int, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	GetTotalCount(ctx context.Context) (int, error)
 	GetCitizenshipDistribution(ctx context.Context) (map[string]int, error)
@@ -32,12 +41,23 @@ type NotificationRepository interface {
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) error
 	FindByToken(ctx context.Context, token string) (*domain.Session, error)
+	FindByUserID(ctx context.Context, userID string) ([]*domain.Session, error)
 	DeleteByToken(ctx context.Context, token string) error
-	DeleteExpired(ctx context.Context) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
 	Update(ctx context.Context, session *domain.Session) error
+	UpdateToken(ctx context.Context, id primitive.ObjectID, token string) error
+	Touch(ctx context.Context, id primitive.ObjectID, info domain.ClientInfo) error
 	DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error
 }
 
+type APITokenRepository interface {
+	Create(ctx context.Context, token *domain.APIToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIToken, error)
+	Revoke(ctx context.Context, userID, id primitive.ObjectID) error
+	UpdateLastUsed(ctx context.Context, id primitive.ObjectID) error
+}
+
 type ExpressionRepository interface {
 	Create(ctx context.Context, expression *domain.Expression) error
 	FindByID(ctx context.Context, id string) (*domain.Expression, error)
@@ -46,18 +66,46 @@ type ExpressionRepository interface {
 	FindByIDs(ctx context.Context, ids []string) ([]*domain.Expression, error)
 	GetByUserID(ctx context.Context, userID string) ([]*domain.Expression, error)
 	Update(ctx context.Context, expression *domain.Expression) error
+	SetModerationStatus(ctx context.Context, id string, status domain.ModerationStatus) error
 	Delete(ctx context.Context, id string) error
 	GetTotalCount(ctx context.Context) (int, error)
 	GetTotalAcknowledgements(ctx context.Context) (int, error)
 	GetMediaTypeDistribution(ctx context.Context) (map[string]int, error)
+	// FindCreatedBetween returns expressions created after after and up to until, oldest first
+	FindCreatedBetween(ctx context.Context, after, until time.Time) ([]*domain.Expression, error)
 }
 
 type AcknowledgementRepository interface {
 	Create(ctx context.Context, acknowledgement *domain.Acknowledgement) error
+	FindByID(ctx context.Context, id string) (*domain.Acknowledgement, error)
 	FindByExpression(ctx context.Context, expressionID string) ([]*domain.Acknowledgement, error)
 	FindByAcknowledger(ctx context.Context, acknowledgerID string) ([]*domain.Acknowledgement, error)
 	FindByStatus(ctx context.Context, status domain.AcknowledgementStatus) ([]*domain.Acknowledgement, error)
 	Update(ctx context.Context, acknowledgement *domain.Acknowledgement) error
+	SetModerationStatus(ctx context.Context, id string, status domain.ModerationStatus) error
+	// FindCreatedBetween returns acknowledgements created after after and up to until, oldest first
+	FindCreatedBetween(ctx context.Context, after, until time.Time) ([]*domain.Acknowledgement, error)
+}
+
+// ModerationRepository stores user reports, the moderation queue and the moderation log
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, report *domain.Report) error
+	HasReported(ctx context.Context, reporterID primitive.ObjectID, contentType domain.ContentType, contentID string) (bool, error)
+	FindReportsByCase(ctx context.Context, caseID primitive.ObjectID) ([]*domain.Report, error)
+
+	CreateCase(ctx context.Context, moderationCase *domain.ModerationCase) error
+	FindCaseByID(ctx context.Context, id primitive.ObjectID) (*domain.ModerationCase, error)
+	// FindActiveCase returns the unresolved case for a piece of content, if any
+	FindActiveCase(ctx context.Context, contentType domain.ContentType, contentID string) (*domain.ModerationCase, error)
+	FindCasesByStatus(ctx context.Context, statuses []domain.CaseStatus) ([]*domain.ModerationCase, error)
+	AddReportToCase(ctx context.Context, caseID primitive.ObjectID, reason domain.ReportReason) error
+	// UpdateCase saves the case only if it still has the given status and
+	// claimant, returning domain.ErrInvalidCaseTransition if another moderator got there first
+	UpdateCase(ctx context.Context, moderationCase *domain.ModerationCase, from domain.CaseStatus, claimedBy *primitive.ObjectID) error
+
+	// AppendLog records a moderation event; log entries are never modified
+	AppendLog(ctx context.Context, entry *domain.ModerationLogEntry) error
+	FindLogByCase(ctx context.Context, caseID primitive.ObjectID) ([]*domain.ModerationLogEntry, error)
 }
 
 // PasskeyRepository handles passkey credential storage operations
@@ -71,10 +119,115 @@ type PasskeyRepository interface {
 
 	// User-Passkey relationship operations
 	AssignCredentialToUser(ctx context.Context, userPasskey *domain.UserPasskey) error
+	GetUserPasskeyByID(ctx context.Context, id primitive.ObjectID) (*domain.UserPasskey, error)
 	GetUserPasskeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.UserPasskey, error)
 	GetActiveUserPasskeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.UserPasskey, error)
 	DeactivateUserPasskey(ctx context.Context, id primitive.ObjectID) error
 	UpdateUserPasskeyLastUsed(ctx context.Context, id primitive.ObjectID, deviceInfo string) error
+	RenameUserPasskey(ctx context.Context, id primitive.ObjectID, name string) error
+}
+
+// SecurityEventRepository stores the security event log
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *domain.SecurityEvent) error
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.SecurityEvent, error)
+}
+
+// RelayedTransactionRepository stores relay requests and the transactions sent for them
+type RelayedTransactionRepository interface {
+	// Create stores a new request, returning domain.ErrRelayNonceUsed if the sender's nonce was already taken
+	Create(ctx context.Context, tx *domain.RelayedTransaction) error
+	Update(ctx context.Context, tx *domain.RelayedTransaction) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.RelayedTransaction, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.RelayedTransaction, error)
+	// FindByStatus returns transactions in any of statuses, oldest first
+	FindByStatus(ctx context.Context, statuses ...domain.RelayStatus) ([]*domain.RelayedTransaction, error)
+	// NextNonce returns the nonce the sender's next request must use
+	NextNonce(ctx context.Context, from string) (uint64, error)
+	// Usage sums what the operator paid for mined transactions created since the given time
+	Usage(ctx context.Context, operator string, since time.Time) (map[domain.RelayOperation]domain.OperationUsage, error)
+}
+
+// SubsidyChangeRepository stores subsidy grants and revocations until they are on-chain
+type SubsidyChangeRepository interface {
+	Create(ctx context.Context, change *domain.SubsidyChange) error
+	Update(ctx context.Context, change *domain.SubsidyChange) error
+	// FindByStatus returns up to limit changes in status, oldest first; a limit of 0 returns all
+	FindByStatus(ctx context.Context, status domain.SubsidyChangeStatus, limit int) ([]*domain.SubsidyChange, error)
+	// FindUnconfirmed returns the user's queued and sent changes to operation, oldest first
+	FindUnconfirmed(ctx context.Context, userID primitive.ObjectID, operation string) ([]*domain.SubsidyChange, error)
+	// List returns up to limit changes, newest first, optionally only those in status
+	List(ctx context.Context, status domain.SubsidyChangeStatus, limit int) ([]*domain.SubsidyChange, error)
+	CountUnconfirmed(ctx context.Context) (int, error)
+}
+
+// OperatorBudgetRepository stores the monthly gas budgets of operator accounts
+type OperatorBudgetRepository interface {
+	Get(ctx context.Context, operator string) (*domain.OperatorBudget, error)
+	Set(ctx context.Context, budget *domain.OperatorBudget) error
+	Delete(ctx context.Context, operator string) error
+	List(ctx context.Context) ([]*domain.OperatorBudget, error)
+}
+
+// AnchorRepository stores Merkle anchoring batches and the proofs of the records in them
+type AnchorRepository interface {
+	CreateBatch(ctx context.Context, batch *domain.AnchorBatch) error
+	UpdateBatch(ctx context.Context, batch *domain.AnchorBatch) error
+	FindBatchByID(ctx context.Context, id primitive.ObjectID) (*domain.AnchorBatch, error)
+	// LatestBatch returns the batch with the latest window, or nil if there is none
+	LatestBatch(ctx context.Context) (*domain.AnchorBatch, error)
+	// FindBatchesByStatus returns batches in status, oldest first
+	FindBatchesByStatus(ctx context.Context, status domain.AnchorStatus) ([]*domain.AnchorBatch, error)
+	// SaveProofs stores proofs, replacing any earlier proof of the same record
+	SaveProofs(ctx context.Context, proofs []*domain.AnchorProof) error
+	FindProof(ctx context.Context, documentID string) (*domain.AnchorProof, error)
+}
+
+// ChainCursorRepository remembers how far background jobs have read the chain
+type ChainCursorRepository interface {
+	// Get returns the next block to read for name, and false if nothing was read yet
+	Get(ctx context.Context, name string) (uint64, bool, error)
+	Set(ctx context.Context, name string, nextBlock uint64) error
+}
+
+// CredentialRepository stores issued verifiable credentials
+type CredentialRepository interface {
+	// Create stores a credential, or returns domain.ErrConflict if one was already issued for its source
+	Create(ctx context.Context, credential *domain.Credential) error
+	FindByID(ctx context.Context, id string) (*domain.Credential, error)
+	FindBySource(ctx context.Context, sourceID string) (*domain.Credential, error)
+	// FindByHolder lists a user's credentials, newest first
+	FindByHolder(ctx context.Context, userID string) ([]*domain.Credential, error)
+}
+
+// InvitationRepository stores directed invitations to acknowledge expressions
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	// Update saves invitation if its status is still from, or returns
+	// domain.ErrInvitationNotPending
+	Update(ctx context.Context, invitation *domain.Invitation, from domain.InvitationStatus) error
+	FindByID(ctx context.Context, id string) (*domain.Invitation, error)
+	// FindByCreator lists the invitations a user sent, newest first
+	FindByCreator(ctx context.Context, userID string) ([]*domain.Invitation, error)
+	// FindByInviteeID lists the invitations linked to a user's account, newest first
+	FindByInviteeID(ctx context.Context, userID string) ([]*domain.Invitation, error)
+	// FindPending lists the pending invitations to an expression
+	FindPending(ctx context.Context, expressionID string) ([]*domain.Invitation, error)
+	// FindUnlinked lists the pending invitations sent to kind and invitee
+	// that are not yet linked to an account
+	FindUnlinked(ctx context.Context, kind domain.InviteeKind, invitee string) ([]*domain.Invitation, error)
+	// ExpireLapsed marks pending invitations past their expiry as expired
+	ExpireLapsed(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ReplyRepository stores the replies of acknowledgement dialogues
+type ReplyRepository interface {
+	Create(ctx context.Context, reply *domain.Reply) error
+	FindByID(ctx context.Context, id string) (*domain.Reply, error)
+	// FindByAcknowledgement returns up to limit replies to an acknowledgement,
+	// oldest first, starting after the reply with ID after if it is set
+	FindByAcknowledgement(ctx context.Context, acknowledgementID string, after string, limit int) ([]*domain.Reply, error)
+	SetModerationStatus(ctx context.Context, id string, status domain.ModerationStatus) error
 }
 
 // StatisticsRepository handles statistics data storage
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"proofofpeacemaking/internal/core/domain"
+)
+
+// ContentScreener inspects new content before it is published. Screeners are
+// combined into a chain; any one flagging the content sends it to moderation.
+type ContentScreener interface {
+	// Name identifies the screener in moderation cases and logs
+	Name() string
+	Screen(ctx context.Context, input *domain.ScreeningInput) (*domain.ScreeningResult, error)
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"io"
+	"time"
 
 	"proofofpeacemaking/internal/core/domain"
 
@@ -17,6 +18,11 @@ type NotificationService interface {
 	NotifyProofRequestAccepted(ctx context.Context, request *domain.ProofRequest) error
 	NotifyProofRequestRejected(ctx context.Context, request *domain.ProofRequest) error
 	NotifyNFTMinted(ctx context.Context, nft *domain.ProofNFT) error
+	NotifySecurityAlert(ctx context.Context, event *domain.SecurityEvent) error
+	NotifyModerationNotice(ctx context.Context, userID primitive.ObjectID, moderationCase *domain.ModerationCase, message string) error
+	NotifyInvitationReceived(ctx context.Context, invitation *domain.Invitation) error
+	NotifyInvitationAccepted(ctx context.Context, invitation *domain.Invitation) error
+	NotifyNewReply(ctx context.Context, reply *domain.Reply, recipientID string) error
 	GetUserNotifications(ctx context.Context, userAddress string) ([]*domain.Notification, error)
 	MarkNotificationAsRead(ctx context.Context, userAddress string, notificationID string) error
 }
@@ -29,6 +35,7 @@ type AuthService interface {
 	LoginWithEmail(ctx context.Context, email string, password string) (*domain.User, string, error)
 	VerifyToken(ctx context.Context, token string) (string, error)
 	Logout(ctx context.Context, token string) error
+	RotateSession(ctx context.Context, token string) (string, error)
 	DeleteAllUserSessions(ctx context.Context, userIdentifier string) error
 }
 
@@ -49,12 +56,71 @@ type AcknowledgementService interface {
 	ListByUser(ctx context.Context, userAddress string) ([]*domain.Acknowledgement, error)
 	ListByStatus(ctx context.Context, status domain.AcknowledgementStatus) ([]*domain.Acknowledgement, error)
 	Update(ctx context.Context, acknowledgement *domain.Acknowledgement) error
+	// VerifyAttestation checks a signed attestation on its own, without looking up the acknowledgement
+	VerifyAttestation(ctx context.Context, message domain.AttestationMessage, signature string) (*domain.AttestationVerification, error)
+	// GetAttestation checks an acknowledgement's stored attestation against the acknowledgement as it is now
+	GetAttestation(ctx context.Context, id string) (*domain.AttestationVerification, error)
 }
 
+// ProofNFTService runs the proof request flow on expression/acknowledgement
+// pairings: either party requests a proof and the other approves or rejects it
 type ProofNFTService interface {
-	RequestProof(ctx context.Context, expressionID string, acknowledgementID string) error
-	ApproveProof(ctx context.Context, requestID string) error
-	ListUserProofs(ctx context.Context, userAddress string) ([]*domain.ProofNFT, error)
+	RequestProof(ctx context.Context, user *domain.User, expressionID string, acknowledgementID string) (*domain.ProofRequest, error)
+	// ApproveProof accepts a request and creates the proof NFT to be minted
+	ApproveProof(ctx context.Context, user *domain.User, requestID string) (*domain.ProofNFT, error)
+	RejectProof(ctx context.Context, user *domain.User, requestID string) error
+	CancelProof(ctx context.Context, user *domain.User, requestID string) error
+	// ListUserProofs lists the user's pairings that have entered the NFT flow
+	ListUserProofs(ctx context.Context, user *domain.User) ([]*domain.ExpressionAcknowledgement, error)
+}
+
+// ProofMintService mints the proof NFTs of approved proof requests and moves
+// their pairings through MINTING to MINTED or FAILED
+type ProofMintService interface {
+	// Mint settles sent mints and sends the approved ones that can be minted
+	Mint(ctx context.Context) error
+	// Run calls Mint every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// ExpressionAcknowledgementService keeps the pairings of expressions and
+// acknowledgements in step with the acknowledgements
+type ExpressionAcknowledgementService interface {
+	// Sync creates or updates the pairing of acknowledgement. Refuting an
+	// acknowledgement withdraws an open proof request.
+	Sync(ctx context.Context, acknowledgement *domain.Acknowledgement) error
+	// Backfill pairs acknowledgements saved before pairings existed, skipping
+	// those that cannot be paired, and links older proof requests to their pairings
+	Backfill(ctx context.Context) error
+	ListByUser(ctx context.Context, userID string) ([]*domain.ExpressionAcknowledgement, error)
+}
+
+// ReplyService runs the threaded dialogue between an expression's creator
+// and an acknowledger under the acknowledgement
+type ReplyService interface {
+	// Create posts reply by author, one of the two parties, and notifies the other
+	Create(ctx context.Context, author *domain.User, reply *domain.Reply) error
+	// List returns a page of an acknowledgement's replies for one of the two
+	// parties, starting after cursor. A limit of 0 uses the default page size.
+	List(ctx context.Context, user *domain.User, acknowledgementID string, cursor string, limit int) (*domain.ReplyPage, error)
+}
+
+// InvitationService invites specific people to acknowledge an expression
+// through signed links
+type InvitationService interface {
+	// Invite sends creator's invitation to acknowledge expressionID to invitee,
+	// a username, email or wallet address. The returned invitation carries its link.
+	Invite(ctx context.Context, creator *domain.User, expressionID string, invitee string, message string) (*domain.Invitation, error)
+	// Open returns the invitation a signed link token points to
+	Open(ctx context.Context, token string) (*domain.Invitation, error)
+	// Accept accepts the invitation behind token for user, who must be its invitee
+	Accept(ctx context.Context, user *domain.User, token string) (*domain.Invitation, error)
+	ListSent(ctx context.Context, user *domain.User) ([]*domain.Invitation, error)
+	ListReceived(ctx context.Context, user *domain.User) ([]*domain.Invitation, error)
+	// LinkUser attaches pending invitations sent to a new account's wallet
+	// address to the account and notifies its owner. Email invitations are
+	// linked when accepted through the emailed link.
+	LinkUser(ctx context.Context, user *domain.User) error
 }
 
 // FeedService handles feed-related operations
@@ -72,6 +138,7 @@ type UserService interface {
 	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
 	UpdateNonce(ctx context.Context, id primitive.ObjectID, nonce int) error
 	ConnectWallet(ctx context.Context, userID primitive.ObjectID, address string) error
+	SetRole(ctx context.Context, userID primitive.ObjectID, role domain.Role) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
 
@@ -85,6 +152,11 @@ type WebAuthnService interface {
 	FinishRegistration(ctx context.Context, userID primitive.ObjectID, sessionData webauthn.SessionData, response *protocol.ParsedCredentialCreationData) error
 	BeginAuthentication(ctx context.Context, userID primitive.ObjectID) (*protocol.CredentialAssertion, webauthn.SessionData, error)
 	FinishAuthentication(ctx context.Context, userID primitive.ObjectID, sessionData webauthn.SessionData, response *protocol.ParsedCredentialAssertionData) error
+	BeginDiscoverableLogin(ctx context.Context) (*protocol.CredentialAssertion, webauthn.SessionData, error)
+	FinishDiscoverableLogin(ctx context.Context, sessionData webauthn.SessionData, response *protocol.ParsedCredentialAssertionData) (*domain.User, error)
+	ListPasskeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.UserPasskey, error)
+	RenamePasskey(ctx context.Context, userID primitive.ObjectID, passkeyID primitive.ObjectID, name string) error
+	RevokePasskey(ctx context.Context, userID primitive.ObjectID, passkeyID primitive.ObjectID) error
 }
 
 // SessionService handles session management
@@ -93,6 +165,126 @@ type SessionService interface {
 	GetSession(ctx context.Context, token string) (*domain.Session, error)
 	Update(ctx context.Context, session *domain.Session) error
 	Delete(ctx context.Context, token string) error
+	ListByUser(ctx context.Context, userID string) ([]*domain.Session, error)
+	Revoke(ctx context.Context, userID string, sessionID string) error
+	// RevokeAll ends every session the user has, signing all of their devices out
+	RevokeAll(ctx context.Context, userID string) error
+}
+
+// APITokenService handles personal access tokens for programmatic API access
+type APITokenService interface {
+	// Create issues a new token and returns it together with its plaintext value, which is not stored
+	Create(ctx context.Context, userID primitive.ObjectID, name string, scopes []domain.APITokenScope, ttl time.Duration) (*domain.APIToken, string, error)
+	List(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIToken, error)
+	Revoke(ctx context.Context, userID primitive.ObjectID, tokenID primitive.ObjectID) error
+	// Authenticate resolves a presented token to its record and the owning user's identifier
+	Authenticate(ctx context.Context, token string) (*domain.APIToken, string, error)
+}
+
+// RateLimitService enforces per-route request limits and sign-in lockouts
+type RateLimitService interface {
+	// Allow counts a request by subject against policy
+	Allow(ctx context.Context, policy domain.RateLimitPolicy, subject string) (*domain.RateLimitDecision, error)
+	// CheckLockout returns domain.ErrAccountLocked and the lock's end if subject may not sign in
+	CheckLockout(ctx context.Context, subject string) (time.Time, error)
+	// RecordFailure counts a failed sign-in and returns the lock's end if it locked the account
+	RecordFailure(ctx context.Context, subject string) (time.Time, error)
+	// RecordSuccess clears the failure count after a successful sign-in
+	RecordSuccess(ctx context.Context, subject string) error
+}
+
+// ModerationService handles user reports and the moderator workflow around them
+type ModerationService interface {
+	// Report files a user report, opening a case for the content or joining its open one
+	Report(ctx context.Context, reporter *domain.User, contentType domain.ContentType, contentID string, reason domain.ReportReason, details string) (*domain.Report, error)
+	// Flag opens a case on behalf of an automated screener rather than a user
+	Flag(ctx context.Context, contentType domain.ContentType, contentID string, authorID string, result *domain.ScreeningResult) (*domain.ModerationCase, error)
+	ListQueue(ctx context.Context, statuses []domain.CaseStatus) ([]*domain.ModerationCase, error)
+	GetCase(ctx context.Context, caseID primitive.ObjectID) (*domain.ModerationCase, []*domain.Report, []*domain.ModerationLogEntry, error)
+	Claim(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID) (*domain.ModerationCase, error)
+	Escalate(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID, note string) (*domain.ModerationCase, error)
+	// Resolve applies the chosen action and closes a case the moderator has claimed
+	Resolve(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID, action domain.ModerationAction, note string, suspendFor time.Duration) (*domain.ModerationCase, error)
+	Reopen(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID, note string) (*domain.ModerationCase, error)
+}
+
+// RelayerService sends subsidized Diamond calls on behalf of users, who sign
+// the call with EIP-712 instead of paying gas themselves
+type RelayerService interface {
+	// Domain is the EIP-712 domain requests must be signed under
+	Domain() domain.RelayDomain
+	// NextNonce returns the nonce the address's next request must use
+	NextNonce(ctx context.Context, address string) (uint64, error)
+	// Submit checks the request and the user's subsidies and sends the transaction
+	Submit(ctx context.Context, user *domain.User, req *domain.RelayRequest) (*domain.RelayedTransaction, error)
+	// SubmitOperatorCall sends a Diamond call on the operator's own behalf, such as a subsidy batch, within the operator's budget
+	SubmitOperatorCall(ctx context.Context, operation domain.RelayOperation, calldata []byte) (*domain.RelayedTransaction, error)
+	Get(ctx context.Context, userID primitive.ObjectID, id string) (*domain.RelayedTransaction, error)
+	List(ctx context.Context, userID primitive.ObjectID) ([]*domain.RelayedTransaction, error)
+	// ProcessPending records receipts, retries unsent requests and replaces stuck transactions
+	ProcessPending(ctx context.Context) error
+	// Run calls ProcessPending every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// SubsidyService lets admins decide whose gas the platform pays and keeps
+// User.SubsidizedOps in step with the PermissionsFacet
+type SubsidyService interface {
+	// SetSubsidies grants or revokes operations for a user and queues the change for the chain
+	SetSubsidies(ctx context.Context, admin *domain.User, userID string, operations []string, granted bool) ([]*domain.SubsidyChange, error)
+	ListChanges(ctx context.Context, status domain.SubsidyChangeStatus) ([]*domain.SubsidyChange, error)
+	SetBudget(ctx context.Context, admin *domain.User, operator string, monthlyLimitGwei int64) (*domain.OperatorBudget, error)
+	RemoveBudget(ctx context.Context, operator string) error
+	// Report returns subsidy counts and each operator's spending this month
+	Report(ctx context.Context) (*domain.SubsidyReport, error)
+	// Sync sends queued changes, settles sent ones and applies new SubsidyStatusChanged events
+	Sync(ctx context.Context) error
+	// Run calls Sync every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// AnchorService commits Merkle roots over new expressions and
+// acknowledgements to the chain and serves each record's inclusion proof
+type AnchorService interface {
+	// Anchor settles sent batches, resends failed ones and batches records created since the last batch
+	Anchor(ctx context.Context) error
+	GetInclusionProof(ctx context.Context, documentID string) (*domain.InclusionProof, error)
+	// Run calls Anchor every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// CredentialService issues W3C Verifiable Credentials for completed proofs of
+// peacemaking and verifies them
+type CredentialService interface {
+	// Issue returns the credential for a minted proof NFT or an accepted proof
+	// request, issuing it on first use. Only the two parties may ask for it.
+	Issue(ctx context.Context, user *domain.User, source domain.CredentialSource, sourceID string) (*domain.Credential, error)
+	List(ctx context.Context, user *domain.User) ([]*domain.Credential, error)
+	// Get returns a credential held by user
+	Get(ctx context.Context, user *domain.User, id string) (*domain.Credential, error)
+	// Verify checks a JWT-VC's signature and that the credential has not been
+	// revoked because its proof was withdrawn or its acknowledgement refuted
+	Verify(ctx context.Context, jwt string) *domain.CredentialVerification
+	// DIDDocument is the issuer's did:web document
+	DIDDocument() map[string]any
+	// Context is the JSON-LD context of the credential terms
+	Context() map[string]any
+}
+
+// CertificateService renders printable certificates of completed proofs of
+// peacemaking
+type CertificateService interface {
+	// Render draws the certificate of a minted proof NFT or accepted proof
+	// request for one of its two parties
+	Render(ctx context.Context, user *domain.User, source domain.CredentialSource, sourceID string, format domain.CertificateFormat) ([]byte, error)
+}
+
+// VerificationService describes expressions and completed proofs for their
+// public pages
+type VerificationService interface {
+	GetExpression(ctx context.Context, id string) (*domain.PublicRecord, error)
+	// GetRecord looks id up as an expression, a proof NFT or a proof request
+	GetRecord(ctx context.Context, id string) (*domain.PublicRecord, error)
 }
 
 // StatisticsService handles system statistics
@@ -3,28 +3,50 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/chain"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
+// attestationClockSkew is how far ahead of the server's clock a signing
+// time may be
+const attestationClockSkew = 5 * time.Minute
+
 type acknowledgementService struct {
 	acknowledgementRepo ports.AcknowledgementRepository
+	pairingService      ports.ExpressionAcknowledgementService
 }
 
-func NewAcknowledgementService(acknowledgementRepo ports.AcknowledgementRepository) ports.AcknowledgementService {
+func NewAcknowledgementService(acknowledgementRepo ports.AcknowledgementRepository, pairingService ports.ExpressionAcknowledgementService) ports.AcknowledgementService {
 	return &acknowledgementService{
 		acknowledgementRepo: acknowledgementRepo,
+		pairingService:      pairingService,
 	}
 }
 
 func (s *acknowledgementService) Create(ctx context.Context, acknowledgement *domain.Acknowledgement) error {
+	ctx, span := tracing.Start(ctx, "AcknowledgementService.Create")
+	defer span.End()
+
+	if err := s.checkAttestation(acknowledgement); err != nil {
+		return err
+	}
 	if err := s.acknowledgementRepo.Create(ctx, acknowledgement); err != nil {
 		return fmt.Errorf("failed to create acknowledgement: %w", err)
 	}
+	s.syncPairing(ctx, acknowledgement)
 	return nil
 }
 
 func (s *acknowledgementService) ListByExpression(ctx context.Context, expressionID string) ([]*domain.Acknowledgement, error) {
+	ctx, span := tracing.Start(ctx, "AcknowledgementService.ListByExpression")
+	defer span.End()
+
 	acknowledgements, err := s.acknowledgementRepo.FindByExpression(ctx, expressionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list acknowledgements by expression: %w", err)
@@ -33,6 +55,9 @@ func (s *acknowledgementService) ListByExpression(ctx context.Context, expressio
 }
 
 func (s *acknowledgementService) ListByUser(ctx context.Context, userID string) ([]*domain.Acknowledgement, error) {
+	ctx, span := tracing.Start(ctx, "AcknowledgementService.ListByUser")
+	defer span.End()
+
 	acknowledgements, err := s.acknowledgementRepo.FindByAcknowledger(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list acknowledgements by user: %w", err)
@@ -41,16 +66,151 @@ func (s *acknowledgementService) ListByUser(ctx context.Context, userID string)
 }
 
 func (s *acknowledgementService) Update(ctx context.Context, acknowledgement *domain.Acknowledgement) error {
+	ctx, span := tracing.Start(ctx, "AcknowledgementService.Update")
+	defer span.End()
+
+	if err := s.checkAttestation(acknowledgement); err != nil {
+		return err
+	}
 	if err := s.acknowledgementRepo.Update(ctx, acknowledgement); err != nil {
 		return fmt.Errorf("failed to update acknowledgement: %w", err)
 	}
+	s.syncPairing(ctx, acknowledgement)
 	return nil
 }
 
+// syncPairing keeps the acknowledgement's pairing in step. The acknowledgement
+// is already stored, so a failure is logged and repaired by the startup backfill.
+func (s *acknowledgementService) syncPairing(ctx context.Context, acknowledgement *domain.Acknowledgement) {
+	if err := s.pairingService.Sync(ctx, acknowledgement); err != nil {
+		slog.ErrorContext(ctx, "failed to sync acknowledgement pairing", "acknowledgement_id", acknowledgement.ID.Hex(), "error", err)
+	}
+}
+
 func (s *acknowledgementService) ListByStatus(ctx context.Context, status domain.AcknowledgementStatus) ([]*domain.Acknowledgement, error) {
+	ctx, span := tracing.Start(ctx, "AcknowledgementService.ListByStatus")
+	defer span.End()
+
 	acknowledgements, err := s.acknowledgementRepo.FindByStatus(ctx, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list acknowledgements by status: %w", err)
 	}
 	return acknowledgements, nil
 }
+
+func (s *acknowledgementService) VerifyAttestation(ctx context.Context, message domain.AttestationMessage, signature string) (*domain.AttestationVerification, error) {
+	_, span := tracing.Start(ctx, "AcknowledgementService.VerifyAttestation")
+	defer span.End()
+
+	if !common.IsHexAddress(message.Acknowledger) {
+		return nil, domain.Validation("invalid acknowledger address")
+	}
+	return verifyAttestation(message, signature), nil
+}
+
+func (s *acknowledgementService) GetAttestation(ctx context.Context, id string) (*domain.AttestationVerification, error) {
+	ctx, span := tracing.Start(ctx, "AcknowledgementService.GetAttestation")
+	defer span.End()
+
+	acknowledgement, err := s.acknowledgementRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if acknowledgement == nil || !acknowledgement.ModerationStatus.IsPubliclyVisible() {
+		return nil, domain.NotFound("acknowledgement not found")
+	}
+	if acknowledgement.Attestation == nil {
+		return nil, domain.ErrAttestationNotFound
+	}
+
+	verification := verifyAttestation(attestationMessage(acknowledgement), acknowledgement.Attestation.Signature)
+	verification.AcknowledgementID = acknowledgement.ID.Hex()
+	if verification.Valid {
+		// The signature stays valid for the content signed, so also check
+		// that the content has not changed since
+		contentHash, err := chain.ContentHash(acknowledgement.Content)
+		if err != nil {
+			return nil, err
+		}
+		if contentHash.Hex() != acknowledgement.Attestation.ContentHash {
+			verification.Valid = false
+			verification.Reason = "the acknowledgement's content has changed since it was signed"
+		}
+	}
+	return verification, nil
+}
+
+// checkAttestation fills in the content hash of a signed acknowledgement and
+// makes sure the signature covers it as it will be stored. Unsigned
+// acknowledgements pass unchanged.
+func (s *acknowledgementService) checkAttestation(acknowledgement *domain.Acknowledgement) error {
+	attestation := acknowledgement.Attestation
+	if attestation == nil {
+		return nil
+	}
+	if attestation.Signer == "" {
+		return domain.ErrAttestationNeedsWallet
+	}
+	if !common.IsHexAddress(attestation.Signer) {
+		return domain.Validation("invalid signer address")
+	}
+	if attestation.Timestamp <= 0 {
+		return domain.Validation("attestation timestamp is required")
+	}
+	if time.Unix(attestation.Timestamp, 0).After(time.Now().Add(attestationClockSkew)) {
+		return domain.ErrAttestationFromFuture
+	}
+
+	contentHash, err := chain.ContentHash(acknowledgement.Content)
+	if err != nil {
+		return err
+	}
+	attestation.Signer = common.HexToAddress(attestation.Signer).Hex()
+	attestation.ContentHash = contentHash.Hex()
+
+	if !verifyAttestation(attestationMessage(acknowledgement), attestation.Signature).Valid {
+		return domain.ErrAttestationInvalid
+	}
+	return nil
+}
+
+// attestationMessage is what the acknowledger of a signed acknowledgement signed
+func attestationMessage(acknowledgement *domain.Acknowledgement) domain.AttestationMessage {
+	return domain.AttestationMessage{
+		ExpressionID: acknowledgement.ExpressionID,
+		Acknowledger: acknowledgement.Attestation.Signer,
+		ContentHash:  acknowledgement.Attestation.ContentHash,
+		Status:       acknowledgement.Status,
+		Timestamp:    acknowledgement.Attestation.Timestamp,
+	}
+}
+
+// verifyAttestation checks that signature over message was made by the
+// acknowledger named in it
+func verifyAttestation(message domain.AttestationMessage, signature string) *domain.AttestationVerification {
+	verification := &domain.AttestationVerification{
+		Message:   message,
+		Signature: signature,
+	}
+
+	digest, err := chain.HashAttestation(message)
+	if err != nil {
+		verification.Reason = "the message is malformed"
+		return verification
+	}
+	verification.Digest = digest.Hex()
+
+	signer, err := chain.RecoverSigner(digest, signature)
+	if err != nil {
+		verification.Reason = "the signature is malformed"
+		return verification
+	}
+	verification.Signer = signer.Hex()
+	if signer != common.HexToAddress(message.Acknowledger) {
+		verification.Reason = "the signature was not made by the acknowledger"
+		return verification
+	}
+
+	verification.Valid = true
+	return verification
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// anchorSettleDelay keeps the newest records out of a batch, so one saved
+// with a creation time just before the window closed is not skipped
+const anchorSettleDelay = time.Minute
+
+type anchorService struct {
+	anchorRepo          ports.AnchorRepository
+	expressionRepo      ports.ExpressionRepository
+	acknowledgementRepo ports.AcknowledgementRepository
+	txRepo              ports.RelayedTransactionRepository
+	relayer             ports.RelayerService
+	chainID             int64
+	diamond             common.Address
+}
+
+// NewAnchorService anchors roots through the relayer's operator account on
+// the AnchorFacet of the Diamond at diamond
+func NewAnchorService(
+	anchorRepo ports.AnchorRepository,
+	expressionRepo ports.ExpressionRepository,
+	acknowledgementRepo ports.AcknowledgementRepository,
+	txRepo ports.RelayedTransactionRepository,
+	relayer ports.RelayerService,
+	chainID int64,
+	diamond common.Address,
+) ports.AnchorService {
+	return &anchorService{
+		anchorRepo:          anchorRepo,
+		expressionRepo:      expressionRepo,
+		acknowledgementRepo: acknowledgementRepo,
+		txRepo:              txRepo,
+		relayer:             relayer,
+		chainID:             chainID,
+		diamond:             diamond,
+	}
+}
+
+func (s *anchorService) Anchor(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "AnchorService.Anchor")
+	defer span.End()
+
+	if err := s.settle(ctx); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to settle anchor batches: %w", err)
+	}
+	if err := s.batch(ctx); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to build anchor batch: %w", err)
+	}
+
+	pending, err := s.anchorRepo.FindBatchesByStatus(ctx, domain.AnchorStatusPending)
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	for _, batch := range pending {
+		if err := s.send(ctx, batch); err != nil {
+			tracing.Fail(span, err)
+			return fmt.Errorf("failed to send anchor batch: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *anchorService) GetInclusionProof(ctx context.Context, documentID string) (*domain.InclusionProof, error) {
+	ctx, span := tracing.Start(ctx, "AnchorService.GetInclusionProof")
+	defer span.End()
+
+	proof, err := s.anchorRepo.FindProof(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		kind, _, err := s.findRecord(ctx, documentID)
+		if err != nil {
+			return nil, err
+		}
+		if kind == "" {
+			return nil, domain.NotFound("record not found")
+		}
+		return nil, domain.ErrNotAnchored
+	}
+
+	batch, err := s.anchorRepo.FindBatchByID(ctx, proof.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, domain.ErrNotAnchored
+	}
+
+	inclusion := &domain.InclusionProof{
+		AnchorProof: *proof,
+		Root:        batch.Root,
+		Status:      batch.Status,
+		TxHash:      batch.TxHash,
+		BlockNumber: batch.BlockNumber,
+		ChainID:     s.chainID,
+		Contract:    s.diamond.Hex(),
+	}
+
+	// Rebuild the leaf from the record as it is now; a record changed or
+	// removed since it was anchored no longer matches
+	_, leaf, err := s.findRecord(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	inclusion.Intact = leaf != nil && leaf.Leaf == proof.Leaf
+	return inclusion, nil
+}
+
+func (s *anchorService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Anchor(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to anchor records", "error", err)
+			}
+		}
+	}
+}
+
+// settle records the outcome of sent batches. A batch whose transaction
+// failed goes back to pending to be sent again; its proofs stay valid as the
+// root does not change.
+func (s *anchorService) settle(ctx context.Context) error {
+	batches, err := s.anchorRepo.FindBatchesByStatus(ctx, domain.AnchorStatusSubmitted)
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches {
+		if batch.RelayID == nil {
+			continue
+		}
+		tx, err := s.txRepo.FindByID(ctx, *batch.RelayID)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			continue
+		}
+
+		switch tx.Status {
+		case domain.RelayStatusConfirmed:
+			batch.Status = domain.AnchorStatusConfirmed
+			batch.TxHash = tx.TxHash
+			batch.BlockNumber = tx.BlockNumber
+			batch.Error = ""
+			slog.InfoContext(ctx, "anchor batch confirmed", "batch_id", batch.ID.Hex(), "root", batch.Root, "tx_hash", tx.TxHash)
+		case domain.RelayStatusFailed:
+			batch.Status = domain.AnchorStatusPending
+			batch.RelayID = nil
+			batch.Error = tx.Error
+			slog.WarnContext(ctx, "anchor batch failed, will resend", "batch_id", batch.ID.Hex(), "error", tx.Error)
+		default:
+			continue
+		}
+		if err := s.anchorRepo.UpdateBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch builds a tree over the records created since the last batch and
+// stores it as pending
+func (s *anchorService) batch(ctx context.Context) error {
+	var after time.Time
+	latest, err := s.anchorRepo.LatestBatch(ctx)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		after = latest.WindowEnd
+	}
+	until := time.Now().Add(-anchorSettleDelay)
+	if !until.After(after) {
+		return nil
+	}
+
+	expressions, err := s.expressionRepo.FindCreatedBetween(ctx, after, until)
+	if err != nil {
+		return err
+	}
+	acknowledgements, err := s.acknowledgementRepo.FindCreatedBetween(ctx, after, until)
+	if err != nil {
+		return err
+	}
+
+	proofs := make([]*domain.AnchorProof, 0, len(expressions)+len(acknowledgements))
+	for _, expression := range expressions {
+		proof, err := anchorLeaf(domain.AnchorExpression, expression.ID.Hex(), expression.Content, expression.Creator, expression.CreatedAt)
+		if err != nil {
+			return err
+		}
+		proofs = append(proofs, proof)
+	}
+	for _, acknowledgement := range acknowledgements {
+		proof, err := anchorLeaf(domain.AnchorAcknowledgement, acknowledgement.ID.Hex(), acknowledgement.Content, acknowledgement.Acknowledger, acknowledgement.CreatedAt)
+		if err != nil {
+			return err
+		}
+		proofs = append(proofs, proof)
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	leaves := make([]common.Hash, len(proofs))
+	for i, proof := range proofs {
+		leaves[i] = common.HexToHash(proof.Leaf)
+	}
+	root, paths := chain.BuildMerkleTree(leaves)
+
+	batch := &domain.AnchorBatch{
+		ID:          primitive.NewObjectID(),
+		Root:        root.Hex(),
+		LeafCount:   len(proofs),
+		WindowStart: after,
+		WindowEnd:   until,
+		Status:      domain.AnchorStatusPending,
+	}
+	for i, proof := range proofs {
+		proof.BatchID = batch.ID
+		proof.Proof = make([]string, len(paths[i]))
+		for j, sibling := range paths[i] {
+			proof.Proof[j] = sibling.Hex()
+		}
+	}
+
+	// Proofs are saved first: if creating the batch fails, the next run
+	// covers the same window and replaces them
+	if err := s.anchorRepo.SaveProofs(ctx, proofs); err != nil {
+		return err
+	}
+	if err := s.anchorRepo.CreateBatch(ctx, batch); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "built anchor batch", "batch_id", batch.ID.Hex(), "root", batch.Root, "leaves", batch.LeafCount)
+	return nil
+}
+
+// send commits a batch's root on-chain
+func (s *anchorService) send(ctx context.Context, batch *domain.AnchorBatch) error {
+	data, err := chain.PackAnchorRoot(common.HexToHash(batch.Root), batch.LeafCount)
+	if err != nil {
+		return err
+	}
+	tx, err := s.relayer.SubmitOperatorCall(ctx, domain.RelayAnchorRoot, data)
+	if err != nil {
+		return err
+	}
+
+	batch.Status = domain.AnchorStatusSubmitted
+	batch.RelayID = &tx.ID
+	return s.anchorRepo.UpdateBatch(ctx, batch)
+}
+
+// findRecord looks a document up among expressions and acknowledgements and
+// returns its kind and current leaf, or "" if it does not exist
+func (s *anchorService) findRecord(ctx context.Context, documentID string) (domain.AnchorKind, *domain.AnchorProof, error) {
+	expression, err := s.expressionRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return "", nil, err
+	}
+	if expression != nil {
+		leaf, err := anchorLeaf(domain.AnchorExpression, documentID, expression.Content, expression.Creator, expression.CreatedAt)
+		return domain.AnchorExpression, leaf, err
+	}
+
+	acknowledgement, err := s.acknowledgementRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return "", nil, err
+	}
+	if acknowledgement != nil {
+		leaf, err := anchorLeaf(domain.AnchorAcknowledgement, documentID, acknowledgement.Content, acknowledgement.Acknowledger, acknowledgement.CreatedAt)
+		return domain.AnchorAcknowledgement, leaf, err
+	}
+	return "", nil, nil
+}
+
+// anchorLeaf hashes one record into a leaf
+func anchorLeaf(kind domain.AnchorKind, id string, content map[string]string, creator string, createdAt time.Time) (*domain.AnchorProof, error) {
+	contentHash, err := chain.ContentHash(content)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := chain.AnchorLeaf(string(kind), id, contentHash, creator, createdAt.Unix())
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AnchorProof{
+		DocumentID:  id,
+		Kind:        kind,
+		ContentHash: contentHash.Hex(),
+		Creator:     creator,
+		Timestamp:   createdAt.Unix(),
+		Leaf:        leaf.Hex(),
+	}, nil
+}
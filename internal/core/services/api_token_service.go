@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxAPITokenLifetime caps how long a personal access token may stay valid
+	maxAPITokenLifetime = 365 * 24 * time.Hour
+	// apiTokenTouchInterval limits how often last-used timestamps are written
+	apiTokenTouchInterval = time.Minute
+)
+
+type apiTokenService struct {
+	tokenRepo ports.APITokenRepository
+	userRepo  ports.UserRepository
+}
+
+func NewAPITokenService(tokenRepo ports.APITokenRepository, userRepo ports.UserRepository) ports.APITokenService {
+	return &apiTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *apiTokenService) Create(ctx context.Context, userID primitive.ObjectID, name string, scopes []domain.APITokenScope, ttl time.Duration) (*domain.APIToken, string, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.Create")
+	defer span.End()
+
+	if len(scopes) == 0 {
+		return nil, "", domain.ErrInvalidAPITokenScope
+	}
+	for _, scope := range scopes {
+		if !domain.ValidAPITokenScope(scope) {
+			return nil, "", domain.ErrInvalidAPITokenScope
+		}
+	}
+	if ttl <= 0 || ttl > maxAPITokenLifetime {
+		return nil, "", domain.Validation("token lifetime must be between 1 and 365 days")
+	}
+
+	secret, err := generateSecureToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	plaintext := domain.APITokenPrefix + secret
+
+	token := &domain.APIToken{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Name:      name,
+		TokenHash: domain.HashToken(plaintext),
+		Hint:      plaintext[len(plaintext)-4:],
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, plaintext, nil
+}
+
+func (s *apiTokenService) List(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIToken, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.List")
+	defer span.End()
+
+	tokens, err := s.tokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = []*domain.APIToken{}
+	}
+	return tokens, nil
+}
+
+func (s *apiTokenService) Revoke(ctx context.Context, userID primitive.ObjectID, tokenID primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "APITokenService.Revoke")
+	defer span.End()
+
+	return s.tokenRepo.Revoke(ctx, userID, tokenID)
+}
+
+func (s *apiTokenService) Authenticate(ctx context.Context, plaintext string) (*domain.APIToken, string, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.Authenticate")
+	defer span.End()
+
+	token, err := s.tokenRepo.FindByHash(ctx, domain.HashToken(plaintext))
+	if err != nil {
+		return nil, "", err
+	}
+	if token == nil || !token.IsActive() {
+		return nil, "", domain.Unauthorized("invalid or expired api token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID.Hex())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, "", domain.Unauthorized("invalid api token: user not found")
+	}
+
+	if token.LastUsedAt == nil || time.Since(*token.LastUsedAt) > apiTokenTouchInterval {
+		if err := s.tokenRepo.UpdateLastUsed(ctx, token.ID); err != nil {
+			slog.WarnContext(ctx, "failed to update API token last used", "token_id", token.ID.Hex(), "error", err)
+		}
+	}
+
+	// Handlers identify users the same way session auth does: wallet address first, then email
+	if user.Address != "" {
+		return token, user.Address, nil
+	}
+	return token, user.Email, nil
+}
@@ -8,10 +8,11 @@ import (
 	"math/big"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 	"strings"
 	"time"
 
-	"log"
+	"log/slog"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -19,15 +20,26 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// sessionLifetime is the absolute lifetime of a login session
+	sessionLifetime = 24 * time.Hour
+	// sessionIdleTimeout ends a session that has not been used for this long
+	sessionIdleTimeout = 2 * time.Hour
+	// sessionTouchInterval limits how often activity is written back to the session
+	sessionTouchInterval = time.Minute
+)
+
 type authService struct {
-	userService ports.UserService
-	sessionRepo ports.SessionRepository
+	userService       ports.UserService
+	sessionRepo       ports.SessionRepository
+	invitationService ports.InvitationService
 }
 
-func NewAuthService(userService ports.UserService, sessionRepo ports.SessionRepository) ports.AuthService {
+func NewAuthService(userService ports.UserService, sessionRepo ports.SessionRepository, invitationService ports.InvitationService) ports.AuthService {
 	return &authService{
-		userService: userService,
-		sessionRepo: sessionRepo,
+		userService:       userService,
+		sessionRepo:       sessionRepo,
+		invitationService: invitationService,
 	}
 }
 
@@ -39,8 +51,46 @@ func generateSecureToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// createSession issues a new login session for the user and returns its token
+func (s *authService) createSession(ctx context.Context, userID primitive.ObjectID, address string) (string, error) {
+	sessionToken, err := generateSecureToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	client := domain.ClientInfoFromContext(ctx)
+	now := time.Now()
+	session := &domain.Session{
+		ID:         primitive.NewObjectID(),
+		UserID:     userID.Hex(),
+		Token:      sessionToken,
+		Address:    address,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		LastSeenAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ExpiresAt:  now.Add(sessionLifetime),
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return sessionToken, nil
+}
+
+// linkInvitations hands a newly registered user the invitations that were
+// waiting for their email or wallet. Registration succeeds even if this fails.
+func (s *authService) linkInvitations(ctx context.Context, user *domain.User) {
+	if err := s.invitationService.LinkUser(ctx, user); err != nil {
+		slog.ErrorContext(ctx, "failed to link invitations to new user", "user_id", user.ID.Hex(), "error", err)
+	}
+}
+
 func (s *authService) GenerateNonce(ctx context.Context, address string) (int, error) {
-	log.Printf("[AUTH] Generating nonce for address: %s", address)
+	ctx, span := tracing.Start(ctx, "AuthService.GenerateNonce")
+	defer span.End()
 
 	// Generate random nonce
 	max := big.NewInt(1000000)
@@ -53,18 +103,16 @@ func (s *authService) GenerateNonce(ctx context.Context, address string) (int, e
 	// Find user
 	user, err := s.userService.GetUserByAddress(ctx, address)
 	if err != nil {
-		log.Printf("[AUTH] Error finding user: %v", err)
+		slog.ErrorContext(ctx, "failed to find user", "error", err)
 		return 0, fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// If user exists, update their nonce
 	if user != nil {
-		log.Printf("[AUTH] Found existing user with ID: %s", user.ID.Hex())
 		if err := s.userService.UpdateNonce(ctx, user.ID, nonce); err != nil {
-			log.Printf("[AUTH] Error updating nonce: %v", err)
+			slog.ErrorContext(ctx, "failed to update nonce", "error", err)
 			return 0, fmt.Errorf("failed to update nonce: %w", err)
 		}
-		log.Printf("[AUTH] Updated nonce to %d for user %s", nonce, user.ID.Hex())
 	} else {
 		// Create new user if not exists
 		// Generate a valid username from the address (e.g., "0x844a54d19d")
@@ -79,29 +127,30 @@ func (s *authService) GenerateNonce(ctx context.Context, address string) (int, e
 			UpdatedAt: time.Now(),
 		}
 		if err := s.userService.Create(ctx, user); err != nil {
-			log.Printf("[AUTH] Error creating user: %v", err)
+			slog.ErrorContext(ctx, "failed to create user", "error", err)
 			return 0, fmt.Errorf("failed to create user: %w", err)
 		}
-		log.Printf("[AUTH] Created new user with ID: %s and nonce: %d", user.ID.Hex(), nonce)
+		slog.InfoContext(ctx, "created wallet user", "user_id", user.ID.Hex())
+		s.linkInvitations(ctx, user)
 	}
 
 	return nonce, nil
 }
 
 func (s *authService) VerifySignature(ctx context.Context, address string, signature string) (bool, string, error) {
-	log.Printf("[AUTH] Verifying signature for address: %s", address)
+	ctx, span := tracing.Start(ctx, "AuthService.VerifySignature")
+	defer span.End()
 
 	// Get user and their nonce
 	user, err := s.userService.GetUserByAddress(ctx, address)
 	if err != nil {
-		log.Printf("[AUTH] Error finding user: %v", err)
+		slog.ErrorContext(ctx, "failed to find user", "error", err)
 		return false, "", fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		log.Printf("[AUTH] User not found for address: %s", address)
-		return false, "", fmt.Errorf("user not found")
+		slog.InfoContext(ctx, "signature verification for unknown address", "address", address)
+		return false, "", domain.NotFound("user not found")
 	}
-	log.Printf("[AUTH] Found user with ID: %s", user.ID.Hex())
 
 	// Store current nonce for verification
 	currentNonce := user.Nonce
@@ -116,7 +165,7 @@ func (s *authService) VerifySignature(ctx context.Context, address string, signa
 	// Decode signature
 	decodedSig := hexutil.MustDecode(signature)
 	if len(decodedSig) != 65 {
-		return false, "", fmt.Errorf("invalid signature length")
+		return false, "", domain.Validation("invalid signature length")
 	}
 
 	// Extract r, s, v from signature
@@ -134,7 +183,7 @@ func (s *authService) VerifySignature(ctx context.Context, address string, signa
 	// Recover public key
 	pubKeyECDSA, err := crypto.SigToPub(messageHash, signatureBytes)
 	if err != nil {
-		log.Printf("[AUTH] Failed to recover public key: %v", err)
+		slog.InfoContext(ctx, "failed to recover public key", "error", err)
 		return false, "", fmt.Errorf("failed to recover public key: %w", err)
 	}
 
@@ -143,8 +192,8 @@ func (s *authService) VerifySignature(ctx context.Context, address string, signa
 
 	// Compare addresses (case-insensitive)
 	if !strings.EqualFold(recoveredAddr.Hex(), address) {
-		log.Printf("[AUTH] Address mismatch - Recovered: %s, Expected: %s", recoveredAddr.Hex(), address)
-		return false, "", fmt.Errorf("signature does not match address")
+		slog.InfoContext(ctx, "signature does not match address", "user_id", user.ID.Hex())
+		return false, "", domain.Unauthorized("signature does not match address")
 	}
 
 	// Only update nonce after successful verification
@@ -158,31 +207,19 @@ func (s *authService) VerifySignature(ctx context.Context, address string, signa
 		return false, "", fmt.Errorf("failed to update nonce: %w", err)
 	}
 
-	// Create session
-	sessionToken, err := generateSecureToken()
+	sessionToken, err := s.createSession(ctx, user.ID, address)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to generate session token: %w", err)
-	}
-
-	// Create session
-	session := &domain.Session{
-		ID:        primitive.NewObjectID(),
-		UserID:    user.ID.Hex(),
-		Token:     sessionToken,
-		Address:   address,
-		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-	}
-
-	if err := s.sessionRepo.Create(ctx, session); err != nil {
-		return false, "", fmt.Errorf("failed to create session: %w", err)
+		return false, "", err
 	}
 
-	log.Printf("[AUTH] Successfully verified signature and created session for address: %s", address)
+	slog.InfoContext(ctx, "wallet sign-in", "user_id", user.ID.Hex())
 	return true, sessionToken, nil
 }
 
 func (s *authService) Register(ctx context.Context, address string, email string) (*domain.User, string, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	user, err := s.userService.GetUserByAddress(ctx, address)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to find user: %w", err)
@@ -206,108 +243,127 @@ func (s *authService) Register(ctx context.Context, address string, email string
 			return nil, "", fmt.Errorf("failed to update user: %w", err)
 		}
 	}
+	s.linkInvitations(ctx, user)
 
-	// Create session
-	sessionToken, err := generateSecureToken()
+	sessionToken, err := s.createSession(ctx, user.ID, address)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate session token: %w", err)
-	}
-
-	session := &domain.Session{
-		ID:        primitive.NewObjectID(),
-		UserID:    user.ID.Hex(),
-		Token:     sessionToken,
-		Address:   address,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	if err := s.sessionRepo.Create(ctx, session); err != nil {
-		return nil, "", fmt.Errorf("failed to create session: %w", err)
+		return nil, "", err
 	}
 
 	return user, sessionToken, nil
 }
 
 func (s *authService) VerifyToken(ctx context.Context, token string) (string, error) {
-	// log.Printf("[AUTH] Verifying token")
+	ctx, span := tracing.Start(ctx, "AuthService.VerifyToken")
+	defer span.End()
+
 	session, err := s.sessionRepo.FindByToken(ctx, token)
 	if err != nil {
-		log.Printf("[AUTH] Error finding session: %v", err)
+		slog.ErrorContext(ctx, "failed to find session", "error", err)
 		return "", fmt.Errorf("failed to find session: %w", err)
 	}
 	if session == nil {
-		log.Printf("[AUTH] Session not found")
-		return "", fmt.Errorf("invalid or expired session")
+		slog.DebugContext(ctx, "session not found")
+		return "", domain.Unauthorized("invalid or expired session")
 	}
 	if session.ExpiresAt.Before(time.Now()) {
-		log.Printf("[AUTH] Session expired")
-		return "", fmt.Errorf("session expired")
+		slog.DebugContext(ctx, "session expired", "session_id", session.ID.Hex())
+		return "", domain.Unauthorized("session expired")
+	}
+	if time.Since(session.LastSeenAt) > sessionIdleTimeout {
+		slog.DebugContext(ctx, "session idle for too long", "session_id", session.ID.Hex())
+		if err := s.sessionRepo.DeleteByToken(ctx, token); err != nil {
+			slog.ErrorContext(ctx, "failed to delete idle session", "error", err)
+		}
+		return "", domain.Unauthorized("session expired")
+	}
+
+	// Slide the idle window forward, writing at most once per interval
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessionRepo.Touch(ctx, session.ID, domain.ClientInfoFromContext(ctx)); err != nil {
+			slog.WarnContext(ctx, "failed to update session activity", "error", err)
+		}
 	}
 
 	// Get user from session
 	user, err := s.userService.GetUserByID(ctx, session.UserID)
 	if err != nil {
-		log.Printf("[AUTH] Error finding user: %v", err)
+		slog.ErrorContext(ctx, "failed to find user", "error", err)
 		return "", fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		log.Printf("[AUTH] User not found")
-		return "", fmt.Errorf("invalid session: user not found")
+		slog.WarnContext(ctx, "session refers to missing user", "session_id", session.ID.Hex())
+		return "", domain.Unauthorized("invalid session: user not found")
 	}
 
 	// For wallet auth, return address
 	if session.Address != "" {
-		// log.Printf("[AUTH] Session verified for wallet address: %s", session.Address)
 		return session.Address, nil
 	}
 
 	// For email auth, return email
 	if user.Email != "" {
-		// log.Printf("[AUTH] Session verified for email: %s", user.Email)
 		return user.Email, nil
 	}
 
-	return "", fmt.Errorf("invalid session: no authentication method found")
+	return "", domain.Unauthorized("invalid session: no authentication method found")
 }
 
 func (s *authService) Logout(ctx context.Context, token string) error {
-	// Find the session
+	ctx, span := tracing.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	if err := s.sessionRepo.DeleteByToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to invalidate session: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSession replaces the token of an existing session and returns the new
+// one. Call it whenever the session's privileges change so a token captured
+// earlier stops working.
+func (s *authService) RotateSession(ctx context.Context, token string) (string, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RotateSession")
+	defer span.End()
+
 	session, err := s.sessionRepo.FindByToken(ctx, token)
 	if err != nil {
-		return fmt.Errorf("failed to find session: %w", err)
+		return "", fmt.Errorf("failed to find session: %w", err)
 	}
 	if session == nil {
-		return nil // Session already invalid, nothing to do
+		return "", domain.Unauthorized("invalid or expired session")
 	}
 
-	// Invalidate the session by setting expiry to now
-	session.ExpiresAt = time.Now()
-	session.UpdatedAt = time.Now()
+	newToken, err := generateSecureToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
 
-	// Update the session in the database
-	if err := s.sessionRepo.Update(ctx, session); err != nil {
-		return fmt.Errorf("failed to invalidate session: %w", err)
+	if err := s.sessionRepo.UpdateToken(ctx, session.ID, newToken); err != nil {
+		return "", fmt.Errorf("failed to rotate session: %w", err)
 	}
 
-	return nil
+	return newToken, nil
 }
 
 func (s *authService) RegisterWithEmail(ctx context.Context, email string, password string, username string) (*domain.User, string, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RegisterWithEmail")
+	defer span.End()
+
 	// Validate email format
 	if !strings.Contains(email, "@") {
-		return nil, "", fmt.Errorf("invalid email format")
+		return nil, "", domain.Validation("invalid email format")
 	}
 
 	// Validate username length
 	if len(username) < 3 || len(username) > 30 {
-		return nil, "", fmt.Errorf("username must be between 3 and 30 characters")
+		return nil, "", domain.Validation("username must be between 3 and 30 characters")
 	}
 
 	// Validate password strength
 	if len(password) < 8 {
-		return nil, "", fmt.Errorf("password must be at least 8 characters")
+		return nil, "", domain.Validation("password must be at least 8 characters")
 	}
 
 	// Check if email already exists (case-insensitive)
@@ -316,7 +372,7 @@ func (s *authService) RegisterWithEmail(ctx context.Context, email string, passw
 		return nil, "", fmt.Errorf("error checking email: %w", err)
 	}
 	if existingUser != nil {
-		return nil, "", fmt.Errorf("email already registered")
+		return nil, "", domain.Conflict("email already registered")
 	}
 
 	// Check if username already exists (case-insensitive)
@@ -325,7 +381,7 @@ func (s *authService) RegisterWithEmail(ctx context.Context, email string, passw
 		return nil, "", fmt.Errorf("error checking username: %w", err)
 	}
 	if existingUser != nil {
-		return nil, "", fmt.Errorf("username already taken")
+		return nil, "", domain.Conflict("username already taken")
 	}
 
 	// Hash password
@@ -347,58 +403,34 @@ func (s *authService) RegisterWithEmail(ctx context.Context, email string, passw
 	if err := s.userService.Create(ctx, user); err != nil {
 		return nil, "", fmt.Errorf("error creating user: %w", err)
 	}
+	s.linkInvitations(ctx, user)
 
-	// Create session
-	sessionToken, err := generateSecureToken()
+	sessionToken, err := s.createSession(ctx, user.ID, "")
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate session token: %w", err)
-	}
-
-	session := &domain.Session{
-		ID:        primitive.NewObjectID(),
-		UserID:    user.ID.Hex(),
-		Token:     sessionToken,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-	}
-
-	if err := s.sessionRepo.Create(ctx, session); err != nil {
-		return nil, "", fmt.Errorf("failed to create session: %w", err)
+		return nil, "", err
 	}
 
 	return user, sessionToken, nil
 }
 
 func (s *authService) LoginWithEmail(ctx context.Context, email string, password string) (*domain.User, string, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.LoginWithEmail")
+	defer span.End()
+
 	// Get user by email
 	user, err := s.userService.GetUserByEmail(ctx, email)
-	if err != nil {
-		return nil, "", fmt.Errorf("invalid email or password")
+	if err != nil || user == nil {
+		return nil, "", domain.Unauthorized("invalid email or password")
 	}
 
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, "", fmt.Errorf("invalid email or password")
+		return nil, "", domain.Unauthorized("invalid email or password")
 	}
 
-	// Create session
-	sessionToken, err := generateSecureToken()
+	sessionToken, err := s.createSession(ctx, user.ID, "")
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate session token")
-	}
-
-	session := &domain.Session{
-		ID:        primitive.NewObjectID(),
-		UserID:    user.ID.Hex(),
-		Token:     sessionToken,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-	}
-
-	if err := s.sessionRepo.Create(ctx, session); err != nil {
 		return nil, "", fmt.Errorf("failed to create session")
 	}
 
@@ -406,6 +438,9 @@ func (s *authService) LoginWithEmail(ctx context.Context, email string, password
 }
 
 func (s *authService) DeleteAllUserSessions(ctx context.Context, userIdentifier string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.DeleteAllUserSessions")
+	defer span.End()
+
 	// Get user by email or address
 	var user *domain.User
 	var err error
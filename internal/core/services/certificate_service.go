@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/certificate"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+)
+
+type certificateService struct {
+	proofSources
+	userRepo ports.UserRepository
+	siteURL  string
+}
+
+// NewCertificateService renders certificates whose QR codes link to pages
+// under siteURL
+func NewCertificateService(
+	proofNFTRepo ports.ProofNFTRepository,
+	proofRequestRepo ports.ProofRequestRepository,
+	expressionRepo ports.ExpressionRepository,
+	userRepo ports.UserRepository,
+	siteURL string,
+) ports.CertificateService {
+	return &certificateService{
+		proofSources: proofSources{
+			proofNFTRepo:     proofNFTRepo,
+			proofRequestRepo: proofRequestRepo,
+			expressionRepo:   expressionRepo,
+		},
+		userRepo: userRepo,
+		siteURL:  siteURL,
+	}
+}
+
+func (s *certificateService) Render(ctx context.Context, user *domain.User, source domain.CredentialSource, sourceID string, format domain.CertificateFormat) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "CertificateService.Render")
+	defer span.End()
+
+	if format != domain.CertificatePDF && format != domain.CertificatePNG {
+		return nil, domain.Validation("certificate format must be pdf or png")
+	}
+	proof, err := s.load(ctx, source, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if !proof.heldBy(user.ID.Hex()) {
+		return nil, domain.Forbidden("only the two parties of a proof can get its certificate")
+	}
+
+	expression, err := s.expressionRepo.FindByID(ctx, proof.expressionID)
+	if err != nil {
+		return nil, err
+	}
+	if expression == nil {
+		return nil, domain.NotFound("expression not found")
+	}
+
+	cert := &domain.Certificate{
+		Source:    source,
+		SourceID:  sourceID,
+		Excerpt:   certificate.Excerpt(expression.Content["text"]),
+		TokenID:   proof.tokenID,
+		IPFSHash:  proof.ipfsHash,
+		Date:      proof.date,
+		VerifyURL: s.siteURL + "/verify/" + sourceID,
+	}
+	if cert.IPFSHash == "" {
+		cert.IPFSHash = expression.IPFSHash
+	}
+	for _, party := range []struct{ id, role string }{
+		{proof.creatorID, "creator"},
+		{proof.acknowledgerID, "acknowledger"},
+	} {
+		certParty, err := s.party(ctx, party.id, party.role)
+		if err != nil {
+			return nil, err
+		}
+		cert.Parties = append(cert.Parties, *certParty)
+	}
+
+	data, err := certificate.Render(cert, format)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return data, nil
+}
+
+func (s *certificateService) party(ctx context.Context, userID, role string) (*domain.CertificateParty, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	party := &domain.CertificateParty{Role: role}
+	if user == nil {
+		return party, nil
+	}
+
+	party.Name = user.DisplayName
+	if party.Name == "" {
+		party.Name = user.Username
+	}
+	if country, ok := domain.CountriesMap[user.Citizenship]; ok {
+		party.Country = user.Citizenship
+		party.CountryName = country.Name
+		party.Flag = country.Flag
+	}
+	return party, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+)
+
+// proofSources reads the records a proof of peacemaking can be completed by
+type proofSources struct {
+	proofNFTRepo     ports.ProofNFTRepository
+	proofRequestRepo ports.ProofRequestRepository
+	expressionRepo   ports.ExpressionRepository
+}
+
+// completedProof is the part of a minted proof NFT or accepted proof request
+// that credentials and certificates are made from
+type completedProof struct {
+	expressionID   string
+	creatorID      string
+	acknowledgerID string
+	date           time.Time
+	tokenID        int
+	// ipfsHash is the CID of the NFT's metadata, for minted proofs
+	ipfsHash string
+	// pairingID is the expression-acknowledgement pairing the proof came from, if linked
+	pairingID string
+}
+
+func (p *completedProof) heldBy(userID string) bool {
+	return userID == p.creatorID || userID == p.acknowledgerID
+}
+
+// load reads a proof NFT or proof request and checks that it is complete
+func (s *proofSources) load(ctx context.Context, source domain.CredentialSource, sourceID string) (*completedProof, error) {
+	switch source {
+	case domain.CredentialSourceProofNFT:
+		nft, err := s.proofNFTRepo.FindByID(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if nft == nil {
+			return nil, domain.NotFound("proof NFT not found")
+		}
+		if nft.MintedAt == nil {
+			return nil, domain.ErrCredentialNotEligible
+		}
+		creatorID, err := s.expressionCreator(ctx, nft.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return &completedProof{
+			expressionID:   nft.Expression,
+			creatorID:      creatorID,
+			acknowledgerID: nft.Acknowledger,
+			date:           *nft.MintedAt,
+			tokenID:        nft.TokenID,
+			ipfsHash:       nft.IPFSHash,
+			pairingID:      nft.PairingID,
+		}, nil
+
+	case domain.CredentialSourceProofRequest:
+		request, err := s.proofRequestRepo.FindByID(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if request == nil {
+			return nil, domain.NotFound("proof request not found")
+		}
+		if request.Status != domain.ProofRequestAccepted {
+			return nil, domain.ErrCredentialNotEligible
+		}
+		creatorID, err := s.expressionCreator(ctx, request.ExpressionID)
+		if err != nil {
+			return nil, err
+		}
+		// Either side may have initiated the request
+		acknowledgerID := request.PeerID
+		if acknowledgerID == creatorID {
+			acknowledgerID = request.InitiatorID
+		}
+		return &completedProof{
+			expressionID:   request.ExpressionID,
+			creatorID:      creatorID,
+			acknowledgerID: acknowledgerID,
+			date:           request.UpdatedAt,
+			pairingID:      request.PairingID,
+		}, nil
+	}
+	return nil, domain.Validation("unknown credential source %q", source)
+}
+
+func (s *proofSources) expressionCreator(ctx context.Context, expressionID string) (string, error) {
+	expression, err := s.expressionRepo.FindByID(ctx, expressionID)
+	if err != nil {
+		return "", err
+	}
+	if expression == nil {
+		return "", domain.NotFound("expression not found")
+	}
+	return expression.Creator, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"proofofpeacemaking/internal/tracing"
 	"strings"
 )
 
@@ -43,6 +44,9 @@ func NewCountryService() *countryService {
 }
 
 func (s *countryService) SearchCountries(ctx context.Context, query string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.SearchCountries")
+	defer span.End()
+
 	if query == "" {
 		return s.countries, nil
 	}
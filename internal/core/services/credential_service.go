@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/credential"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// peacemakingCredentialType is the credential and subject type of a Proof of
+// Peacemaking, defined by the issuer's JSON-LD context
+const peacemakingCredentialType = "ProofOfPeacemaking"
+
+// credentialIDPrefix precedes the stored credential's ID in the id of the VC
+const credentialIDPrefix = "urn:proofofpeacemaking:credential:"
+
+type credentialService struct {
+	proofSources
+	credentialRepo ports.CredentialRepository
+	pairingRepo    ports.ExpressionAcknowledgementRepository
+	userRepo       ports.UserRepository
+	issuer         *credential.Issuer
+}
+
+// NewCredentialService issues credentials signed by issuer
+func NewCredentialService(
+	credentialRepo ports.CredentialRepository,
+	proofNFTRepo ports.ProofNFTRepository,
+	proofRequestRepo ports.ProofRequestRepository,
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	expressionRepo ports.ExpressionRepository,
+	userRepo ports.UserRepository,
+	issuer *credential.Issuer,
+) ports.CredentialService {
+	return &credentialService{
+		proofSources: proofSources{
+			proofNFTRepo:     proofNFTRepo,
+			proofRequestRepo: proofRequestRepo,
+			expressionRepo:   expressionRepo,
+		},
+		credentialRepo: credentialRepo,
+		pairingRepo:    pairingRepo,
+		userRepo:       userRepo,
+		issuer:         issuer,
+	}
+}
+
+func (s *credentialService) Issue(ctx context.Context, user *domain.User, source domain.CredentialSource, sourceID string) (*domain.Credential, error) {
+	ctx, span := tracing.Start(ctx, "CredentialService.Issue")
+	defer span.End()
+
+	existing, err := s.credentialRepo.FindBySource(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if !existing.HeldBy(user.ID.Hex()) {
+			return nil, domain.ErrCredentialNotParty
+		}
+		return existing, nil
+	}
+
+	proof, err := s.load(ctx, source, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if !proof.heldBy(user.ID.Hex()) {
+		return nil, domain.ErrCredentialNotParty
+	}
+
+	subject, err := s.subject(ctx, proof)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	issued := &domain.Credential{
+		ID:        primitive.NewObjectID(),
+		Source:    source,
+		SourceID:  sourceID,
+		HolderIDs: []string{proof.creatorID, proof.acknowledgerID},
+		IssuedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	issued.Credential = domain.VerifiableCredential{
+		Context:           []string{credential.ContextV1, s.issuer.ContextURL()},
+		ID:                credentialIDPrefix + issued.ID.Hex(),
+		Type:              []string{"VerifiableCredential", peacemakingCredentialType},
+		IssuanceDate:      issued.IssuedAt,
+		CredentialSubject: *subject,
+	}
+	issued.JWT, err = s.issuer.Issue(&issued.Credential)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, fmt.Errorf("failed to sign credential: %w", err)
+	}
+
+	if err := s.credentialRepo.Create(ctx, issued); err != nil {
+		// Both parties asked at once; hand out the one that was stored
+		if errors.Is(err, domain.ErrConflict) {
+			return s.credentialRepo.FindBySource(ctx, sourceID)
+		}
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "issued credential", "credential_id", issued.ID.Hex(), "source", source, "source_id", sourceID)
+	return issued, nil
+}
+
+func (s *credentialService) List(ctx context.Context, user *domain.User) ([]*domain.Credential, error) {
+	ctx, span := tracing.Start(ctx, "CredentialService.List")
+	defer span.End()
+
+	return s.credentialRepo.FindByHolder(ctx, user.ID.Hex())
+}
+
+func (s *credentialService) Get(ctx context.Context, user *domain.User, id string) (*domain.Credential, error) {
+	ctx, span := tracing.Start(ctx, "CredentialService.Get")
+	defer span.End()
+
+	issued, err := s.credentialRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Someone else's credential is reported as missing rather than forbidden,
+	// so IDs cannot be probed
+	if issued == nil || !issued.HeldBy(user.ID.Hex()) {
+		return nil, domain.ErrCredentialNotFound
+	}
+	return issued, nil
+}
+
+// Verify checks the signature, then that the credential is still on record
+// and the proof it attests to still stands
+func (s *credentialService) Verify(ctx context.Context, jwt string) *domain.CredentialVerification {
+	ctx, span := tracing.Start(ctx, "CredentialService.Verify")
+	defer span.End()
+
+	result := s.issuer.Verify(jwt)
+	if !result.Valid {
+		return result
+	}
+
+	reason, err := s.revocation(ctx, result.Credential)
+	if err != nil {
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "could not check credential status", "credential_id", result.Credential.ID, "error", err)
+		reason = "the credential's status could not be checked"
+	}
+	if reason != "" {
+		result.Valid = false
+		result.Reason = reason
+	}
+	return result
+}
+
+func (s *credentialService) DIDDocument() map[string]any {
+	return s.issuer.DIDDocument()
+}
+
+func (s *credentialService) Context() map[string]any {
+	return s.issuer.Context()
+}
+
+// revocation returns why a correctly signed credential no longer stands, or ""
+// if it does. A credential is revoked when its record is gone, when its proof
+// request was cancelled or its NFT never minted, or when the acknowledgement
+// it rests on was refuted.
+func (s *credentialService) revocation(ctx context.Context, vc *domain.VerifiableCredential) (string, error) {
+	id, ok := strings.CutPrefix(vc.ID, credentialIDPrefix)
+	if !ok {
+		return "the credential is not on record", nil
+	}
+	issued, err := s.credentialRepo.FindByID(ctx, id)
+	if errors.Is(err, domain.ErrValidation) {
+		return "the credential is not on record", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if issued == nil {
+		return "the credential is not on record", nil
+	}
+
+	proof, err := s.load(ctx, issued.Source, issued.SourceID)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrCredentialNotEligible) {
+		return "the proof it attests to has been withdrawn", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if proof.pairingID != "" {
+		pairing, err := s.pairingRepo.FindByID(ctx, proof.pairingID)
+		if err != nil {
+			return "", err
+		}
+		if pairing != nil && pairing.AcknowledgementStatus == domain.AcknowledgementStatusRefuted {
+			return "the acknowledgement it attests to has been refuted", nil
+		}
+	}
+	return "", nil
+}
+
+// subject describes the proof's parties and expression
+func (s *credentialService) subject(ctx context.Context, proof *completedProof) (*domain.PeacemakingSubject, error) {
+	expression, err := s.expressionRepo.FindByID(ctx, proof.expressionID)
+	if err != nil {
+		return nil, err
+	}
+	if expression == nil {
+		return nil, domain.NotFound("expression not found")
+	}
+	contentHash, err := chain.ContentHash(expression.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := s.party(ctx, proof.creatorID, "creator")
+	if err != nil {
+		return nil, err
+	}
+	acknowledger, err := s.party(ctx, proof.acknowledgerID, "acknowledger")
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PeacemakingSubject{
+		Type:    peacemakingCredentialType,
+		Parties: []domain.CredentialParty{*creator, *acknowledger},
+		Expression: domain.CredentialExpression{
+			ID:          proof.expressionID,
+			ContentHash: contentHash.Hex(),
+		},
+		Date:    proof.date.UTC(),
+		TokenID: proof.tokenID,
+	}, nil
+}
+
+func (s *credentialService) party(ctx context.Context, userID, role string) (*domain.CredentialParty, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	party := &domain.CredentialParty{Role: role}
+	if user == nil {
+		return party, nil
+	}
+
+	party.Name = user.DisplayName
+	if party.Name == "" {
+		party.Name = user.Username
+	}
+	if user.Address != "" {
+		party.ID = "did:pkh:eip155:1:" + common.HexToAddress(user.Address).Hex()
+	}
+	return party, nil
+}
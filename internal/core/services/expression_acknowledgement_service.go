@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+)
+
+type expressionAcknowledgementService struct {
+	pairingRepo         ports.ExpressionAcknowledgementRepository
+	expressionRepo      ports.ExpressionRepository
+	acknowledgementRepo ports.AcknowledgementRepository
+	proofRequestRepo    ports.ProofRequestRepository
+	proofNFTRepo        ports.ProofNFTRepository
+}
+
+func NewExpressionAcknowledgementService(
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	expressionRepo ports.ExpressionRepository,
+	acknowledgementRepo ports.AcknowledgementRepository,
+	proofRequestRepo ports.ProofRequestRepository,
+	proofNFTRepo ports.ProofNFTRepository,
+) ports.ExpressionAcknowledgementService {
+	return &expressionAcknowledgementService{
+		pairingRepo:         pairingRepo,
+		expressionRepo:      expressionRepo,
+		acknowledgementRepo: acknowledgementRepo,
+		proofRequestRepo:    proofRequestRepo,
+		proofNFTRepo:        proofNFTRepo,
+	}
+}
+
+func (s *expressionAcknowledgementService) Sync(ctx context.Context, acknowledgement *domain.Acknowledgement) error {
+	ctx, span := tracing.Start(ctx, "ExpressionAcknowledgementService.Sync")
+	defer span.End()
+
+	if err := s.sync(ctx, acknowledgement); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to sync expression acknowledgement: %w", err)
+	}
+	return nil
+}
+
+func (s *expressionAcknowledgementService) Backfill(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ExpressionAcknowledgementService.Backfill")
+	defer span.End()
+
+	acknowledgements, err := s.acknowledgementRepo.FindCreatedBetween(ctx, time.Time{}, time.Now())
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	skipped := 0
+	for _, acknowledgement := range acknowledgements {
+		// One acknowledgement that cannot be paired, such as one whose
+		// expression was deleted, must not leave the rest unpaired
+		if err := s.sync(ctx, acknowledgement); err != nil {
+			skipped++
+			slog.WarnContext(ctx, "skipping acknowledgement that cannot be paired", "acknowledgement_id", acknowledgement.ID.Hex(), "error", err)
+		}
+	}
+	slog.InfoContext(ctx, "acknowledgement pairings synced", "count", len(acknowledgements)-skipped, "skipped", skipped)
+
+	if err := s.pairProofRequests(ctx); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to pair proof requests: %w", err)
+	}
+	return nil
+}
+
+func (s *expressionAcknowledgementService) ListByUser(ctx context.Context, userID string) ([]*domain.ExpressionAcknowledgement, error) {
+	ctx, span := tracing.Start(ctx, "ExpressionAcknowledgementService.ListByUser")
+	defer span.End()
+
+	return s.pairingRepo.FindByUser(ctx, userID)
+}
+
+// sync creates the acknowledgement's pairing or brings its status up to date
+func (s *expressionAcknowledgementService) sync(ctx context.Context, acknowledgement *domain.Acknowledgement) error {
+	pairing, err := s.pairingRepo.FindByAcknowledgement(ctx, acknowledgement.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if pairing == nil {
+		expression, err := s.expressionRepo.FindByID(ctx, acknowledgement.ExpressionID)
+		if err != nil {
+			return err
+		}
+		if expression == nil {
+			return domain.NotFound("expression not found")
+		}
+		pairing = &domain.ExpressionAcknowledgement{
+			ExpressionID:          acknowledgement.ExpressionID,
+			AcknowledgementID:     acknowledgement.ID.Hex(),
+			CreatorID:             expression.Creator,
+			AcknowledgerID:        acknowledgement.Acknowledger,
+			AcknowledgementStatus: acknowledgement.Status,
+			NFTStatus:             domain.NFTStatusNone,
+		}
+		err = s.pairingRepo.Create(ctx, pairing)
+		if !errors.Is(err, domain.ErrConflict) {
+			return err
+		}
+		// Paired concurrently; bring that pairing up to date instead
+		if pairing, err = s.pairingRepo.FindByAcknowledgement(ctx, acknowledgement.ID.Hex()); err != nil || pairing == nil {
+			return err
+		}
+	}
+	if pairing.AcknowledgementStatus == acknowledgement.Status {
+		return nil
+	}
+
+	pairing.AcknowledgementStatus = acknowledgement.Status
+	if acknowledgement.Status == domain.AcknowledgementStatusRefuted && pairing.NFTStatus == domain.NFTStatusRequested {
+		// Nothing is left to prove once the acknowledgement is withdrawn
+		return withdrawProofRequest(ctx, s.pairingRepo, s.proofRequestRepo, pairing, domain.ProofRequestCancelled)
+	}
+	return s.pairingRepo.Update(ctx, pairing, pairing.NFTStatus)
+}
+
+// pairProofRequests links proof requests made before pairings existed to the
+// pairing of their expression and two parties
+func (s *expressionAcknowledgementService) pairProofRequests(ctx context.Context) error {
+	requests, err := s.proofRequestRepo.FindUnpaired(ctx)
+	if err != nil {
+		return err
+	}
+	paired := 0
+	for _, request := range requests {
+		pairing, err := s.requestPairing(ctx, request)
+		if err != nil {
+			return err
+		}
+		if pairing == nil {
+			slog.WarnContext(ctx, "skipping proof request with no pairing", "request_id", request.ID.Hex(), "expression_id", request.ExpressionID)
+			continue
+		}
+
+		requestedAt, answeredAt := request.CreatedAt, request.UpdatedAt
+		request.PairingID = pairing.ID.Hex()
+		if err := s.proofRequestRepo.Update(ctx, request, request.Status); err != nil {
+			return err
+		}
+		if err := s.adoptProofRequest(ctx, pairing, request, requestedAt, answeredAt); err != nil {
+			return err
+		}
+		paired++
+	}
+	if len(requests) > 0 {
+		slog.InfoContext(ctx, "proof requests paired", "count", paired, "skipped", len(requests)-paired)
+	}
+	return nil
+}
+
+// requestPairing finds the pairing of a request's expression between its
+// initiator and peer, or returns nil
+func (s *expressionAcknowledgementService) requestPairing(ctx context.Context, request *domain.ProofRequest) (*domain.ExpressionAcknowledgement, error) {
+	pairings, err := s.pairingRepo.FindByUser(ctx, request.InitiatorID)
+	if err != nil {
+		return nil, err
+	}
+	var found *domain.ExpressionAcknowledgement
+	for _, pairing := range pairings {
+		if pairing.ExpressionID != request.ExpressionID || pairing.OtherParty(request.InitiatorID) != request.PeerID {
+			continue
+		}
+		// Prefer a pairing that is still free over one already in the NFT flow
+		if found == nil || (found.NFTStatus != domain.NFTStatusNone && pairing.NFTStatus == domain.NFTStatusNone) {
+			found = pairing
+		}
+	}
+	return found, nil
+}
+
+// adoptProofRequest moves a free pairing to where its old request left it: a
+// pending request is REQUESTED, and an accepted one APPROVED or, once its NFT
+// was minted, MINTED
+func (s *expressionAcknowledgementService) adoptProofRequest(ctx context.Context, pairing *domain.ExpressionAcknowledgement, request *domain.ProofRequest, requestedAt, answeredAt time.Time) error {
+	if pairing.NFTStatus != domain.NFTStatusNone {
+		return nil
+	}
+
+	switch request.Status {
+	case domain.ProofRequestPending:
+		pairing.NFTStatus = domain.NFTStatusRequested
+	case domain.ProofRequestAccepted:
+		nft, err := s.pairProofNFT(ctx, pairing)
+		if err != nil || nft == nil {
+			return err
+		}
+		pairing.NFTStatus = domain.NFTStatusApproved
+		if nft.MintedAt != nil {
+			pairing.NFTStatus = domain.NFTStatusMinted
+		}
+		pairing.NFTApprovedBy = request.PeerID
+		pairing.NFTApprovedAt = &answeredAt
+		pairing.ProofNFTID = nft.ID.Hex()
+	default:
+		return nil
+	}
+	pairing.NFTRequestedBy = request.InitiatorID
+	pairing.NFTRequestedAt = &requestedAt
+	pairing.ProofRequestID = request.ID.Hex()
+	return s.pairingRepo.Update(ctx, pairing, domain.NFTStatusNone)
+}
+
+// pairProofNFT links the unpaired proof NFT of a pairing's expression and
+// acknowledger to the pairing, if there is one
+func (s *expressionAcknowledgementService) pairProofNFT(ctx context.Context, pairing *domain.ExpressionAcknowledgement) (*domain.ProofNFT, error) {
+	nfts, err := s.proofNFTRepo.FindByExpression(ctx, pairing.ExpressionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, nft := range nfts {
+		if nft.Acknowledger != pairing.AcknowledgerID || nft.PairingID != "" {
+			continue
+		}
+		nft.PairingID = pairing.ID.Hex()
+		if err := s.proofNFTRepo.Update(ctx, nft); err != nil {
+			return nil, err
+		}
+		return nft, nil
+	}
+	return nil, nil
+}
+
+// withdrawProofRequest closes the pairing's pending proof request with status
+// and returns the pairing to NONE so a new proof can be requested
+func withdrawProofRequest(
+	ctx context.Context,
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	proofRequestRepo ports.ProofRequestRepository,
+	pairing *domain.ExpressionAcknowledgement,
+	status domain.ProofRequestStatus,
+) error {
+	request, err := proofRequestRepo.FindByID(ctx, pairing.ProofRequestID)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		request.Status = status
+		err := proofRequestRepo.Update(ctx, request, domain.ProofRequestPending)
+		if err != nil && !errors.Is(err, domain.ErrProofRequestNotPending) {
+			return err
+		}
+	}
+
+	pairing.NFTStatus = domain.NFTStatusNone
+	pairing.NFTRequestedBy = ""
+	pairing.NFTRequestedAt = nil
+	pairing.ProofRequestID = ""
+	return pairingRepo.Update(ctx, pairing, domain.NFTStatusRequested)
+}
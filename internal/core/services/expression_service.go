@@ -4,11 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"path/filepath"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"proofofpeacemaking/internal/core/storage"
+	"proofofpeacemaking/internal/tracing"
 	"time"
 )
 
@@ -16,21 +17,30 @@ type expressionService struct {
 	expressionRepo      ports.ExpressionRepository
 	acknowledgementRepo ports.AcknowledgementRepository
 	storage             storage.Storage
+	screener            ports.ContentScreener
+	moderationService   ports.ModerationService
 }
 
 func NewExpressionService(
 	expressionRepo ports.ExpressionRepository,
 	acknowledgementRepo ports.AcknowledgementRepository,
 	storage storage.Storage,
+	screener ports.ContentScreener,
+	moderationService ports.ModerationService,
 ) ports.ExpressionService {
 	return &expressionService{
 		expressionRepo:      expressionRepo,
 		acknowledgementRepo: acknowledgementRepo,
 		storage:             storage,
+		screener:            screener,
+		moderationService:   moderationService,
 	}
 }
 
 func (s *expressionService) Create(ctx context.Context, expression *domain.Expression) error {
+	ctx, span := tracing.Start(ctx, "ExpressionService.Create")
+	defer span.End()
+
 	// Handle media uploads if present
 	if expression.MediaContent != nil {
 		// Handle video upload
@@ -58,20 +68,68 @@ func (s *expressionService) Create(ctx context.Context, expression *domain.Expre
 		expression.MediaContent = nil
 	}
 
+	// Screen the content before it is persisted; flagged expressions are held back for review
+	result, err := s.screen(ctx, expression)
+	if err != nil {
+		return err
+	}
+	expression.Status = domain.ExpressionStatusPending
+	if result.Flagged {
+		expression.Status = domain.ExpressionStatusPendingReview
+		expression.ModerationStatus = domain.ModerationStatusPendingReview
+	}
+
 	// Create expression in repository with updated content paths
 	if err := s.expressionRepo.Create(ctx, expression); err != nil {
 		return fmt.Errorf("failed to create expression: %w", err)
 	}
+
+	if result.Flagged {
+		if _, err := s.moderationService.Flag(ctx, domain.ContentTypeExpression, expression.ID.Hex(), expression.Creator, result); err != nil {
+			// The expression stays hidden, so a missing case only delays publication
+			slog.ErrorContext(ctx, "failed to open moderation case", "expression_id", expression.ID.Hex(), "error", err)
+		}
+	}
 	return nil
 }
 
+// screen runs the configured screener over an expression's text and media
+func (s *expressionService) screen(ctx context.Context, expression *domain.Expression) (*domain.ScreeningResult, error) {
+	if s.screener == nil {
+		return &domain.ScreeningResult{}, nil
+	}
+
+	media := make(map[string]string)
+	for mediaType, key := range expression.Content {
+		if mediaType != "text" {
+			media[mediaType] = key
+		}
+	}
+
+	result, err := s.screener.Screen(ctx, &domain.ScreeningInput{
+		ContentType: domain.ContentTypeExpression,
+		ContentID:   expression.ID.Hex(),
+		Text:        expression.Content["text"],
+		Media:       media,
+		Locale:      expression.Locale,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen expression: %w", err)
+	}
+	return result, nil
+}
+
 func (s *expressionService) Get(ctx context.Context, id string) (*domain.Expression, error) {
+	ctx, span := tracing.Start(ctx, "ExpressionService.Get")
+	defer span.End()
+
 	expression, err := s.expressionRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expression: %w", err)
 	}
-	if expression == nil {
-		return nil, nil
+	// Hidden expressions are treated as missing outside the moderation tools
+	if expression == nil || !expression.ModerationStatus.IsPubliclyVisible() {
+		return nil, domain.NotFound("expression not found")
 	}
 
 	// Initialize counts to 0
@@ -83,6 +141,7 @@ func (s *expressionService) Get(ctx context.Context, id string) (*domain.Express
 	if err != nil {
 		return nil, fmt.Errorf("failed to get acknowledgements for expression: %w", err)
 	}
+	acks = visibleAcknowledgements(acks)
 	expression.Acknowledgements = acks
 
 	// Calculate active acknowledgement count
@@ -101,11 +160,23 @@ func (s *expressionService) Get(ctx context.Context, id string) (*domain.Express
 }
 
 func (s *expressionService) List(ctx context.Context) ([]*domain.Expression, error) {
+	ctx, span := tracing.Start(ctx, "ExpressionService.List")
+	defer span.End()
+
 	expressions, err := s.expressionRepo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list expressions: %w", err)
 	}
 
+	// Leave out expressions moderators have hidden
+	visible := expressions[:0]
+	for _, expr := range expressions {
+		if expr.ModerationStatus.IsPubliclyVisible() {
+			visible = append(visible, expr)
+		}
+	}
+	expressions = visible
+
 	// For each expression, get its acknowledgements and calculate counts
 	for _, expr := range expressions {
 		// Initialize counts to 0
@@ -117,6 +188,7 @@ func (s *expressionService) List(ctx context.Context) ([]*domain.Expression, err
 		if err != nil {
 			return nil, fmt.Errorf("failed to get acknowledgements for expression: %w", err)
 		}
+		acks = visibleAcknowledgements(acks)
 		expr.Acknowledgements = acks
 
 		// Calculate active acknowledgement count
@@ -136,6 +208,9 @@ func (s *expressionService) List(ctx context.Context) ([]*domain.Expression, err
 }
 
 func (s *expressionService) ListByUser(ctx context.Context, userID string) ([]*domain.Expression, error) {
+	ctx, span := tracing.Start(ctx, "ExpressionService.ListByUser")
+	defer span.End()
+
 	expressions, err := s.expressionRepo.FindByCreatorID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list expressions by user: %w", err)
@@ -166,6 +241,9 @@ func (s *expressionService) ListByUser(ctx context.Context, userID string) ([]*d
 }
 
 func (s *expressionService) GetMultiple(ctx context.Context, ids []string) (map[string]*domain.Expression, error) {
+	ctx, span := tracing.Start(ctx, "ExpressionService.GetMultiple")
+	defer span.End()
+
 	expressions := make(map[string]*domain.Expression)
 
 	// Get all expressions in one query
@@ -200,6 +278,17 @@ func (s *expressionService) GetMultiple(ctx context.Context, ids []string) (map[
 	return expressions, nil
 }
 
+// visibleAcknowledgements drops acknowledgements moderators have hidden
+func visibleAcknowledgements(acks []*domain.Acknowledgement) []*domain.Acknowledgement {
+	visible := make([]*domain.Acknowledgement, 0, len(acks))
+	for _, ack := range acks {
+		if ack.ModerationStatus.IsPubliclyVisible() {
+			visible = append(visible, ack)
+		}
+	}
+	return visible
+}
+
 // Helper function to get content type
 func getContentType(filename string) string {
 	ext := filepath.Ext(filename)
@@ -227,79 +316,74 @@ func getContentType(filename string) string {
 
 // UploadMedia uploads media content for an expression
 func (s *expressionService) UploadMedia(ctx context.Context, expressionID string, mediaType string, reader io.Reader, filename string) (string, error) {
-	log.Printf("[EXPRESSION] Starting media upload - ExpressionID: %s, MediaType: %s, Filename: %s", expressionID, mediaType, filename)
+	ctx, span := tracing.Start(ctx, "ExpressionService.UploadMedia")
+	defer span.End()
 
 	// Get the file extension from original filename
 	ext := filepath.Ext(filename)
 	// Path format: expressions/[expressionID]/[mediaType][extension]
 	// Example: expressions/123abc/video.mp4
 	key := fmt.Sprintf("expressions/%s/%s%s", expressionID, mediaType, ext)
-	log.Printf("[EXPRESSION] Generated storage key: %s", key)
 
 	// Use the content type detection
 	contentType := getContentType(filename)
-	log.Printf("[EXPRESSION] Detected content type: %s for file: %s", contentType, filename)
 
 	err := s.storage.UploadFile(ctx, key, reader, storage.UploadOptions{
 		ContentType:  contentType,
 		CacheControl: "public, max-age=31536000", // 1 year cache for media
 	})
 	if err != nil {
-		log.Printf("[EXPRESSION] Failed to upload media - Key: %s, Error: %v", key, err)
+		slog.ErrorContext(ctx, "failed to upload media", "key", key, "error", err)
 		return "", fmt.Errorf("failed to upload media: %w", err)
 	}
 
-	log.Printf("[EXPRESSION] Successfully uploaded media - Key: %s", key)
+	slog.DebugContext(ctx, "uploaded media", "key", key, "content_type", contentType)
 	return key, nil
 }
 
 // GetMedia retrieves media content for an expression
 func (s *expressionService) GetMedia(ctx context.Context, expressionID string, mediaType string) (io.ReadCloser, error) {
-	key := fmt.Sprintf("expressions/%s/%s", expressionID, mediaType)
-	log.Printf("[EXPRESSION] Retrieving media - ExpressionID: %s, MediaType: %s, Key: %s", expressionID, mediaType, key)
+	ctx, span := tracing.Start(ctx, "ExpressionService.GetMedia")
+	defer span.End()
 
+	key := fmt.Sprintf("expressions/%s/%s", expressionID, mediaType)
 	reader, err := s.storage.GetFile(ctx, key)
 	if err != nil {
-		log.Printf("[EXPRESSION] Failed to retrieve media - Key: %s, Error: %v", key, err)
+		slog.ErrorContext(ctx, "failed to retrieve media", "key", key, "error", err)
 		return nil, fmt.Errorf("failed to get media: %w", err)
 	}
 
-	log.Printf("[EXPRESSION] Successfully retrieved media - Key: %s", key)
 	return reader, nil
 }
 
 // DeleteMedia removes media content for an expression
 func (s *expressionService) DeleteMedia(ctx context.Context, expressionID string, mediaType string) error {
-	key := fmt.Sprintf("expressions/%s/%s", expressionID, mediaType)
-	log.Printf("[EXPRESSION] Deleting media - ExpressionID: %s, MediaType: %s, Key: %s", expressionID, mediaType, key)
+	ctx, span := tracing.Start(ctx, "ExpressionService.DeleteMedia")
+	defer span.End()
 
+	key := fmt.Sprintf("expressions/%s/%s", expressionID, mediaType)
 	if err := s.storage.DeleteFile(ctx, key); err != nil {
-		log.Printf("[EXPRESSION] Failed to delete media - Key: %s, Error: %v", key, err)
+		slog.ErrorContext(ctx, "failed to delete media", "key", key, "error", err)
 		return fmt.Errorf("failed to delete media: %w", err)
 	}
 
-	log.Printf("[EXPRESSION] Successfully deleted media - Key: %s", key)
+	slog.InfoContext(ctx, "deleted media", "key", key)
 	return nil
 }
 
 // Helper function to add presigned URLs to an expression's content
 func (s *expressionService) addPresignedURLs(ctx context.Context, expression *domain.Expression) error {
-	log.Printf("[EXPRESSION] Generating presigned URLs for expression: %s", expression.ID.Hex())
-
 	mediaTypes := []string{"image", "audio", "video"}
 	for _, mediaType := range mediaTypes {
 		if key, exists := expression.Content[mediaType]; exists {
-			log.Printf("[EXPRESSION] Generating presigned URL for %s - Key: %s", mediaType, key)
-
 			// Generate a presigned URL that's valid for 1 hour
 			url, err := s.storage.GetPresignedURL(ctx, key, time.Hour)
 			if err != nil {
-				log.Printf("[EXPRESSION] Failed to generate presigned URL for %s - Key: %s, Error: %v", mediaType, key, err)
+				slog.ErrorContext(ctx, "failed to generate presigned URL", "key", key, "error", err)
 				return fmt.Errorf("failed to generate presigned URL for %s: %w", mediaType, err)
 			}
 
 			expression.Content[mediaType] = url
-			log.Printf("[EXPRESSION] Successfully generated presigned URL for %s", mediaType)
 		}
 	}
 
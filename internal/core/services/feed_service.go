@@ -4,6 +4,7 @@ import (
 	"context"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 )
 
 type feedService struct {
@@ -25,7 +26,10 @@ func NewFeedService(
 }
 
 func (s *feedService) GetFeed(ctx context.Context) ([]map[string]interface{}, error) {
-	// Get all expressions
+	ctx, span := tracing.Start(ctx, "FeedService.GetFeed")
+	defer span.End()
+
+	// Get all expressions; List already leaves out hidden ones
 	expressions, err := s.expressionService.List(ctx)
 	if err != nil {
 		return nil, err
@@ -39,6 +43,7 @@ func (s *feedService) GetFeed(ctx context.Context) ([]map[string]interface{}, er
 		if err != nil {
 			return nil, err
 		}
+		acks = visibleAcknowledgements(acks)
 
 		// Count active acknowledgements
 		activeCount := 0
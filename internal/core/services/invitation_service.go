@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"proofofpeacemaking/internal/core/certificate"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/invite"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type invitationService struct {
+	invitationRepo      ports.InvitationRepository
+	userRepo            ports.UserRepository
+	expressionService   ports.ExpressionService
+	notificationService ports.NotificationService
+	mailer              ports.Mailer
+	signer              *invite.Signer
+	siteURL             string
+	lifetime            time.Duration
+}
+
+// NewInvitationService sends invite links to siteURL that stay valid for lifetime
+func NewInvitationService(
+	invitationRepo ports.InvitationRepository,
+	userRepo ports.UserRepository,
+	expressionService ports.ExpressionService,
+	notificationService ports.NotificationService,
+	mailer ports.Mailer,
+	signer *invite.Signer,
+	siteURL string,
+	lifetime time.Duration,
+) ports.InvitationService {
+	return &invitationService{
+		invitationRepo:      invitationRepo,
+		userRepo:            userRepo,
+		expressionService:   expressionService,
+		notificationService: notificationService,
+		mailer:              mailer,
+		signer:              signer,
+		siteURL:             siteURL,
+		lifetime:            lifetime,
+	}
+}
+
+func (s *invitationService) Invite(ctx context.Context, creator *domain.User, expressionID string, invitee string, message string) (*domain.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.Invite")
+	defer span.End()
+
+	expression, err := s.expressionService.Get(ctx, expressionID)
+	if err != nil {
+		return nil, err
+	}
+	if expression.Creator != creator.ID.Hex() {
+		return nil, domain.ErrInvitationNotCreator
+	}
+	message = strings.TrimSpace(message)
+	if len([]rune(message)) > domain.InvitationMessageMaxLength {
+		return nil, domain.Validation("the message must be at most %d characters", domain.InvitationMessageMaxLength)
+	}
+
+	kind, invitee, err := parseInvitee(invitee)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.findInvitee(ctx, kind, invitee)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	if account != nil && account.ID == creator.ID {
+		return nil, domain.ErrInvitationSelf
+	}
+
+	invitation := &domain.Invitation{
+		ID:           primitive.NewObjectID(),
+		ExpressionID: expression.ID.Hex(),
+		CreatorID:    creator.ID.Hex(),
+		Invitee:      invitee,
+		InviteeKind:  kind,
+		Message:      message,
+		Status:       domain.InvitationPending,
+		ExpiresAt:    time.Now().Add(s.lifetime).Truncate(time.Second),
+	}
+	// Account emails are not verified, so an email invitation is linked to an
+	// account only when it is accepted through the emailed link
+	if account != nil && kind != domain.InviteeEmail {
+		invitation.InviteeID = account.ID.Hex()
+	}
+
+	if err := s.checkDuplicate(ctx, invitation); err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		tracing.Fail(span, err)
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	invitation.Link = s.link(invitation)
+	slog.InfoContext(ctx, "invitation sent", "invitation_id", invitation.ID.Hex(), "expression_id", invitation.ExpressionID, "invitee_kind", kind)
+
+	if invitation.InviteeID != "" {
+		if err := s.notificationService.NotifyInvitationReceived(ctx, invitation); err != nil {
+			slog.ErrorContext(ctx, "failed to notify invitee", "invitation_id", invitation.ID.Hex(), "error", err)
+		}
+	}
+	if kind == domain.InviteeEmail {
+		body := generateInvitationEmailBody(displayName(creator), expression.Content["text"], message, invitation.Link)
+		if err := s.mailer.Send(ctx, invitee, "You are invited to acknowledge an expression of peace", body); err != nil {
+			slog.ErrorContext(ctx, "failed to email invitation", "invitation_id", invitation.ID.Hex(), "error", err)
+		}
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) Open(ctx context.Context, token string) (*domain.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.Open")
+	defer span.End()
+
+	return s.open(ctx, token)
+}
+
+func (s *invitationService) Accept(ctx context.Context, user *domain.User, token string) (*domain.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.Accept")
+	defer span.End()
+
+	invitation, err := s.open(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.CreatorID == user.ID.Hex() {
+		return nil, domain.ErrInvitationSelf
+	}
+	if !invitation.IsFor(user) {
+		return nil, domain.ErrInvitationNotInvitee
+	}
+	switch invitation.Status {
+	case domain.InvitationAccepted:
+		// Following the link again after accepting is harmless
+		return invitation, nil
+	case domain.InvitationExpired:
+		return nil, domain.ErrInvitationExpired
+	}
+
+	now := time.Now()
+	invitation.InviteeID = user.ID.Hex()
+	invitation.Status = domain.InvitationAccepted
+	invitation.AcceptedAt = &now
+	if err := s.invitationRepo.Update(ctx, invitation, domain.InvitationPending); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "invitation accepted", "invitation_id", invitation.ID.Hex(), "user_id", user.ID.Hex())
+
+	if err := s.notificationService.NotifyInvitationAccepted(ctx, invitation); err != nil {
+		slog.ErrorContext(ctx, "failed to notify creator of accepted invitation", "invitation_id", invitation.ID.Hex(), "error", err)
+	}
+	return invitation, nil
+}
+
+func (s *invitationService) ListSent(ctx context.Context, user *domain.User) ([]*domain.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.ListSent")
+	defer span.End()
+
+	if err := s.expireLapsed(ctx); err != nil {
+		return nil, err
+	}
+	invitations, err := s.invitationRepo.FindByCreator(ctx, user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if invitation.Status == domain.InvitationPending {
+			invitation.Link = s.link(invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (s *invitationService) ListReceived(ctx context.Context, user *domain.User) ([]*domain.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.ListReceived")
+	defer span.End()
+
+	if err := s.expireLapsed(ctx); err != nil {
+		return nil, err
+	}
+	invitations, err := s.invitationRepo.FindByInviteeID(ctx, user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	// The invitee reaches a pending invitation through its link
+	for _, invitation := range invitations {
+		if invitation.Status == domain.InvitationPending {
+			invitation.Link = s.link(invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (s *invitationService) LinkUser(ctx context.Context, user *domain.User) error {
+	ctx, span := tracing.Start(ctx, "InvitationService.LinkUser")
+	defer span.End()
+
+	// Email invitations wait for the emailed link, as anyone can sign up
+	// with an email they cannot read
+	if user.Address == "" {
+		return nil
+	}
+	invitations, err := s.invitationRepo.FindUnlinked(ctx, domain.InviteeAddress, strings.ToLower(user.Address))
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+
+	now := time.Now()
+	for _, invitation := range invitations {
+		if invitation.Lapsed(now) || invitation.CreatorID == user.ID.Hex() {
+			continue
+		}
+		invitation.InviteeID = user.ID.Hex()
+		if err := s.invitationRepo.Update(ctx, invitation, domain.InvitationPending); err != nil {
+			if errors.Is(err, domain.ErrInvitationNotPending) {
+				continue
+			}
+			tracing.Fail(span, err)
+			return err
+		}
+		if err := s.notificationService.NotifyInvitationReceived(ctx, invitation); err != nil {
+			slog.ErrorContext(ctx, "failed to notify invitee", "invitation_id", invitation.ID.Hex(), "error", err)
+		}
+	}
+	return nil
+}
+
+// open verifies a link token and returns its invitation, marking it expired
+// once its time is up
+func (s *invitationService) open(ctx context.Context, token string) (*domain.Invitation, error) {
+	id, _, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, domain.ErrInvitationNotFound
+	}
+	invitation, err := s.invitationRepo.FindByID(ctx, id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, domain.ErrInvitationNotFound
+	}
+
+	if invitation.Lapsed(time.Now()) {
+		invitation.Status = domain.InvitationExpired
+		err := s.invitationRepo.Update(ctx, invitation, domain.InvitationPending)
+		if errors.Is(err, domain.ErrInvitationNotPending) {
+			// Accepted just before it lapsed
+			return s.invitationRepo.FindByID(ctx, id.Hex())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return invitation, nil
+}
+
+// checkDuplicate refuses a second pending invitation of the same person to
+// the same expression
+func (s *invitationService) checkDuplicate(ctx context.Context, invitation *domain.Invitation) error {
+	pending, err := s.invitationRepo.FindPending(ctx, invitation.ExpressionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, other := range pending {
+		if other.Lapsed(now) {
+			continue
+		}
+		sameAccount := invitation.InviteeID != "" && other.InviteeID == invitation.InviteeID
+		if sameAccount || (other.InviteeKind == invitation.InviteeKind && other.Invitee == invitation.Invitee) {
+			return domain.ErrInvitationDuplicate
+		}
+	}
+	return nil
+}
+
+func (s *invitationService) expireLapsed(ctx context.Context) error {
+	expired, err := s.invitationRepo.ExpireLapsed(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		slog.InfoContext(ctx, "invitations expired", "count", expired)
+	}
+	return nil
+}
+
+// findInvitee returns the account invitee names, if there is one. Usernames
+// must belong to an account; emails and addresses may belong to people who
+// have yet to register.
+func (s *invitationService) findInvitee(ctx context.Context, kind domain.InviteeKind, invitee string) (*domain.User, error) {
+	switch kind {
+	case domain.InviteeEmail:
+		return s.userRepo.GetByEmail(ctx, invitee)
+	case domain.InviteeAddress:
+		return s.userRepo.GetByAddressIgnoreCase(ctx, invitee)
+	}
+	user, err := s.userRepo.GetByUsername(ctx, invitee)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.NotFound("no one has the username %q", invitee)
+	}
+	return user, nil
+}
+
+func (s *invitationService) link(invitation *domain.Invitation) string {
+	return s.siteURL + "/invite/" + s.signer.Sign(invitation.ID, invitation.ExpiresAt)
+}
+
+// parseInvitee tells usernames, emails and wallet addresses apart. A leading
+// @ marks a username.
+func parseInvitee(invitee string) (domain.InviteeKind, string, error) {
+	invitee = strings.TrimSpace(invitee)
+	username, isUsername := strings.CutPrefix(invitee, "@")
+	switch {
+	case username == "":
+		return "", "", domain.Validation("enter the username, email or wallet address of the person to invite")
+	case isUsername:
+		return domain.InviteeUsername, username, nil
+	case strings.Contains(invitee, "@"):
+		at := strings.LastIndex(invitee, "@")
+		if at == 0 || !strings.Contains(invitee[at:], ".") {
+			return "", "", domain.Validation("invalid email format")
+		}
+		return domain.InviteeEmail, strings.ToLower(invitee), nil
+	case common.IsHexAddress(invitee):
+		return domain.InviteeAddress, strings.ToLower(invitee), nil
+	}
+	return domain.InviteeUsername, invitee, nil
+}
+
+// displayName is how a user is named to people they invite
+func displayName(user *domain.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Username != "" {
+		return user.Username
+	}
+	return "Someone"
+}
+
+func generateInvitationEmailBody(inviter, expression, message, link string) string {
+	note := ""
+	if message != "" {
+		note = fmt.Sprintf(`<p class="note">%s</p>`, html.EscapeString(message))
+	}
+	return fmt.Sprintf(`
+	<html>
+	<head>
+		<style>
+			.container {
+				max-width: 600px;
+				margin: 0 auto;
+				padding: 20px;
+				font-family: Arial, sans-serif;
+			}
+			blockquote {
+				margin: 16px 0;
+				padding-left: 12px;
+				border-left: 3px solid #ccc;
+			}
+			.note {
+				font-style: italic;
+			}
+		</style>
+	</head>
+	<body>
+		<div class="container">
+			<p>%s invited you to acknowledge their expression of peace on Proof of Peacemaking.</p>
+			<blockquote>%s</blockquote>
+			%s
+			<p><a href="%s">Open the invitation</a></p>
+		</div>
+	</body>
+	</html>
+	`, html.EscapeString(inviter), html.EscapeString(certificate.Excerpt(expression)), note, html.EscapeString(link))
+}
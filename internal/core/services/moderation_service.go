@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultSuspension is used when a moderator suspends a user without giving a duration
+const defaultSuspension = 7 * 24 * time.Hour
+
+type moderationService struct {
+	moderationRepo      ports.ModerationRepository
+	expressionRepo      ports.ExpressionRepository
+	acknowledgementRepo ports.AcknowledgementRepository
+	replyRepo           ports.ReplyRepository
+	pairingRepo         ports.ExpressionAcknowledgementRepository
+	userRepo            ports.UserRepository
+	notificationService ports.NotificationService
+}
+
+func NewModerationService(
+	moderationRepo ports.ModerationRepository,
+	expressionRepo ports.ExpressionRepository,
+	acknowledgementRepo ports.AcknowledgementRepository,
+	replyRepo ports.ReplyRepository,
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	userRepo ports.UserRepository,
+	notificationService ports.NotificationService,
+) ports.ModerationService {
+	return &moderationService{
+		moderationRepo:      moderationRepo,
+		expressionRepo:      expressionRepo,
+		acknowledgementRepo: acknowledgementRepo,
+		replyRepo:           replyRepo,
+		pairingRepo:         pairingRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *moderationService) Report(ctx context.Context, reporter *domain.User, contentType domain.ContentType, contentID string, reason domain.ReportReason, details string) (*domain.Report, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.Report")
+	defer span.End()
+
+	if !domain.ValidReportReason(reason) {
+		return nil, domain.ErrInvalidReportReason
+	}
+
+	authorID, err := s.contentAuthor(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reported, err := s.moderationRepo.HasReported(ctx, reporter.ID, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if reported {
+		return nil, domain.ErrAlreadyReported
+	}
+
+	moderationCase, err := s.moderationRepo.FindActiveCase(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase == nil {
+		moderationCase = &domain.ModerationCase{
+			ContentType: contentType,
+			ContentID:   contentID,
+			AuthorID:    authorID,
+			Status:      domain.CaseStatusOpen,
+			Reasons:     []domain.ReportReason{reason},
+			ReportCount: 1,
+		}
+		if err := s.moderationRepo.CreateCase(ctx, moderationCase); err != nil {
+			return nil, err
+		}
+	} else if err := s.moderationRepo.AddReportToCase(ctx, moderationCase.ID, reason); err != nil {
+		return nil, err
+	}
+
+	report := &domain.Report{
+		CaseID:      moderationCase.ID,
+		ContentType: contentType,
+		ContentID:   contentID,
+		ReporterID:  reporter.ID,
+		Reason:      reason,
+		Details:     details,
+	}
+	if err := s.moderationRepo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := s.appendLog(ctx, moderationCase, reporter.ID, domain.ModerationActionReport, string(reason)); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *moderationService) Flag(ctx context.Context, contentType domain.ContentType, contentID string, authorID string, result *domain.ScreeningResult) (*domain.ModerationCase, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.Flag")
+	defer span.End()
+
+	moderationCase, err := s.moderationRepo.FindActiveCase(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase == nil {
+		moderationCase = &domain.ModerationCase{
+			ContentType: contentType,
+			ContentID:   contentID,
+			AuthorID:    authorID,
+			Status:      domain.CaseStatusOpen,
+			Reasons:     []domain.ReportReason{result.Reason},
+			Source:      "screener:" + result.Screener,
+		}
+		if err := s.moderationRepo.CreateCase(ctx, moderationCase); err != nil {
+			return nil, err
+		}
+	} else if err := s.moderationRepo.AddReportToCase(ctx, moderationCase.ID, result.Reason); err != nil {
+		return nil, err
+	}
+
+	if err := s.appendLog(ctx, moderationCase, primitive.NilObjectID, domain.ModerationActionReport,
+		fmt.Sprintf("flagged by %s screener: %s", result.Screener, strings.Join(result.Matches, ", "))); err != nil {
+		return nil, err
+	}
+	return moderationCase, nil
+}
+
+func (s *moderationService) ListQueue(ctx context.Context, statuses []domain.CaseStatus) ([]*domain.ModerationCase, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.ListQueue")
+	defer span.End()
+
+	if len(statuses) == 0 {
+		statuses = []domain.CaseStatus{domain.CaseStatusOpen, domain.CaseStatusClaimed, domain.CaseStatusEscalated}
+	}
+
+	cases, err := s.moderationRepo.FindCasesByStatus(ctx, statuses)
+	if err != nil {
+		return nil, err
+	}
+	if cases == nil {
+		cases = []*domain.ModerationCase{}
+	}
+	return cases, nil
+}
+
+func (s *moderationService) GetCase(ctx context.Context, caseID primitive.ObjectID) (*domain.ModerationCase, []*domain.Report, []*domain.ModerationLogEntry, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.GetCase")
+	defer span.End()
+
+	moderationCase, err := s.getCase(ctx, caseID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reports, err := s.moderationRepo.FindReportsByCase(ctx, caseID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entries, err := s.moderationRepo.FindLogByCase(ctx, caseID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return moderationCase, reports, entries, nil
+}
+
+func (s *moderationService) Claim(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID) (*domain.ModerationCase, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.Claim")
+	defer span.End()
+
+	moderationCase, err := s.getCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch moderationCase.Status {
+	case domain.CaseStatusOpen:
+	case domain.CaseStatusEscalated:
+		// Escalated cases are for admins to decide
+		if !moderator.HasRole(domain.RoleAdmin) {
+			return nil, domain.ErrInvalidCaseTransition
+		}
+	default:
+		return nil, domain.ErrInvalidCaseTransition
+	}
+
+	from, claimedBy := moderationCase.Status, moderationCase.ClaimedBy
+	moderationCase.Status = domain.CaseStatusClaimed
+	moderationCase.ClaimedBy = &moderator.ID
+	if err := s.moderationRepo.UpdateCase(ctx, moderationCase, from, claimedBy); err != nil {
+		return nil, err
+	}
+
+	if err := s.appendLog(ctx, moderationCase, moderator.ID, domain.ModerationActionClaim, ""); err != nil {
+		return nil, err
+	}
+	return moderationCase, nil
+}
+
+func (s *moderationService) Escalate(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID, note string) (*domain.ModerationCase, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.Escalate")
+	defer span.End()
+
+	moderationCase, err := s.getClaimedCase(ctx, moderator, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	moderationCase.Status = domain.CaseStatusEscalated
+	moderationCase.ClaimedBy = nil
+	if err := s.moderationRepo.UpdateCase(ctx, moderationCase, domain.CaseStatusClaimed, &moderator.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.appendLog(ctx, moderationCase, moderator.ID, domain.ModerationActionEscalate, note); err != nil {
+		return nil, err
+	}
+	return moderationCase, nil
+}
+
+func (s *moderationService) Resolve(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID, action domain.ModerationAction, note string, suspendFor time.Duration) (*domain.ModerationCase, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.Resolve")
+	defer span.End()
+
+	moderationCase, err := s.getClaimedCase(ctx, moderator, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case domain.ModerationActionHide:
+		err = s.setContentStatus(ctx, moderationCase, domain.ModerationStatusHidden)
+	case domain.ModerationActionRestore:
+		// Content a screener held back is published for the first time
+		if err = s.releasePendingReview(ctx, moderationCase); err == nil {
+			err = s.setContentStatus(ctx, moderationCase, domain.ModerationStatusVisible)
+		}
+	case domain.ModerationActionSuspend:
+		if suspendFor <= 0 {
+			suspendFor = defaultSuspension
+		}
+		err = s.suspendAuthor(ctx, moderationCase, time.Now().Add(suspendFor))
+	case domain.ModerationActionWarn, domain.ModerationActionDismiss:
+		// The content is acceptable, so release it if a screener was holding it back
+		err = s.releasePendingReview(ctx, moderationCase)
+	default:
+		return nil, domain.Validation("unsupported moderation action %q", action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	moderationCase.Status = domain.CaseStatusResolved
+	moderationCase.Resolution = action
+	moderationCase.ResolvedAt = &now
+	if err := s.moderationRepo.UpdateCase(ctx, moderationCase, domain.CaseStatusClaimed, &moderator.ID); err != nil {
+		return nil, err
+	}
+
+	// The case is resolved either way, so the author hears about it even if logging fails
+	logErr := s.appendLog(ctx, moderationCase, moderator.ID, action, note)
+	s.notifyAuthor(ctx, moderationCase)
+	if logErr != nil {
+		return nil, logErr
+	}
+	return moderationCase, nil
+}
+
+func (s *moderationService) Reopen(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID, note string) (*domain.ModerationCase, error) {
+	ctx, span := tracing.Start(ctx, "ModerationService.Reopen")
+	defer span.End()
+
+	moderationCase, err := s.getCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase.Status != domain.CaseStatusResolved || !moderator.HasRole(domain.RoleAdmin) {
+		return nil, domain.ErrInvalidCaseTransition
+	}
+	claimedBy := moderationCase.ClaimedBy
+
+	// Only one unresolved case may exist per piece of content
+	active, err := s.moderationRepo.FindActiveCase(ctx, moderationCase.ContentType, moderationCase.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, domain.ErrInvalidCaseTransition
+	}
+
+	moderationCase.Status = domain.CaseStatusOpen
+	moderationCase.ClaimedBy = nil
+	moderationCase.Resolution = ""
+	moderationCase.ResolvedAt = nil
+	if err := s.moderationRepo.UpdateCase(ctx, moderationCase, domain.CaseStatusResolved, claimedBy); err != nil {
+		return nil, err
+	}
+
+	if err := s.appendLog(ctx, moderationCase, moderator.ID, domain.ModerationActionReopen, note); err != nil {
+		return nil, err
+	}
+	return moderationCase, nil
+}
+
+func (s *moderationService) getCase(ctx context.Context, caseID primitive.ObjectID) (*domain.ModerationCase, error) {
+	moderationCase, err := s.moderationRepo.FindCaseByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase == nil {
+		return nil, domain.ErrCaseNotFound
+	}
+	return moderationCase, nil
+}
+
+// getClaimedCase loads a case and checks the moderator currently holds it
+func (s *moderationService) getClaimedCase(ctx context.Context, moderator *domain.User, caseID primitive.ObjectID) (*domain.ModerationCase, error) {
+	moderationCase, err := s.getCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase.Status != domain.CaseStatusClaimed || moderationCase.ClaimedBy == nil || *moderationCase.ClaimedBy != moderator.ID {
+		return nil, domain.ErrCaseNotClaimed
+	}
+	return moderationCase, nil
+}
+
+// contentAuthor checks the content exists and returns the ID of the user who posted it
+func (s *moderationService) contentAuthor(ctx context.Context, contentType domain.ContentType, contentID string) (string, error) {
+	switch contentType {
+	case domain.ContentTypeExpression:
+		expression, err := s.expressionRepo.FindByID(ctx, contentID)
+		if err != nil || expression == nil {
+			return "", domain.ErrContentNotFound
+		}
+		return expression.Creator, nil
+	case domain.ContentTypeAcknowledgement:
+		acknowledgement, err := s.acknowledgementRepo.FindByID(ctx, contentID)
+		if err != nil || acknowledgement == nil {
+			return "", domain.ErrContentNotFound
+		}
+		return acknowledgement.Acknowledger, nil
+	case domain.ContentTypeReply:
+		reply, err := s.replyRepo.FindByID(ctx, contentID)
+		if err != nil || reply == nil {
+			return "", domain.ErrContentNotFound
+		}
+		return reply.AuthorID, nil
+	}
+	return "", domain.ErrContentNotFound
+}
+
+func (s *moderationService) setContentStatus(ctx context.Context, moderationCase *domain.ModerationCase, status domain.ModerationStatus) error {
+	switch moderationCase.ContentType {
+	case domain.ContentTypeExpression:
+		return s.expressionRepo.SetModerationStatus(ctx, moderationCase.ContentID, status)
+	case domain.ContentTypeAcknowledgement:
+		return s.acknowledgementRepo.SetModerationStatus(ctx, moderationCase.ContentID, status)
+	case domain.ContentTypeReply:
+		return s.replyRepo.SetModerationStatus(ctx, moderationCase.ContentID, status)
+	}
+	return domain.ErrContentNotFound
+}
+
+// releasePendingReview publishes content that was held for review by a screener.
+// A held reply was never announced, so the other party is told about it now.
+func (s *moderationService) releasePendingReview(ctx context.Context, moderationCase *domain.ModerationCase) error {
+	switch moderationCase.ContentType {
+	case domain.ContentTypeExpression:
+		expression, err := s.expressionRepo.FindByID(ctx, moderationCase.ContentID)
+		if err != nil || expression == nil || expression.ModerationStatus != domain.ModerationStatusPendingReview {
+			return err
+		}
+		return s.expressionRepo.SetModerationStatus(ctx, moderationCase.ContentID, domain.ModerationStatusVisible)
+	case domain.ContentTypeReply:
+		reply, err := s.replyRepo.FindByID(ctx, moderationCase.ContentID)
+		if err != nil || reply == nil || reply.ModerationStatus != domain.ModerationStatusPendingReview {
+			return err
+		}
+		if err := s.replyRepo.SetModerationStatus(ctx, moderationCase.ContentID, domain.ModerationStatusVisible); err != nil {
+			return err
+		}
+		s.notifyReleasedReply(ctx, reply)
+	}
+	return nil
+}
+
+// notifyReleasedReply tells the other party of the dialogue about a reply that
+// was just published. Failures are logged, since the reply is already visible.
+func (s *moderationService) notifyReleasedReply(ctx context.Context, reply *domain.Reply) {
+	pairing, err := s.pairingRepo.FindByAcknowledgement(ctx, reply.AcknowledgementID)
+	if err != nil || pairing == nil {
+		slog.ErrorContext(ctx, "could not find dialogue of released reply", "reply_id", reply.ID.Hex(), "error", err)
+		return
+	}
+	if err := s.notificationService.NotifyNewReply(ctx, reply, pairing.OtherParty(reply.AuthorID)); err != nil {
+		slog.ErrorContext(ctx, "failed to notify of new reply", "reply_id", reply.ID.Hex(), "error", err)
+	}
+}
+
+func (s *moderationService) suspendAuthor(ctx context.Context, moderationCase *domain.ModerationCase, until time.Time) error {
+	authorID, err := primitive.ObjectIDFromHex(moderationCase.AuthorID)
+	if err != nil {
+		return fmt.Errorf("invalid author ID format: %w", err)
+	}
+	return s.userRepo.SetSuspendedUntil(ctx, authorID, &until)
+}
+
+// notifyAuthor tells the author about actions that affect them. Failures are logged, not returned,
+// since the moderation decision has already been applied.
+func (s *moderationService) notifyAuthor(ctx context.Context, moderationCase *domain.ModerationCase) {
+	var message string
+	switch moderationCase.Resolution {
+	case domain.ModerationActionHide:
+		message = "One of your posts was hidden because it breaks our community guidelines."
+	case domain.ModerationActionWarn:
+		message = "One of your posts was reported and reviewed. Please keep to our community guidelines."
+	case domain.ModerationActionSuspend:
+		message = "Your account has been temporarily suspended from posting for breaking our community guidelines."
+	default:
+		return
+	}
+
+	authorID, err := primitive.ObjectIDFromHex(moderationCase.AuthorID)
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid author ID on moderation case", "case_id", moderationCase.ID.Hex(), "error", err)
+		return
+	}
+	if err := s.notificationService.NotifyModerationNotice(ctx, authorID, moderationCase, message); err != nil {
+		slog.ErrorContext(ctx, "failed to notify author", "case_id", moderationCase.ID.Hex(), "error", err)
+	}
+}
+
+// appendLog records a moderation event. The action has already happened, so a
+// failure to log does not undo it, but it is returned so the audit gap is not silent.
+func (s *moderationService) appendLog(ctx context.Context, moderationCase *domain.ModerationCase, actorID primitive.ObjectID, action domain.ModerationAction, note string) error {
+	entry := &domain.ModerationLogEntry{
+		CaseID:      moderationCase.ID,
+		ContentType: moderationCase.ContentType,
+		ContentID:   moderationCase.ContentID,
+		ActorID:     actorID,
+		Action:      action,
+		Note:        note,
+	}
+	if err := s.moderationRepo.AppendLog(ctx, entry); err != nil {
+		return fmt.Errorf("failed to append moderation log for case %s: %w", moderationCase.ID.Hex(), err)
+	}
+	return nil
+}
@@ -3,37 +3,29 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
 	"proofofpeacemaking/internal/core/ports"
-
-	"github.com/mailgun/mailgun-go/v4"
+	"proofofpeacemaking/internal/tracing"
 )
 
 type newsletterService struct {
-	mailgunClient *mailgun.MailgunImpl
+	mailer    ports.Mailer
+	recipient string
 }
 
-func NewNewsletterService(mailgunClient *mailgun.MailgunImpl) ports.NewsletterService {
+// NewNewsletterService reports newsletter sign-ups to recipient
+func NewNewsletterService(mailer ports.Mailer, recipient string) ports.NewsletterService {
 	return &newsletterService{
-		mailgunClient: mailgunClient,
+		mailer:    mailer,
+		recipient: recipient,
 	}
 }
 
 func (s *newsletterService) SendContactEmail(ctx context.Context, who string) error {
-	s.mailgunClient.SetAPIBase("https://api.eu.mailgun.net/v3")
-
-	sender := os.Getenv("EMAIL_SENDER_ADDRESS")
-	recipient := os.Getenv("CONTACT_EMAIL_RECIPIENT_ADDRESS")
-	subject := "Newsletter"
-	body := generateContactEmailBody(who)
-	message := s.mailgunClient.NewMessage(sender, subject, "", recipient)
-	message.SetHtml(body)
-
-	_, _, err := s.mailgunClient.Send(ctx, message)
+	ctx, span := tracing.Start(ctx, "NewsletterService.SendContactEmail")
+	defer span.End()
 
-	if err != nil {
-		log.Fatal(err)
+	if err := s.mailer.Send(ctx, s.recipient, "Newsletter", generateContactEmailBody(who)); err != nil {
+		return fmt.Errorf("failed to send newsletter registration: %w", err)
 	}
 
 	return nil
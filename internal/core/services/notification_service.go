@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"fmt"
+	"proofofpeacemaking/internal/tracing"
+	"time"
 
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/metrics"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -30,6 +33,9 @@ func (s *notificationService) NotifyNewAcknowledgement(
 	expression *domain.Expression,
 	acknowledgement *domain.Acknowledgement,
 ) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyNewAcknowledgement")
+	defer span.End()
+
 	notification := &domain.Notification{
 		Type:    domain.NotificationNewAcknowledgement,
 		Title:   "New Acknowledgement",
@@ -57,16 +63,19 @@ func (s *notificationService) NotifyNewAcknowledgement(
 		CreatedAt:      notification.CreatedAt,
 	}
 
-	return s.notificationRepo.CreateUserNotification(ctx, userNotification)
+	return s.deliver(ctx, notification.Type, userNotification)
 }
 
 func (s *notificationService) GetUserNotifications(ctx context.Context, userAddress string) ([]*domain.Notification, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetUserNotifications")
+	defer span.End()
+
 	user, err := s.userRepo.GetByAddress(ctx, userAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, domain.NotFound("user not found")
 	}
 
 	notifications, err := s.notificationRepo.GetUserUnreadNotifications(ctx, user.ID)
@@ -78,17 +87,20 @@ func (s *notificationService) GetUserNotifications(ctx context.Context, userAddr
 }
 
 func (s *notificationService) MarkNotificationAsRead(ctx context.Context, userAddress string, notificationID string) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.MarkNotificationAsRead")
+	defer span.End()
+
 	user, err := s.userRepo.GetByAddress(ctx, userAddress)
 	if err != nil {
 		return fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return fmt.Errorf("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	notificationObjectID, err := primitive.ObjectIDFromHex(notificationID)
 	if err != nil {
-		return fmt.Errorf("invalid notification ID: %w", err)
+		return domain.WrapError(domain.ErrValidation, err, "invalid notification ID")
 	}
 
 	err = s.notificationRepo.MarkAsRead(ctx, user.ID, notificationObjectID)
@@ -100,6 +112,9 @@ func (s *notificationService) MarkNotificationAsRead(ctx context.Context, userAd
 }
 
 func (s *notificationService) NotifyNFTMinted(ctx context.Context, nft *domain.ProofNFT) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyNFTMinted")
+	defer span.End()
+
 	notification := &domain.Notification{
 		Type:    domain.NotificationNFTMinted,
 		Title:   "NFT Minted",
@@ -134,7 +149,7 @@ func (s *notificationService) NotifyNFTMinted(ctx context.Context, nft *domain.P
 			NotificationID: notification.ID,
 			CreatedAt:      notification.CreatedAt,
 		}
-		if err := s.notificationRepo.CreateUserNotification(ctx, userNotification); err != nil {
+		if err := s.deliver(ctx, notification.Type, userNotification); err != nil {
 			return err
 		}
 	}
@@ -143,6 +158,9 @@ func (s *notificationService) NotifyNFTMinted(ctx context.Context, nft *domain.P
 }
 
 func (s *notificationService) NotifyProofRequestReceived(ctx context.Context, request *domain.ProofRequest) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyProofRequestReceived")
+	defer span.End()
+
 	notification := &domain.Notification{
 		Type:    domain.NotificationProofRequestReceived,
 		Title:   "New Proof Request",
@@ -169,10 +187,13 @@ func (s *notificationService) NotifyProofRequestReceived(ctx context.Context, re
 		CreatedAt:      notification.CreatedAt,
 	}
 
-	return s.notificationRepo.CreateUserNotification(ctx, userNotification)
+	return s.deliver(ctx, notification.Type, userNotification)
 }
 
 func (s *notificationService) NotifyProofRequestAccepted(ctx context.Context, request *domain.ProofRequest) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyProofRequestAccepted")
+	defer span.End()
+
 	notification := &domain.Notification{
 		Type:    domain.NotificationProofRequestAccepted,
 		Title:   "Proof Request Accepted",
@@ -199,10 +220,13 @@ func (s *notificationService) NotifyProofRequestAccepted(ctx context.Context, re
 		CreatedAt:      notification.CreatedAt,
 	}
 
-	return s.notificationRepo.CreateUserNotification(ctx, userNotification)
+	return s.deliver(ctx, notification.Type, userNotification)
 }
 
 func (s *notificationService) NotifyProofRequestRejected(ctx context.Context, request *domain.ProofRequest) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyProofRequestRejected")
+	defer span.End()
+
 	notification := &domain.Notification{
 		Type:    domain.NotificationProofRequestRejected,
 		Title:   "Proof Request Rejected",
@@ -229,5 +253,176 @@ func (s *notificationService) NotifyProofRequestRejected(ctx context.Context, re
 		CreatedAt:      notification.CreatedAt,
 	}
 
-	return s.notificationRepo.CreateUserNotification(ctx, userNotification)
+	return s.deliver(ctx, notification.Type, userNotification)
+}
+
+func (s *notificationService) NotifySecurityAlert(ctx context.Context, event *domain.SecurityEvent) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifySecurityAlert")
+	defer span.End()
+
+	notification := &domain.Notification{
+		Type:    domain.NotificationSecurityAlert,
+		Title:   "Security Alert",
+		Message: "We noticed unusual activity on one of your passkeys. If this wasn't you, revoke it from your account settings.",
+		Data: map[string]interface{}{
+			"eventId":   event.ID,
+			"eventType": event.Type,
+			"action":    event.Action,
+		},
+		CreatedAt: event.CreatedAt,
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	userNotification := &domain.UserNotification{
+		UserID:         event.UserID,
+		NotificationID: notification.ID,
+		CreatedAt:      notification.CreatedAt,
+	}
+
+	return s.deliver(ctx, notification.Type, userNotification)
+}
+
+func (s *notificationService) NotifyModerationNotice(ctx context.Context, userID primitive.ObjectID, moderationCase *domain.ModerationCase, message string) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyModerationNotice")
+	defer span.End()
+
+	notification := &domain.Notification{
+		Type:    domain.NotificationModerationNotice,
+		Title:   "Community Guidelines",
+		Message: message,
+		Data: map[string]interface{}{
+			"caseId":      moderationCase.ID,
+			"contentType": moderationCase.ContentType,
+			"contentId":   moderationCase.ContentID,
+			"action":      moderationCase.Resolution,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	userNotification := &domain.UserNotification{
+		UserID:         userID,
+		NotificationID: notification.ID,
+		CreatedAt:      notification.CreatedAt,
+	}
+
+	return s.deliver(ctx, notification.Type, userNotification)
+}
+
+func (s *notificationService) NotifyInvitationReceived(ctx context.Context, invitation *domain.Invitation) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyInvitationReceived")
+	defer span.End()
+
+	notification := &domain.Notification{
+		Type:    domain.NotificationInvitationReceived,
+		Title:   "New Invitation",
+		Message: "You have been invited to acknowledge an expression",
+		Data: map[string]interface{}{
+			"invitationId": invitation.ID,
+			"expressionId": invitation.ExpressionID,
+			"invitedBy":    invitation.CreatorID,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	inviteeID, err := primitive.ObjectIDFromHex(invitation.InviteeID)
+	if err != nil {
+		return fmt.Errorf("invalid invitee ID format: %w", err)
+	}
+
+	userNotification := &domain.UserNotification{
+		UserID:         inviteeID,
+		NotificationID: notification.ID,
+		CreatedAt:      notification.CreatedAt,
+	}
+
+	return s.deliver(ctx, notification.Type, userNotification)
+}
+
+func (s *notificationService) NotifyInvitationAccepted(ctx context.Context, invitation *domain.Invitation) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyInvitationAccepted")
+	defer span.End()
+
+	notification := &domain.Notification{
+		Type:    domain.NotificationInvitationAccepted,
+		Title:   "Invitation Accepted",
+		Message: "Your invitation to acknowledge your expression has been accepted",
+		Data: map[string]interface{}{
+			"invitationId": invitation.ID,
+			"expressionId": invitation.ExpressionID,
+			"acceptedBy":   invitation.InviteeID,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	creatorID, err := primitive.ObjectIDFromHex(invitation.CreatorID)
+	if err != nil {
+		return fmt.Errorf("invalid creator ID format: %w", err)
+	}
+
+	userNotification := &domain.UserNotification{
+		UserID:         creatorID,
+		NotificationID: notification.ID,
+		CreatedAt:      notification.CreatedAt,
+	}
+
+	return s.deliver(ctx, notification.Type, userNotification)
+}
+
+func (s *notificationService) NotifyNewReply(ctx context.Context, reply *domain.Reply, recipientID string) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.NotifyNewReply")
+	defer span.End()
+
+	notification := &domain.Notification{
+		Type:    domain.NotificationNewReply,
+		Title:   "New Reply",
+		Message: "You have a new reply in your dialogue",
+		Data: map[string]interface{}{
+			"replyId":           reply.ID,
+			"acknowledgementId": reply.AcknowledgementID,
+			"expressionId":      reply.ExpressionID,
+			"author":            reply.AuthorID,
+		},
+		CreatedAt: reply.CreatedAt,
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	recipient, err := primitive.ObjectIDFromHex(recipientID)
+	if err != nil {
+		return fmt.Errorf("invalid recipient ID format: %w", err)
+	}
+
+	userNotification := &domain.UserNotification{
+		UserID:         recipient,
+		NotificationID: notification.ID,
+		CreatedAt:      notification.CreatedAt,
+	}
+
+	return s.deliver(ctx, notification.Type, userNotification)
+}
+
+// deliver links a notification to one recipient and counts the fan-out
+func (s *notificationService) deliver(ctx context.Context, notificationType domain.NotificationType, userNotification *domain.UserNotification) error {
+	if err := s.notificationRepo.CreateUserNotification(ctx, userNotification); err != nil {
+		return err
+	}
+	metrics.NotificationRecipients.WithLabelValues(string(notificationType)).Inc()
+	return nil
 }
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type proofMintService struct {
+	pairingRepo         ports.ExpressionAcknowledgementRepository
+	proofNFTRepo        ports.ProofNFTRepository
+	expressionRepo      ports.ExpressionRepository
+	acknowledgementRepo ports.AcknowledgementRepository
+	userRepo            ports.UserRepository
+	txRepo              ports.RelayedTransactionRepository
+	relayer             ports.RelayerService
+	notificationService ports.NotificationService
+	client              chain.Client
+	diamond             common.Address
+	siteURL             string
+}
+
+// NewProofMintService mints proof NFTs through the relayer's operator
+// account, which must own the Diamond at diamond. Each token's URI is its
+// verification page under siteURL.
+func NewProofMintService(
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	proofNFTRepo ports.ProofNFTRepository,
+	expressionRepo ports.ExpressionRepository,
+	acknowledgementRepo ports.AcknowledgementRepository,
+	userRepo ports.UserRepository,
+	txRepo ports.RelayedTransactionRepository,
+	relayer ports.RelayerService,
+	notificationService ports.NotificationService,
+	client chain.Client,
+	diamond common.Address,
+	siteURL string,
+) ports.ProofMintService {
+	return &proofMintService{
+		pairingRepo:         pairingRepo,
+		proofNFTRepo:        proofNFTRepo,
+		expressionRepo:      expressionRepo,
+		acknowledgementRepo: acknowledgementRepo,
+		userRepo:            userRepo,
+		txRepo:              txRepo,
+		relayer:             relayer,
+		notificationService: notificationService,
+		client:              client,
+		diamond:             diamond,
+		siteURL:             siteURL,
+	}
+}
+
+func (s *proofMintService) Mint(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ProofMintService.Mint")
+	defer span.End()
+
+	if err := s.settle(ctx); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to settle proof mints: %w", err)
+	}
+
+	approved, err := s.pairingRepo.FindByNFTStatus(ctx, domain.NFTStatusApproved)
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	for _, pairing := range approved {
+		if err := s.send(ctx, pairing); err != nil {
+			tracing.Fail(span, err)
+			return fmt.Errorf("failed to mint proof NFT of pairing %s: %w", pairing.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *proofMintService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Mint(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to mint proof NFTs", "error", err)
+			}
+		}
+	}
+}
+
+// send mints an approved pairing's NFT. Pairings whose expression is not
+// on-chain yet, or whose acknowledger has no wallet, wait for both.
+func (s *proofMintService) send(ctx context.Context, pairing *domain.ExpressionAcknowledgement) error {
+	nft, err := s.proofNFTRepo.FindByID(ctx, pairing.ProofNFTID)
+	if err != nil {
+		return err
+	}
+	if nft == nil {
+		slog.WarnContext(ctx, "approved pairing has no proof NFT", "pairing_id", pairing.ID.Hex())
+		return nil
+	}
+	expression, err := s.expressionRepo.FindByID(ctx, pairing.ExpressionID)
+	if err != nil {
+		return err
+	}
+	acknowledgement, err := s.acknowledgementRepo.FindByID(ctx, pairing.AcknowledgementID)
+	if err != nil {
+		return err
+	}
+	acknowledger, err := s.userRepo.GetByID(ctx, pairing.AcknowledgerID)
+	if err != nil {
+		return err
+	}
+	if expression == nil || acknowledgement == nil || acknowledger == nil {
+		return s.fail(ctx, pairing, nft, domain.NFTStatusApproved, "the expression, acknowledgement or acknowledger no longer exists")
+	}
+	if expression.OnChainID == 0 || !common.IsHexAddress(expression.CreatorAddress) || !common.IsHexAddress(acknowledger.Address) {
+		return nil
+	}
+
+	data, err := chain.PackMint(
+		common.HexToAddress(acknowledger.Address),
+		expression.OnChainID,
+		acknowledgement.OnChainID,
+		common.HexToAddress(expression.CreatorAddress),
+		s.siteURL+"/verify/"+nft.ID.Hex(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Moving the pairing first claims it, so a mint is only sent once
+	pairing.NFTStatus = domain.NFTStatusMinting
+	if err := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusApproved); err != nil {
+		return err
+	}
+	tx, err := s.relayer.SubmitOperatorCall(ctx, domain.RelayMintProof, data)
+	if err != nil {
+		pairing.NFTStatus = domain.NFTStatusApproved
+		if revertErr := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusMinting); revertErr != nil {
+			slog.ErrorContext(ctx, "failed to release pairing after mint was refused", "pairing_id", pairing.ID.Hex(), "error", revertErr)
+		}
+		return err
+	}
+	nft.RelayID = &tx.ID
+	if err := s.proofNFTRepo.Update(ctx, nft); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "proof NFT mint sent", "proof_nft_id", nft.ID.Hex(), "relay_id", tx.ID.Hex())
+	return nil
+}
+
+// settle records the outcome of sent mints
+func (s *proofMintService) settle(ctx context.Context) error {
+	minting, err := s.pairingRepo.FindByNFTStatus(ctx, domain.NFTStatusMinting)
+	if err != nil {
+		return err
+	}
+	for _, pairing := range minting {
+		nft, err := s.proofNFTRepo.FindByID(ctx, pairing.ProofNFTID)
+		if err != nil {
+			return err
+		}
+		if nft == nil || nft.RelayID == nil {
+			continue
+		}
+		tx, err := s.txRepo.FindByID(ctx, *nft.RelayID)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			continue
+		}
+
+		switch tx.Status {
+		case domain.RelayStatusConfirmed:
+			if err := s.minted(ctx, pairing, nft, tx); err != nil {
+				return err
+			}
+		case domain.RelayStatusFailed:
+			if err := s.fail(ctx, pairing, nft, domain.NFTStatusMinting, tx.Error); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// minted records the token a confirmed mint created and tells the acknowledger
+func (s *proofMintService) minted(ctx context.Context, pairing *domain.ExpressionAcknowledgement, nft *domain.ProofNFT, tx *domain.RelayedTransaction) error {
+	receipt, err := s.client.TransactionReceipt(ctx, common.HexToHash(tx.TxHash))
+	if err != nil {
+		return fmt.Errorf("failed to get mint receipt: %w", err)
+	}
+	tokenID, ok := chain.MintedTokenID(receipt, s.diamond)
+	if !ok {
+		return s.fail(ctx, pairing, nft, domain.NFTStatusMinting, "the mint transaction emitted no POPNFTMinted event")
+	}
+
+	now := time.Now()
+	nft.TokenID = int(tokenID.Int64())
+	nft.Status = domain.ProofNFTStatusMinted
+	nft.MintedAt = &now
+	nft.TxHash = tx.TxHash
+	if err := s.proofNFTRepo.Update(ctx, nft); err != nil {
+		return err
+	}
+	pairing.NFTStatus = domain.NFTStatusMinted
+	if err := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusMinting); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "proof NFT minted", "proof_nft_id", nft.ID.Hex(), "token_id", nft.TokenID, "tx_hash", tx.TxHash)
+
+	if err := s.notificationService.NotifyNFTMinted(ctx, nft); err != nil {
+		slog.ErrorContext(ctx, "failed to notify of minted proof NFT", "proof_nft_id", nft.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+// fail marks the NFT and its pairing failed
+func (s *proofMintService) fail(ctx context.Context, pairing *domain.ExpressionAcknowledgement, nft *domain.ProofNFT, from domain.NFTStatus, reason string) error {
+	nft.Status = domain.ProofNFTStatusFailed
+	nft.Error = reason
+	if err := s.proofNFTRepo.Update(ctx, nft); err != nil {
+		return err
+	}
+	pairing.NFTStatus = domain.NFTStatusFailed
+	if err := s.pairingRepo.Update(ctx, pairing, from); err != nil {
+		return err
+	}
+	slog.WarnContext(ctx, "proof NFT mint failed", "proof_nft_id", nft.ID.Hex(), "pairing_id", pairing.ID.Hex(), "error", reason)
+	return nil
+}
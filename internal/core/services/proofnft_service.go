@@ -3,77 +3,217 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type proofNFTService struct {
-	userRepo     ports.UserRepository
-	proofNFTRepo ports.ProofNFTRepository
+	pairingRepo      ports.ExpressionAcknowledgementRepository
+	proofRequestRepo ports.ProofRequestRepository
+	proofNFTRepo     ports.ProofNFTRepository
 }
 
-func NewProofNFTService(userRepo ports.UserRepository, proofNFTRepo ports.ProofNFTRepository) ports.ProofNFTService {
+func NewProofNFTService(
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	proofRequestRepo ports.ProofRequestRepository,
+	proofNFTRepo ports.ProofNFTRepository,
+) ports.ProofNFTService {
 	return &proofNFTService{
-		userRepo:     userRepo,
-		proofNFTRepo: proofNFTRepo,
+		pairingRepo:      pairingRepo,
+		proofRequestRepo: proofRequestRepo,
+		proofNFTRepo:     proofNFTRepo,
 	}
 }
 
-func (s *proofNFTService) RequestProof(ctx context.Context, expressionID string, acknowledgementID string) error {
-	// Get user from context
-	user, err := s.userRepo.GetByAddress(ctx, expressionID)
+func (s *proofNFTService) RequestProof(ctx context.Context, user *domain.User, expressionID string, acknowledgementID string) (*domain.ProofRequest, error) {
+	ctx, span := tracing.Start(ctx, "ProofNFTService.RequestProof")
+	defer span.End()
+
+	pairing, err := s.pairingRepo.FindByAcknowledgement(ctx, acknowledgementID)
+	if err != nil {
+		return nil, err
+	}
+	if pairing == nil || pairing.ExpressionID != expressionID {
+		return nil, domain.ErrPairingNotFound
+	}
+	userID := user.ID.Hex()
+	if !pairing.HasParty(userID) {
+		return nil, domain.ErrPairingNotParty
+	}
+	if pairing.AcknowledgementStatus != domain.AcknowledgementStatusActive {
+		return nil, domain.ErrPairingRefuted
+	}
+	if pairing.NFTStatus != domain.NFTStatusNone {
+		return nil, domain.ErrPairingNFTStatus
+	}
+
+	request := &domain.ProofRequest{
+		ID:           primitive.NewObjectID(),
+		PairingID:    pairing.ID.Hex(),
+		ExpressionID: pairing.ExpressionID,
+		InitiatorID:  userID,
+		PeerID:       pairing.OtherParty(userID),
+		Status:       domain.ProofRequestPending,
+	}
+
+	// Moving the pairing first claims it, so only one request is ever open
+	now := time.Now()
+	pairing.NFTStatus = domain.NFTStatusRequested
+	pairing.NFTRequestedBy = userID
+	pairing.NFTRequestedAt = &now
+	pairing.ProofRequestID = request.ID.Hex()
+	if err := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusNone); err != nil {
+		return nil, err
+	}
+	if err := s.proofRequestRepo.Create(ctx, request); err != nil {
+		tracing.Fail(span, err)
+		pairing.NFTStatus = domain.NFTStatusNone
+		pairing.NFTRequestedBy = ""
+		pairing.NFTRequestedAt = nil
+		pairing.ProofRequestID = ""
+		if revertErr := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusRequested); revertErr != nil {
+			slog.ErrorContext(ctx, "failed to release pairing after proof request failed", "pairing_id", pairing.ID.Hex(), "error", revertErr)
+		}
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "proof requested", "request_id", request.ID.Hex(), "pairing_id", pairing.ID.Hex())
+	return request, nil
+}
+
+func (s *proofNFTService) ApproveProof(ctx context.Context, user *domain.User, requestID string) (*domain.ProofNFT, error) {
+	ctx, span := tracing.Start(ctx, "ProofNFTService.ApproveProof")
+	defer span.End()
+
+	request, pairing, err := s.pendingRequest(ctx, requestID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
+		return nil, err
 	}
-	if user == nil {
-		return fmt.Errorf("user not found")
+	if request.PeerID != user.ID.Hex() {
+		return nil, domain.ErrProofRequestNotPeer
 	}
 
-	// Create new ProofNFT
 	proofNFT := &domain.ProofNFT{
 		ID:           primitive.NewObjectID(),
-		TokenID:      0, // Will be set by smart contract
-		Expression:   expressionID,
-		Acknowledger: user.ID.Hex(),
-		Status:       string(domain.ProofRequestPending),
+		PairingID:    pairing.ID.Hex(),
+		Expression:   pairing.ExpressionID,
+		Acknowledger: pairing.AcknowledgerID,
+		Status:       domain.ProofNFTStatusPending,
 		CreatedAt:    time.Now(),
 	}
 
-	// Save to database
+	// Moving the pairing first claims it, as in RequestProof, so a request is
+	// answered once; the steps after it hand the pairing back if they fail
+	now := time.Now()
+	pairing.NFTStatus = domain.NFTStatusApproved
+	pairing.NFTApprovedBy = user.ID.Hex()
+	pairing.NFTApprovedAt = &now
+	pairing.ProofNFTID = proofNFT.ID.Hex()
+	if err := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusRequested); err != nil {
+		return nil, err
+	}
+
+	request.Status = domain.ProofRequestAccepted
+	if err := s.proofRequestRepo.Update(ctx, request, domain.ProofRequestPending); err != nil {
+		tracing.Fail(span, err)
+		s.releaseApproval(ctx, pairing)
+		return nil, err
+	}
 	if err := s.proofNFTRepo.Create(ctx, proofNFT); err != nil {
-		return fmt.Errorf("failed to create proof NFT: %w", err)
+		tracing.Fail(span, err)
+		request.Status = domain.ProofRequestPending
+		if revertErr := s.proofRequestRepo.Update(ctx, request, domain.ProofRequestAccepted); revertErr != nil {
+			slog.ErrorContext(ctx, "failed to reopen proof request after proof NFT creation failed", "request_id", requestID, "error", revertErr)
+		}
+		s.releaseApproval(ctx, pairing)
+		return nil, fmt.Errorf("failed to create proof NFT: %w", err)
 	}
 
-	return nil
+	slog.InfoContext(ctx, "proof approved", "request_id", requestID, "proof_nft_id", proofNFT.ID.Hex())
+	return proofNFT, nil
 }
 
-func (s *proofNFTService) ApproveProof(ctx context.Context, requestID string) error {
-	// Get user from context
-	user, err := s.userRepo.GetByAddress(ctx, requestID)
+func (s *proofNFTService) RejectProof(ctx context.Context, user *domain.User, requestID string) error {
+	ctx, span := tracing.Start(ctx, "ProofNFTService.RejectProof")
+	defer span.End()
+
+	request, pairing, err := s.pendingRequest(ctx, requestID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
+		return err
 	}
-	if user == nil {
-		return fmt.Errorf("user not found")
+	if request.PeerID != user.ID.Hex() {
+		return domain.ErrProofRequestNotPeer
 	}
+	return withdrawProofRequest(ctx, s.pairingRepo, s.proofRequestRepo, pairing, domain.ProofRequestRejected)
+}
+
+func (s *proofNFTService) CancelProof(ctx context.Context, user *domain.User, requestID string) error {
+	ctx, span := tracing.Start(ctx, "ProofNFTService.CancelProof")
+	defer span.End()
 
-	// TODO: Implement proof approval logic
-	return nil
+	request, pairing, err := s.pendingRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.InitiatorID != user.ID.Hex() {
+		return domain.ErrProofRequestNotInitiator
+	}
+	return withdrawProofRequest(ctx, s.pairingRepo, s.proofRequestRepo, pairing, domain.ProofRequestCancelled)
 }
 
-func (s *proofNFTService) ListUserProofs(ctx context.Context, userAddress string) ([]*domain.ProofNFT, error) {
-	// Get user by address
-	user, err := s.userRepo.GetByAddress(ctx, userAddress)
+func (s *proofNFTService) ListUserProofs(ctx context.Context, user *domain.User) ([]*domain.ExpressionAcknowledgement, error) {
+	ctx, span := tracing.Start(ctx, "ProofNFTService.ListUserProofs")
+	defer span.End()
+
+	pairings, err := s.pairingRepo.FindByUser(ctx, user.ID.Hex())
 	if err != nil {
-		return nil, fmt.Errorf("failed to find user: %w", err)
+		return nil, err
 	}
-	if user == nil {
-		return nil, fmt.Errorf("user not found")
+	proofs := make([]*domain.ExpressionAcknowledgement, 0, len(pairings))
+	for _, pairing := range pairings {
+		if pairing.NFTStatus != domain.NFTStatusNone {
+			proofs = append(proofs, pairing)
+		}
 	}
+	return proofs, nil
+}
 
-	// TODO: Implement listing user proofs
-	return nil, nil
+// releaseApproval returns an approved pairing to REQUESTED after the rest of
+// the approval failed
+func (s *proofNFTService) releaseApproval(ctx context.Context, pairing *domain.ExpressionAcknowledgement) {
+	pairing.NFTStatus = domain.NFTStatusRequested
+	pairing.NFTApprovedBy = ""
+	pairing.NFTApprovedAt = nil
+	pairing.ProofNFTID = ""
+	if err := s.pairingRepo.Update(ctx, pairing, domain.NFTStatusApproved); err != nil {
+		slog.ErrorContext(ctx, "failed to release pairing after proof approval failed", "pairing_id", pairing.ID.Hex(), "error", err)
+	}
+}
+
+// pendingRequest loads a pending proof request and the pairing it is open on
+func (s *proofNFTService) pendingRequest(ctx context.Context, requestID string) (*domain.ProofRequest, *domain.ExpressionAcknowledgement, error) {
+	request, err := s.proofRequestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request == nil {
+		return nil, nil, domain.ErrProofRequestNotFound
+	}
+	if request.Status != domain.ProofRequestPending {
+		return nil, nil, domain.ErrProofRequestNotPending
+	}
+
+	pairing, err := s.pairingRepo.FindByID(ctx, request.PairingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pairing == nil || pairing.ProofRequestID != requestID || pairing.NFTStatus != domain.NFTStatusRequested {
+		return nil, nil, domain.ErrProofRequestNotPending
+	}
+	return request, pairing, nil
 }
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+)
+
+type rateLimitService struct {
+	store   ports.RateLimitStore
+	lockout domain.LockoutPolicy
+}
+
+func NewRateLimitService(store ports.RateLimitStore, lockout domain.LockoutPolicy) ports.RateLimitService {
+	return &rateLimitService{
+		store:   store,
+		lockout: lockout,
+	}
+}
+
+func (s *rateLimitService) Allow(ctx context.Context, policy domain.RateLimitPolicy, subject string) (*domain.RateLimitDecision, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.Allow")
+	defer span.End()
+
+	count, resetAt, err := s.store.Increment(ctx, "rate:"+policy.Name+":"+subject, policy.Window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count request: %w", err)
+	}
+
+	remaining := policy.Limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &domain.RateLimitDecision{
+		Allowed:   count <= policy.Limit,
+		Limit:     policy.Limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}, nil
+}
+
+func (s *rateLimitService) CheckLockout(ctx context.Context, subject string) (time.Time, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.CheckLockout")
+	defer span.End()
+
+	until, err := s.store.LockedUntil(ctx, lockoutKey(subject))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to check lockout: %w", err)
+	}
+	if !until.IsZero() {
+		return until, domain.ErrAccountLocked
+	}
+	return time.Time{}, nil
+}
+
+func (s *rateLimitService) RecordFailure(ctx context.Context, subject string) (time.Time, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.RecordFailure")
+	defer span.End()
+
+	failures, _, err := s.store.Increment(ctx, lockoutKey(subject), s.lockout.FailureWindow)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to record sign-in failure: %w", err)
+	}
+
+	delay := s.lockout.Delay(failures)
+	if delay == 0 {
+		return time.Time{}, nil
+	}
+
+	until := time.Now().Add(delay)
+	if err := s.store.Lock(ctx, lockoutKey(subject), until); err != nil {
+		return time.Time{}, fmt.Errorf("failed to lock account: %w", err)
+	}
+	slog.WarnContext(ctx, "sign-in locked", "subject", subject, "delay", delay, "failures", failures)
+	return until, nil
+}
+
+func (s *rateLimitService) RecordSuccess(ctx context.Context, subject string) error {
+	ctx, span := tracing.Start(ctx, "RateLimitService.RecordSuccess")
+	defer span.End()
+
+	if err := s.store.Reset(ctx, lockoutKey(subject)); err != nil {
+		return fmt.Errorf("failed to clear sign-in failures: %w", err)
+	}
+	return nil
+}
+
+func lockoutKey(subject string) string {
+	return "lockout:" + subject
+}
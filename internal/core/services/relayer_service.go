@@ -0,0 +1,788 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/metrics"
+	"proofofpeacemaking/internal/tracing"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// relayFeeBumpPercent raises both fees of a replacement transaction. Nodes
+	// only accept a replacement that pays at least 10% more.
+	relayFeeBumpPercent = 20
+	// relayGasHeadroomPercent is added to gas estimates, which can fall short
+	// when state changes between estimation and inclusion
+	relayGasHeadroomPercent = 20
+)
+
+// txIndexingMessage is how geth answers a receipt lookup while its
+// transaction index is incomplete, which includes transactions it never saw
+const txIndexingMessage = "transaction indexing is in progress"
+
+// errFeesAboveCap is returned when the network's base fee alone exceeds what
+// the relayer may pay; the request is retried once fees drop
+var errFeesAboveCap = errors.New("network fees are above the relayer's cap")
+
+type relayerService struct {
+	txRepo                 ports.RelayedTransactionRepository
+	budgetRepo             ports.OperatorBudgetRepository
+	expressionRepo         ports.ExpressionRepository
+	acknowledgementService ports.AcknowledgementService
+	screener               ports.ContentScreener
+	client                 chain.Client
+	operator               *ecdsa.PrivateKey
+	chainID                *big.Int
+	diamond                common.Address
+	domain                 domain.RelayDomain
+	policy                 domain.RelayPolicy
+
+	// mu serialises sending, so operator nonces are handed out in order
+	mu sync.Mutex
+	// nextNonce is the operator's next unused nonce, or nil until read from
+	// the node or after a send failed and the count may be off
+	nextNonce *uint64
+}
+
+// NewRelayerService sends relayed calls to the Diamond at diamond from the
+// operator account, on the chain the client is connected to. Relayed content
+// passes the screener and is recorded in the app before it is sent.
+func NewRelayerService(
+	txRepo ports.RelayedTransactionRepository,
+	budgetRepo ports.OperatorBudgetRepository,
+	expressionRepo ports.ExpressionRepository,
+	acknowledgementService ports.AcknowledgementService,
+	screener ports.ContentScreener,
+	client chain.Client,
+	operator *ecdsa.PrivateKey,
+	chainID int64,
+	diamond common.Address,
+	policy domain.RelayPolicy,
+) ports.RelayerService {
+	return &relayerService{
+		txRepo:                 txRepo,
+		budgetRepo:             budgetRepo,
+		expressionRepo:         expressionRepo,
+		acknowledgementService: acknowledgementService,
+		screener:               screener,
+		client:                 client,
+		operator:               operator,
+		chainID:                big.NewInt(chainID),
+		diamond:                diamond,
+		domain:                 chain.NewRelayDomain(chainID, diamond),
+		policy:                 policy,
+	}
+}
+
+func (s *relayerService) operatorAddress() common.Address {
+	return crypto.PubkeyToAddress(s.operator.PublicKey)
+}
+
+func (s *relayerService) Domain() domain.RelayDomain {
+	return s.domain
+}
+
+func (s *relayerService) NextNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "RelayerService.NextNonce")
+	defer span.End()
+
+	if !common.IsHexAddress(address) {
+		return 0, domain.Validation("invalid address")
+	}
+	return s.txRepo.NextNonce(ctx, common.HexToAddress(address).Hex())
+}
+
+func (s *relayerService) Submit(ctx context.Context, user *domain.User, req *domain.RelayRequest) (*domain.RelayedTransaction, error) {
+	ctx, span := tracing.Start(ctx, "RelayerService.Submit")
+	defer span.End()
+
+	subsidy := req.Operation.Subsidy()
+	if subsidy == "" {
+		return nil, domain.Validation("unknown relay operation %q", req.Operation)
+	}
+	if !common.IsHexAddress(req.From) {
+		return nil, domain.Validation("invalid sender address")
+	}
+	from := common.HexToAddress(req.From)
+	req.From = from.Hex()
+
+	// The signer must be the wallet linked to the account whose subsidies pay
+	if user.Address == "" || !strings.EqualFold(user.Address, req.From) {
+		return nil, domain.Forbidden("requests must be signed by the wallet linked to this account")
+	}
+	if !user.IsSubsidized(subsidy) {
+		return nil, domain.ErrRelayNotSubsidized
+	}
+	if req.Expired(time.Now()) {
+		return nil, domain.ErrRelayRequestExpired
+	}
+
+	digest, err := chain.HashRelayRequest(s.domain, req)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrValidation, err, "invalid relay request")
+	}
+	signer, err := chain.RecoverSigner(digest, req.Signature)
+	if err != nil || signer != from {
+		return nil, domain.ErrRelaySignatureInvalid
+	}
+	if _, err := relayCalldata(req); err != nil {
+		return nil, err
+	}
+
+	// Requests are relayed in nonce order, so a signed request cannot be
+	// replayed or jump ahead of one still waiting
+	next, err := s.txRepo.NextNonce(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+	if req.Nonce != next {
+		return nil, domain.ErrRelayNonceUsed
+	}
+	if err := s.checkBudget(ctx, req.Operation); err != nil {
+		return nil, err
+	}
+
+	// Whatever reaches the chain stays there, so the content is screened and
+	// recorded in the app before the request is queued
+	recordID, err := s.record(ctx, user, req)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	tx := &domain.RelayedTransaction{
+		UserID:      user.ID,
+		Request:     *req,
+		RequestKey:  domain.RelayRequestKey(req.From, req.Nonce),
+		RequestHash: digest.Hex(),
+		RecordID:    recordID,
+		Operator:    s.operatorAddress().Hex(),
+		Status:      domain.RelayStatusPending,
+		Attempts:    []domain.RelayAttempt{},
+	}
+	if err := s.txRepo.Create(ctx, tx); err != nil {
+		s.discardRecord(ctx, req.Operation, recordID)
+		return nil, err
+	}
+
+	// A request that cannot be sent now stays pending and is retried by
+	// ProcessPending, so the user's signature is not wasted
+	if err := s.send(ctx, tx); err != nil {
+		tracing.Fail(span, err)
+		slog.WarnContext(ctx, "failed to send relayed transaction, will retry", "relay_id", tx.ID.Hex(), "error", err)
+	}
+	return tx, nil
+}
+
+func (s *relayerService) SubmitOperatorCall(ctx context.Context, operation domain.RelayOperation, calldata []byte) (*domain.RelayedTransaction, error) {
+	ctx, span := tracing.Start(ctx, "RelayerService.SubmitOperatorCall")
+	defer span.End()
+
+	if err := s.checkBudget(ctx, operation); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	operator := s.operatorAddress().Hex()
+	tx := &domain.RelayedTransaction{
+		Request: domain.RelayRequest{
+			Operation: operation,
+			From:      operator,
+		},
+		// Operator calls have no user nonce, so each gets a key of its own
+		RequestKey: "operator:" + primitive.NewObjectID().Hex(),
+		Operator:   operator,
+		Calldata:   hexutil.Encode(calldata),
+		Status:     domain.RelayStatusPending,
+		Attempts:   []domain.RelayAttempt{},
+	}
+	if err := s.txRepo.Create(ctx, tx); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	if err := s.send(ctx, tx); err != nil {
+		tracing.Fail(span, err)
+		slog.WarnContext(ctx, "failed to send operator call, will retry", "relay_id", tx.ID.Hex(), "operation", operation, "error", err)
+	}
+	return tx, nil
+}
+
+func (s *relayerService) Get(ctx context.Context, userID primitive.ObjectID, id string) (*domain.RelayedTransaction, error) {
+	ctx, span := tracing.Start(ctx, "RelayerService.Get")
+	defer span.End()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrValidation, err, "invalid relayed transaction ID format")
+	}
+	tx, err := s.txRepo.FindByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil || tx.UserID != userID {
+		return nil, domain.ErrRelayedTransactionNotFound
+	}
+	return tx, nil
+}
+
+func (s *relayerService) List(ctx context.Context, userID primitive.ObjectID) ([]*domain.RelayedTransaction, error) {
+	ctx, span := tracing.Start(ctx, "RelayerService.List")
+	defer span.End()
+
+	txs, err := s.txRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if txs == nil {
+		txs = []*domain.RelayedTransaction{}
+	}
+	return txs, nil
+}
+
+func (s *relayerService) ProcessPending(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "RelayerService.ProcessPending")
+	defer span.End()
+
+	txs, err := s.txRepo.FindByStatus(ctx, domain.RelayStatusPending, domain.RelayStatusSubmitted)
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+
+	for _, tx := range txs {
+		var err error
+		if tx.Status == domain.RelayStatusPending {
+			err = s.retry(ctx, tx)
+		} else {
+			err = s.check(ctx, tx)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "failed to process relayed transaction", "relay_id", tx.ID.Hex(), "status", tx.Status, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *relayerService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ProcessPending(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to process relayed transactions", "error", err)
+			}
+		}
+	}
+}
+
+// retry sends a request that has not reached the network yet. Once its
+// deadline has passed it is given up, as the user no longer wants it sent.
+func (s *relayerService) retry(ctx context.Context, tx *domain.RelayedTransaction) error {
+	if tx.Request.Expired(time.Now()) {
+		return s.fail(ctx, tx, "request expired before it could be sent")
+	}
+	return s.send(ctx, tx)
+}
+
+// check records the receipt of whichever attempt was mined, or replaces the
+// transaction if it has waited too long
+func (s *relayerService) check(ctx context.Context, tx *domain.RelayedTransaction) error {
+	// Any attempt may be the one mined, as they share a nonce
+	for i := len(tx.Attempts) - 1; i >= 0; i-- {
+		hash := common.HexToHash(tx.Attempts[i].TxHash)
+		receipt, err := s.client.TransactionReceipt(ctx, hash)
+		if noReceiptYet(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get transaction receipt: %w", err)
+		}
+
+		tx.TxHash = hash.Hex()
+		tx.BlockNumber = receipt.BlockNumber.Uint64()
+		tx.GasUsed = receipt.GasUsed
+		tx.FeeGwei = feeGwei(receipt)
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return s.fail(ctx, tx, "transaction reverted")
+		}
+		tx.Status = domain.RelayStatusConfirmed
+		metrics.ObserveRelay(string(tx.Request.Operation), string(domain.RelayStatusConfirmed))
+		if err := s.txRepo.Update(ctx, tx); err != nil {
+			return err
+		}
+		s.linkOnChainID(ctx, tx, receipt)
+		return nil
+	}
+
+	last := tx.LastAttempt()
+	if last == nil || time.Since(last.SentAt) < s.policy.StuckAfter {
+		return nil
+	}
+	if len(tx.Attempts) >= s.policy.MaxAttempts {
+		slog.WarnContext(ctx, "relayed transaction is stuck and out of replacement attempts", "relay_id", tx.ID.Hex(), "tx_hash", last.TxHash)
+		return nil
+	}
+	return s.replace(ctx, tx)
+}
+
+// fail marks the request as failed for good
+func (s *relayerService) fail(ctx context.Context, tx *domain.RelayedTransaction, reason string) error {
+	tx.Status = domain.RelayStatusFailed
+	tx.Error = reason
+	metrics.ObserveRelay(string(tx.Request.Operation), string(domain.RelayStatusFailed))
+	return s.txRepo.Update(ctx, tx)
+}
+
+// send signs and sends the first transaction for a request
+func (s *relayerService) send(ctx context.Context, tx *domain.RelayedTransaction) error {
+	data, err := txCalldata(tx)
+	if err != nil {
+		return s.fail(ctx, tx, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tx.GasLimit == 0 {
+		estimate, err := s.client.EstimateGas(ctx, ethereum.CallMsg{From: s.operatorAddress(), To: &s.diamond, Data: data})
+		if err != nil {
+			// The call would revert, so sending it would only burn gas
+			return s.fail(ctx, tx, fmt.Sprintf("gas estimation failed: %v", err))
+		}
+		gasLimit := estimate + estimate*relayGasHeadroomPercent/100
+		if gasLimit > s.policy.MaxGasLimit {
+			return s.fail(ctx, tx, fmt.Sprintf("call needs %d gas, more than the relayer's cap of %d", gasLimit, s.policy.MaxGasLimit))
+		}
+		tx.GasLimit = gasLimit
+	}
+
+	tip, feeCap, err := s.fees(ctx)
+	if err != nil {
+		return err
+	}
+	nonce, err := s.takeNonce(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.sendAttempt(ctx, tx, nonce, data, tip, feeCap); err != nil {
+		// The nonce was not used; read it from the node again next time
+		s.nextNonce = nil
+		return err
+	}
+	*s.nextNonce = nonce + 1
+
+	metrics.ObserveRelay(string(tx.Request.Operation), string(domain.RelayStatusSubmitted))
+	return nil
+}
+
+// replace resends a stuck transaction with the same nonce and higher fees
+func (s *relayerService) replace(ctx context.Context, tx *domain.RelayedTransaction) error {
+	data, err := txCalldata(tx)
+	if err != nil {
+		return s.fail(ctx, tx, err.Error())
+	}
+	last := tx.LastAttempt()
+	lastTip, _ := new(big.Int).SetString(last.GasTipCap, 10)
+	lastFeeCap, _ := new(big.Int).SetString(last.GasFeeCap, 10)
+	if lastTip == nil || lastFeeCap == nil {
+		return fmt.Errorf("invalid fees recorded for transaction %s", last.TxHash)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tip, feeCap, err := s.fees(ctx)
+	if err != nil && !errors.Is(err, errFeesAboveCap) {
+		return err
+	}
+	tip = maxBig(tip, bump(lastTip))
+	feeCap = maxBig(feeCap, bump(lastFeeCap))
+	if feeCap.Cmp(s.policy.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(s.policy.MaxFeePerGas)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	// At the cap a replacement would not pay enough more to be accepted
+	if feeCap.Cmp(bumpMin(lastFeeCap)) < 0 || tip.Cmp(bumpMin(lastTip)) < 0 {
+		slog.WarnContext(ctx, "relayed transaction is stuck at the fee cap", "relay_id", tx.ID.Hex(), "tx_hash", last.TxHash)
+		return nil
+	}
+
+	if err := s.sendAttempt(ctx, tx, tx.OperatorNonce, data, tip, feeCap); err != nil {
+		return err
+	}
+	metrics.ObserveRelay(string(tx.Request.Operation), "replaced")
+	return nil
+}
+
+// sendAttempt signs one transaction, records it as a submitted attempt and
+// then sends it. The record is saved first so a transaction that reached the
+// network is never taken for unsent and sent again under a fresh nonce; if
+// sending fails the record is put back as it was.
+func (s *relayerService) sendAttempt(ctx context.Context, tx *domain.RelayedTransaction, nonce uint64, data []byte, tip, feeCap *big.Int) error {
+	signed, err := types.SignNewTx(s.operator, types.LatestSignerForChainID(s.chainID), &types.DynamicFeeTx{
+		ChainID:   s.chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       tx.GasLimit,
+		To:        &s.diamond,
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	previous := *tx
+	tx.Attempts = append(tx.Attempts, domain.RelayAttempt{
+		TxHash:    signed.Hash().Hex(),
+		GasTipCap: tip.String(),
+		GasFeeCap: feeCap.String(),
+		SentAt:    time.Now(),
+	})
+	tx.TxHash = signed.Hash().Hex()
+	tx.OperatorNonce = nonce
+	tx.Status = domain.RelayStatusSubmitted
+	if err := s.txRepo.Update(ctx, tx); err != nil {
+		*tx = previous
+		return err
+	}
+
+	if err := s.client.SendTransaction(ctx, signed); err != nil {
+		*tx = previous
+		if undoErr := s.txRepo.Update(ctx, tx); undoErr != nil {
+			slog.ErrorContext(ctx, "failed to roll back unsent relay attempt", "relay_id", tx.ID.Hex(), "tx_hash", signed.Hash().Hex(), "error", undoErr)
+		}
+		return fmt.Errorf("failed to send transaction: %w", err)
+	}
+	slog.InfoContext(ctx, "sent relayed transaction", "relay_id", tx.ID.Hex(), "tx_hash", tx.TxHash, "nonce", nonce, "attempt", len(tx.Attempts))
+	return nil
+}
+
+// fees picks the tip and fee cap for a new transaction: the node's suggested
+// tip and room for the base fee to double, within the relayer's cap
+func (s *relayerService) fees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tip, err := s.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get gas tip: %w", err)
+	}
+	head, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	baseFee := head.BaseFee
+	if baseFee == nil {
+		baseFee = new(big.Int)
+	}
+
+	if baseFee.Cmp(s.policy.MaxFeePerGas) >= 0 {
+		return tip, new(big.Int).Set(s.policy.MaxFeePerGas), errFeesAboveCap
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+	if feeCap.Cmp(s.policy.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(s.policy.MaxFeePerGas)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	return tip, feeCap, nil
+}
+
+// checkBudget refuses new user requests and operator calls once the operator
+// has spent its budget for the month. Requests already accepted are still
+// sent.
+func (s *relayerService) checkBudget(ctx context.Context, op domain.RelayOperation) error {
+	operator := s.operatorAddress().Hex()
+	budget, err := s.budgetRepo.Get(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if budget == nil {
+		return nil
+	}
+	usage, err := s.txRepo.Usage(ctx, operator, domain.BudgetPeriodStart(time.Now()))
+	if err != nil {
+		return err
+	}
+	var spent int64
+	for _, op := range usage {
+		spent += op.SpentGwei
+	}
+	if spent >= budget.MonthlyLimitGwei {
+		metrics.ObserveRelay(string(op), "budget_exhausted")
+		return domain.ErrOperatorBudgetExhausted
+	}
+	return nil
+}
+
+// takeNonce returns the operator's next nonce, asking the node when the
+// count is unknown. Callers hold mu.
+func (s *relayerService) takeNonce(ctx context.Context) (uint64, error) {
+	if s.nextNonce == nil {
+		nonce, err := s.client.PendingNonceAt(ctx, s.operatorAddress())
+		if err != nil {
+			return 0, fmt.Errorf("failed to get operator nonce: %w", err)
+		}
+		s.nextNonce = &nonce
+	}
+	return *s.nextNonce, nil
+}
+
+// record screens a request's content and adds the expression or
+// acknowledgement it creates to the app, returning the record's ID
+func (s *relayerService) record(ctx context.Context, user *domain.User, req *domain.RelayRequest) (string, error) {
+	id := primitive.NewObjectID()
+	content := relayContent(req)
+	now := time.Now()
+
+	switch req.Operation {
+	case domain.RelayCreateExpression:
+		if err := s.screen(ctx, domain.ContentTypeExpression, id, content); err != nil {
+			return "", err
+		}
+		expression := &domain.Expression{
+			ID:             id,
+			Creator:        user.ID.Hex(),
+			CreatorAddress: req.From,
+			Content:        content,
+			Status:         domain.ExpressionStatusPending,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := s.expressionRepo.Create(ctx, expression); err != nil {
+			return "", err
+		}
+
+	case domain.RelayAcknowledge:
+		expression, err := s.acknowledgedExpression(ctx, user, req)
+		if err != nil {
+			return "", err
+		}
+		if err := s.screen(ctx, domain.ContentTypeAcknowledgement, id, content); err != nil {
+			return "", err
+		}
+		acknowledgement := &domain.Acknowledgement{
+			ID:           id,
+			ExpressionID: expression.ID.Hex(),
+			Acknowledger: user.ID.Hex(),
+			Content:      content,
+			Status:       domain.AcknowledgementStatusActive,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := s.acknowledgementService.Create(ctx, acknowledgement); err != nil {
+			return "", err
+		}
+	}
+	return id.Hex(), nil
+}
+
+// acknowledgedExpression finds the app's record of the expression an
+// acknowledge request is for and checks that the request matches it
+func (s *relayerService) acknowledgedExpression(ctx context.Context, user *domain.User, req *domain.RelayRequest) (*domain.Expression, error) {
+	if req.ExpressionRecordID == "" {
+		return nil, domain.Validation("expressionRecordId is required to acknowledge")
+	}
+	expression, err := s.expressionRepo.FindByID(ctx, req.ExpressionRecordID)
+	if err != nil {
+		return nil, err
+	}
+	if expression == nil || !expression.ModerationStatus.IsPubliclyVisible() {
+		return nil, domain.NotFound("expression not found")
+	}
+	if !strings.EqualFold(expression.CreatorAddress, req.Creator) {
+		return nil, domain.Validation("creator does not match the expression's creator")
+	}
+	if expression.OnChainID > 0 && strconv.Itoa(expression.OnChainID) != req.ExpressionID {
+		return nil, domain.Validation("expression ID does not match the expression's on-chain ID")
+	}
+	if expression.Creator == user.ID.Hex() {
+		return nil, domain.Validation("cannot acknowledge your own expression")
+	}
+
+	acknowledgements, err := s.acknowledgementService.ListByExpression(ctx, expression.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	for _, acknowledgement := range acknowledgements {
+		if acknowledgement.Acknowledger == user.ID.Hex() {
+			return nil, domain.Conflict("expression is already acknowledged")
+		}
+	}
+	return expression, nil
+}
+
+// screen runs the configured screener over relayed content. Flagged content
+// is refused outright rather than held for review, as a request cannot be
+// unsent once a moderator has looked at it.
+func (s *relayerService) screen(ctx context.Context, contentType domain.ContentType, id primitive.ObjectID, content map[string]string) error {
+	if s.screener == nil {
+		return nil
+	}
+
+	media := make(map[string]string)
+	for mediaType, value := range content {
+		if mediaType != "text" && mediaType != "message" {
+			media[mediaType] = value
+		}
+	}
+	text := content["text"]
+	if message := content["message"]; message != "" {
+		text = strings.TrimSpace(message + "\n" + text)
+	}
+
+	result, err := s.screener.Screen(ctx, &domain.ScreeningInput{
+		ContentType: contentType,
+		ContentID:   id.Hex(),
+		Text:        text,
+		Media:       media,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to screen relayed content: %w", err)
+	}
+	if result.Flagged {
+		slog.InfoContext(ctx, "refused relay request flagged by screening", "content_type", contentType, "screener", result.Screener, "reason", result.Reason)
+		return domain.ErrRelayContentFlagged
+	}
+	return nil
+}
+
+// discardRecord removes the expression of a request that could not be
+// queued. Acknowledgements cannot be deleted and are left in place.
+func (s *relayerService) discardRecord(ctx context.Context, op domain.RelayOperation, recordID string) {
+	if op != domain.RelayCreateExpression {
+		slog.WarnContext(ctx, "relay request was not queued, its record stays in the app", "operation", op, "record_id", recordID)
+		return
+	}
+	if err := s.expressionRepo.Delete(ctx, recordID); err != nil {
+		slog.ErrorContext(ctx, "failed to remove expression of unqueued relay request", "expression_id", recordID, "error", err)
+	}
+}
+
+// linkOnChainID stores the on-chain ID of an expression created by a
+// confirmed request on its record in the app
+func (s *relayerService) linkOnChainID(ctx context.Context, tx *domain.RelayedTransaction, receipt *types.Receipt) {
+	if tx.Request.Operation != domain.RelayCreateExpression || tx.RecordID == "" {
+		return
+	}
+	onChainID, ok := chain.CreatedExpressionID(receipt, s.diamond)
+	if !ok || !onChainID.IsInt64() {
+		return
+	}
+	expression, err := s.expressionRepo.FindByID(ctx, tx.RecordID)
+	if err != nil || expression == nil {
+		slog.WarnContext(ctx, "failed to find expression of relayed transaction", "relay_id", tx.ID.Hex(), "expression_id", tx.RecordID, "error", err)
+		return
+	}
+	expression.OnChainID = int(onChainID.Int64())
+	expression.UpdatedAt = time.Now()
+	if err := s.expressionRepo.Update(ctx, expression); err != nil {
+		slog.ErrorContext(ctx, "failed to store on-chain expression ID", "expression_id", tx.RecordID, "error", err)
+	}
+}
+
+// relayContent is a request's content as the app stores it, leaving out
+// empty fields
+func relayContent(req *domain.RelayRequest) map[string]string {
+	content := make(map[string]string)
+	for key, value := range map[string]string{
+		"message": req.Message,
+		"text":    req.TextContent,
+		"audio":   req.AudioContent,
+		"video":   req.VideoContent,
+		"image":   req.ImageContent,
+	} {
+		if value != "" {
+			content[key] = value
+		}
+	}
+	return content
+}
+
+// txCalldata returns the call data of an operator call, or encodes the
+// user's signed request
+func txCalldata(tx *domain.RelayedTransaction) ([]byte, error) {
+	if tx.Calldata != "" {
+		return hexutil.Decode(tx.Calldata)
+	}
+	return relayCalldata(&tx.Request)
+}
+
+// noReceiptYet reports whether a receipt lookup failed only because the
+// transaction is not mined, or not known to the node
+func noReceiptYet(err error) bool {
+	return errors.Is(err, ethereum.NotFound) || (err != nil && strings.Contains(err.Error(), txIndexingMessage))
+}
+
+// feeGwei is what a mined transaction cost, rounded up to whole gwei
+func feeGwei(receipt *types.Receipt) int64 {
+	if receipt.EffectiveGasPrice == nil {
+		return 0
+	}
+	wei := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	gwei := big.NewInt(params.GWei)
+	wei.Add(wei, new(big.Int).Sub(gwei, big.NewInt(1)))
+	return wei.Div(wei, gwei).Int64()
+}
+
+// relayCalldata encodes the Diamond call carrying a user's signed request,
+// which the facets check again before crediting the signer
+func relayCalldata(req *domain.RelayRequest) ([]byte, error) {
+	var data []byte
+	var err error
+	switch req.Operation {
+	case domain.RelayCreateExpression:
+		data, err = chain.PackCreateExpressionFor(req)
+	case domain.RelayAcknowledge:
+		data, err = chain.PackAcknowledgeFor(req)
+	default:
+		return nil, domain.Validation("unknown relay operation %q", req.Operation)
+	}
+	if err != nil {
+		return nil, domain.Validation("invalid relay request: %v", err)
+	}
+	return data, nil
+}
+
+// bump raises a fee by relayFeeBumpPercent
+func bump(fee *big.Int) *big.Int {
+	raised := new(big.Int).Mul(fee, big.NewInt(100+relayFeeBumpPercent))
+	return raised.Div(raised, big.NewInt(100))
+}
+
+// bumpMin is the lowest fee a node accepts for a replacement, 10% more
+func bumpMin(fee *big.Int) *big.Int {
+	raised := new(big.Int).Mul(fee, big.NewInt(110))
+	return raised.Div(raised, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,673 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// artifactsDir is where hardhat writes the compiled contracts, from this package
+const artifactsDir = "../../../" + chain.DefaultArtifactsDir
+
+// memRelayedTransactions keeps relayed transactions in memory, copying them
+// in and out as the Mongo repository does
+type memRelayedTransactions struct {
+	mu  sync.Mutex
+	txs []domain.RelayedTransaction
+	// failUpdates is how many of the next updates fail, as if the database were down
+	failUpdates int
+}
+
+func copyRelayedTransaction(tx domain.RelayedTransaction) *domain.RelayedTransaction {
+	tx.Attempts = append([]domain.RelayAttempt(nil), tx.Attempts...)
+	return &tx
+}
+
+func (r *memRelayedTransactions) Create(ctx context.Context, tx *domain.RelayedTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.txs {
+		if existing.RequestKey == tx.RequestKey {
+			return domain.ErrRelayNonceUsed
+		}
+	}
+	tx.ID = primitive.NewObjectID()
+	tx.CreatedAt = time.Now()
+	r.txs = append(r.txs, *copyRelayedTransaction(*tx))
+	return nil
+}
+
+func (r *memRelayedTransactions) Update(ctx context.Context, tx *domain.RelayedTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failUpdates > 0 {
+		r.failUpdates--
+		return errors.New("database unavailable")
+	}
+	for i := range r.txs {
+		if r.txs[i].ID == tx.ID {
+			r.txs[i] = *copyRelayedTransaction(*tx)
+			return nil
+		}
+	}
+	return errors.New("relayed transaction not found")
+}
+
+func (r *memRelayedTransactions) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.RelayedTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range r.txs {
+		if tx.ID == id {
+			return copyRelayedTransaction(tx), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRelayedTransactions) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.RelayedTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var txs []*domain.RelayedTransaction
+	for _, tx := range r.txs {
+		if tx.UserID == userID {
+			txs = append(txs, copyRelayedTransaction(tx))
+		}
+	}
+	return txs, nil
+}
+
+func (r *memRelayedTransactions) FindByStatus(ctx context.Context, statuses ...domain.RelayStatus) ([]*domain.RelayedTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var txs []*domain.RelayedTransaction
+	for _, tx := range r.txs {
+		for _, status := range statuses {
+			if tx.Status == status {
+				txs = append(txs, copyRelayedTransaction(tx))
+			}
+		}
+	}
+	return txs, nil
+}
+
+func (r *memRelayedTransactions) NextNonce(ctx context.Context, from string) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var next uint64
+	for _, tx := range r.txs {
+		if tx.Request.From == from && tx.Request.Nonce >= next {
+			next = tx.Request.Nonce + 1
+		}
+	}
+	return next, nil
+}
+
+func (r *memRelayedTransactions) Usage(ctx context.Context, operator string, since time.Time) (map[domain.RelayOperation]domain.OperationUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := make(map[domain.RelayOperation]domain.OperationUsage)
+	for _, tx := range r.txs {
+		if tx.Operator != operator || tx.CreatedAt.Before(since) || tx.FeeGwei <= 0 {
+			continue
+		}
+		op := usage[tx.Request.Operation]
+		op.Transactions++
+		op.SpentGwei += tx.FeeGwei
+		usage[tx.Request.Operation] = op
+	}
+	return usage, nil
+}
+
+func (r *memRelayedTransactions) get(t *testing.T, id primitive.ObjectID) *domain.RelayedTransaction {
+	t.Helper()
+	tx, _ := r.FindByID(context.Background(), id)
+	if tx == nil {
+		t.Fatalf("relayed transaction %s not stored", id.Hex())
+	}
+	return tx
+}
+
+type memOperatorBudgets struct {
+	budgets map[string]*domain.OperatorBudget
+}
+
+func (r *memOperatorBudgets) Get(ctx context.Context, operator string) (*domain.OperatorBudget, error) {
+	return r.budgets[operator], nil
+}
+
+func (r *memOperatorBudgets) Set(ctx context.Context, budget *domain.OperatorBudget) error {
+	r.budgets[budget.Operator] = budget
+	return nil
+}
+
+func (r *memOperatorBudgets) Delete(ctx context.Context, operator string) error {
+	delete(r.budgets, operator)
+	return nil
+}
+
+func (r *memOperatorBudgets) List(ctx context.Context) ([]*domain.OperatorBudget, error) {
+	var budgets []*domain.OperatorBudget
+	for _, budget := range r.budgets {
+		budgets = append(budgets, budget)
+	}
+	return budgets, nil
+}
+
+// memExpressions implements the part of ExpressionRepository the relayer uses
+type memExpressions struct {
+	ports.ExpressionRepository
+	expressions map[string]*domain.Expression
+}
+
+func (r *memExpressions) Create(ctx context.Context, expression *domain.Expression) error {
+	r.expressions[expression.ID.Hex()] = expression
+	return nil
+}
+
+func (r *memExpressions) FindByID(ctx context.Context, id string) (*domain.Expression, error) {
+	return r.expressions[id], nil
+}
+
+func (r *memExpressions) Update(ctx context.Context, expression *domain.Expression) error {
+	r.expressions[expression.ID.Hex()] = expression
+	return nil
+}
+
+func (r *memExpressions) Delete(ctx context.Context, id string) error {
+	delete(r.expressions, id)
+	return nil
+}
+
+// memAcknowledgements implements the part of AcknowledgementService the relayer uses
+type memAcknowledgements struct {
+	ports.AcknowledgementService
+	acknowledgements []*domain.Acknowledgement
+}
+
+func (s *memAcknowledgements) Create(ctx context.Context, acknowledgement *domain.Acknowledgement) error {
+	s.acknowledgements = append(s.acknowledgements, acknowledgement)
+	return nil
+}
+
+func (s *memAcknowledgements) ListByExpression(ctx context.Context, expressionID string) ([]*domain.Acknowledgement, error) {
+	var acknowledgements []*domain.Acknowledgement
+	for _, acknowledgement := range s.acknowledgements {
+		if acknowledgement.ExpressionID == expressionID {
+			acknowledgements = append(acknowledgements, acknowledgement)
+		}
+	}
+	return acknowledgements, nil
+}
+
+// wordScreener flags text containing its word
+type wordScreener struct {
+	word string
+}
+
+func (s wordScreener) Name() string {
+	return "word"
+}
+
+func (s wordScreener) Screen(ctx context.Context, input *domain.ScreeningInput) (*domain.ScreeningResult, error) {
+	return &domain.ScreeningResult{Flagged: strings.Contains(input.Text, s.word), Screener: s.Name()}, nil
+}
+
+type relayerFixture struct {
+	sim              *chain.Simulated
+	relayer          *relayerService
+	txs              *memRelayedTransactions
+	budgets          *memOperatorBudgets
+	expressions      *memExpressions
+	acknowledgements *memAcknowledgements
+	operator         *ecdsa.PrivateKey
+}
+
+func testRelayPolicy() domain.RelayPolicy {
+	return domain.RelayPolicy{
+		MaxFeePerGas: big.NewInt(100 * params.GWei),
+		MaxGasLimit:  1_000_000,
+		StuckAfter:   time.Hour,
+		MaxAttempts:  3,
+	}
+}
+
+// newRelayerFixture starts a relayer on a simulated chain without contracts,
+// where calls to the Diamond are mined as plain transactions
+func newRelayerFixture(t *testing.T, policy domain.RelayPolicy) *relayerFixture {
+	operator, _ := crypto.GenerateKey()
+	sim := chain.NewSimulated(crypto.PubkeyToAddress(operator.PublicKey))
+	return startRelayerFixture(t, sim, operator, chain.SimulatedDiamondAddress, policy)
+}
+
+// newDiamondRelayerFixture starts a relayer on a simulated chain running the
+// Diamond, owned by the operator, from hardhat's compiled artifacts
+func newDiamondRelayerFixture(t *testing.T) *relayerFixture {
+	operator, _ := crypto.GenerateKey()
+	operatorAddress := crypto.PubkeyToAddress(operator.PublicKey)
+	sim, err := chain.NewSimulatedDiamond(artifactsDir, operatorAddress, operatorAddress)
+	if err != nil {
+		t.Skipf("contracts not compiled, run `npx hardhat compile` in scripts: %v", err)
+	}
+	return startRelayerFixture(t, sim, operator, chain.SimulatedDiamondAddress, testRelayPolicy())
+}
+
+func startRelayerFixture(t *testing.T, sim *chain.Simulated, operator *ecdsa.PrivateKey, diamond common.Address, policy domain.RelayPolicy) *relayerFixture {
+	t.Cleanup(func() { sim.Close() })
+	f := &relayerFixture{
+		sim:              sim,
+		txs:              &memRelayedTransactions{},
+		budgets:          &memOperatorBudgets{budgets: map[string]*domain.OperatorBudget{}},
+		expressions:      &memExpressions{expressions: map[string]*domain.Expression{}},
+		acknowledgements: &memAcknowledgements{},
+		operator:         operator,
+	}
+	f.relayer = NewRelayerService(f.txs, f.budgets, f.expressions, f.acknowledgements, wordScreener{word: "forbidden"},
+		sim.Client(), operator, chain.SimulatedChainID, diamond, policy).(*relayerService)
+	return f
+}
+
+// newWallet returns a key and a user subsidized for expressions and
+// acknowledgements with its address as their wallet
+func newWallet(t *testing.T) (*ecdsa.PrivateKey, *domain.User) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, &domain.User{
+		ID:            primitive.NewObjectID(),
+		Address:       crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		SubsidizedOps: []string{domain.SubsidyExpression, domain.SubsidyAcknowledgement},
+	}
+}
+
+// signedExpression returns a createExpression request from key, signed the
+// way wallets sign, with v as 27 or 28
+func (f *relayerFixture) signedExpression(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, text string) *domain.RelayRequest {
+	req := &domain.RelayRequest{
+		Operation:   domain.RelayCreateExpression,
+		From:        crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		TextContent: text,
+		Nonce:       nonce,
+		Deadline:    time.Now().Add(time.Hour).Unix(),
+	}
+	f.sign(t, key, req)
+	return req
+}
+
+func (f *relayerFixture) sign(t *testing.T, key *ecdsa.PrivateKey, req *domain.RelayRequest) {
+	t.Helper()
+	digest, err := chain.HashRelayRequest(f.relayer.Domain(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	req.Signature = hexutil.Encode(sig)
+}
+
+func (f *relayerFixture) submit(t *testing.T, user *domain.User, req *domain.RelayRequest) *domain.RelayedTransaction {
+	t.Helper()
+	tx, err := f.relayer.Submit(context.Background(), user, req)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	return tx
+}
+
+// mine seals a block and lets the relayer record what it contained
+func (f *relayerFixture) mine(t *testing.T) {
+	t.Helper()
+	f.sim.Commit()
+	if err := f.relayer.ProcessPending(context.Background()); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+}
+
+func TestRelayerConfirmsMinedTransaction(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	key, user := newWallet(t)
+
+	tx := f.submit(t, user, f.signedExpression(t, key, 0, "peace"))
+	if tx.Status != domain.RelayStatusSubmitted || len(tx.Attempts) != 1 {
+		t.Fatalf("got status %s with %d attempts, want submitted with 1", tx.Status, len(tx.Attempts))
+	}
+	expression := f.expressions.expressions[tx.RecordID]
+	if expression == nil || expression.Creator != user.ID.Hex() || expression.Content["text"] != "peace" {
+		t.Fatalf("relayed expression not recorded in the app: %+v", expression)
+	}
+
+	f.mine(t)
+	stored := f.txs.get(t, tx.ID)
+	if stored.Status != domain.RelayStatusConfirmed {
+		t.Fatalf("got status %s, want confirmed", stored.Status)
+	}
+	if stored.BlockNumber == 0 || stored.GasUsed == 0 || stored.FeeGwei == 0 {
+		t.Errorf("receipt not recorded: block %d, gas %d, fee %d gwei", stored.BlockNumber, stored.GasUsed, stored.FeeGwei)
+	}
+	if stored.TxHash != stored.Attempts[0].TxHash {
+		t.Errorf("got tx hash %s, want %s", stored.TxHash, stored.Attempts[0].TxHash)
+	}
+}
+
+func TestRelayerOrdersOperatorNonces(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	alice, aliceUser := newWallet(t)
+	bob, bobUser := newWallet(t)
+
+	txs := []*domain.RelayedTransaction{
+		f.submit(t, aliceUser, f.signedExpression(t, alice, 0, "first")),
+		f.submit(t, bobUser, f.signedExpression(t, bob, 0, "second")),
+		f.submit(t, aliceUser, f.signedExpression(t, alice, 1, "third")),
+	}
+	for i, tx := range txs {
+		if tx.OperatorNonce != uint64(i) {
+			t.Errorf("request %d got operator nonce %d, want %d", i, tx.OperatorNonce, i)
+		}
+	}
+
+	f.mine(t)
+	for i, tx := range txs {
+		if status := f.txs.get(t, tx.ID).Status; status != domain.RelayStatusConfirmed {
+			t.Errorf("request %d got status %s, want confirmed", i, status)
+		}
+	}
+}
+
+func TestRelayerDoesNotSendUnrecordedAttempts(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	key, user := newWallet(t)
+	operator := crypto.PubkeyToAddress(f.operator.PublicKey)
+
+	// The attempt cannot be saved, so it must not reach the network either
+	f.txs.failUpdates = 1
+	tx := f.submit(t, user, f.signedExpression(t, key, 0, "peace"))
+	if stored := f.txs.get(t, tx.ID); stored.Status != domain.RelayStatusPending || len(stored.Attempts) != 0 {
+		t.Fatalf("got status %s with %d attempts, want pending with none", stored.Status, len(stored.Attempts))
+	}
+	if nonce, err := f.sim.Client().PendingNonceAt(context.Background(), operator); err != nil || nonce != 0 {
+		t.Fatalf("operator sent %d transactions (err %v), want none", nonce, err)
+	}
+
+	// The retry sends it once, under the nonce the first try would have used
+	f.mine(t)
+	f.mine(t)
+	stored := f.txs.get(t, tx.ID)
+	if stored.Status != domain.RelayStatusConfirmed || stored.OperatorNonce != 0 || len(stored.Attempts) != 1 {
+		t.Fatalf("got status %s, nonce %d, %d attempts; want confirmed, 0, 1", stored.Status, stored.OperatorNonce, len(stored.Attempts))
+	}
+	if nonce, err := f.sim.Client().PendingNonceAt(context.Background(), operator); err != nil || nonce != 1 {
+		t.Fatalf("operator sent %d transactions (err %v), want 1", nonce, err)
+	}
+}
+
+func TestRelayerRejectsReplayedAndSkippedNonces(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	key, user := newWallet(t)
+
+	req := f.signedExpression(t, key, 0, "once")
+	f.submit(t, user, req)
+
+	replay := *req
+	if _, err := f.relayer.Submit(context.Background(), user, &replay); !errors.Is(err, domain.ErrRelayNonceUsed) {
+		t.Errorf("replayed request: got %v, want ErrRelayNonceUsed", err)
+	}
+	if _, err := f.relayer.Submit(context.Background(), user, f.signedExpression(t, key, 2, "skipped")); !errors.Is(err, domain.ErrRelayNonceUsed) {
+		t.Errorf("skipped nonce: got %v, want ErrRelayNonceUsed", err)
+	}
+
+	forged := f.signedExpression(t, key, 1, "forged")
+	forged.TextContent = "changed after signing"
+	if _, err := f.relayer.Submit(context.Background(), user, forged); !errors.Is(err, domain.ErrRelaySignatureInvalid) {
+		t.Errorf("altered request: got %v, want ErrRelaySignatureInvalid", err)
+	}
+
+	if len(f.txs.txs) != 1 || len(f.expressions.expressions) != 1 {
+		t.Errorf("rejected requests were stored: %d transactions, %d expressions", len(f.txs.txs), len(f.expressions.expressions))
+	}
+}
+
+func TestRelayerHoldsRequestsAboveFeeCap(t *testing.T) {
+	policy := testRelayPolicy()
+	// Below the simulated chain's base fee of 1 gwei
+	policy.MaxFeePerGas = big.NewInt(params.GWei / 2)
+	f := newRelayerFixture(t, policy)
+	key, user := newWallet(t)
+
+	tx := f.submit(t, user, f.signedExpression(t, key, 0, "later"))
+	if tx.Status != domain.RelayStatusPending || len(tx.Attempts) != 0 {
+		t.Fatalf("got status %s with %d attempts, want pending with none", tx.Status, len(tx.Attempts))
+	}
+
+	// Once the cap allows it the request is sent, paying no more than the cap
+	f.relayer.policy.MaxFeePerGas = big.NewInt(2 * params.GWei)
+	if err := f.relayer.ProcessPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	stored := f.txs.get(t, tx.ID)
+	if stored.Status != domain.RelayStatusSubmitted {
+		t.Fatalf("got status %s, want submitted", stored.Status)
+	}
+	feeCap, _ := new(big.Int).SetString(stored.Attempts[0].GasFeeCap, 10)
+	if feeCap.Cmp(f.relayer.policy.MaxFeePerGas) > 0 {
+		t.Errorf("fee cap %s is above the relayer's cap %s", feeCap, f.relayer.policy.MaxFeePerGas)
+	}
+
+	f.mine(t)
+	if status := f.txs.get(t, tx.ID).Status; status != domain.RelayStatusConfirmed {
+		t.Errorf("got status %s, want confirmed", status)
+	}
+}
+
+func TestRelayerEnforcesBudget(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	key, user := newWallet(t)
+
+	f.submit(t, user, f.signedExpression(t, key, 0, "paid for"))
+	f.mine(t)
+	operator := f.relayer.operatorAddress().Hex()
+	f.budgets.budgets[operator] = &domain.OperatorBudget{Operator: operator, MonthlyLimitGwei: 1}
+
+	if _, err := f.relayer.Submit(context.Background(), user, f.signedExpression(t, key, 1, "over budget")); !errors.Is(err, domain.ErrOperatorBudgetExhausted) {
+		t.Errorf("user request: got %v, want ErrOperatorBudgetExhausted", err)
+	}
+	data, err := chain.PackSetOperatorStatus(f.relayer.operatorAddress(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.relayer.SubmitOperatorCall(context.Background(), domain.RelaySetOperatorStatus, data); !errors.Is(err, domain.ErrOperatorBudgetExhausted) {
+		t.Errorf("operator call: got %v, want ErrOperatorBudgetExhausted", err)
+	}
+	if len(f.txs.txs) != 1 {
+		t.Errorf("got %d transactions, want only the one within budget", len(f.txs.txs))
+	}
+}
+
+func TestRelayerReplacesStuckTransaction(t *testing.T) {
+	policy := testRelayPolicy()
+	policy.StuckAfter = 0
+	policy.MaxAttempts = 2
+	f := newRelayerFixture(t, policy)
+	key, user := newWallet(t)
+
+	tx := f.submit(t, user, f.signedExpression(t, key, 0, "stuck"))
+
+	// The node forgets the transaction, as one that is never mined
+	f.sim.Rollback()
+	if err := f.relayer.ProcessPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	stored := f.txs.get(t, tx.ID)
+	if len(stored.Attempts) != 2 {
+		t.Fatalf("got %d attempts, want a replacement", len(stored.Attempts))
+	}
+	first, second := stored.Attempts[0], stored.Attempts[1]
+	firstCap, _ := new(big.Int).SetString(first.GasFeeCap, 10)
+	secondCap, _ := new(big.Int).SetString(second.GasFeeCap, 10)
+	if secondCap.Cmp(bumpMin(firstCap)) < 0 {
+		t.Errorf("replacement fee cap %s is not 10%% above %s", secondCap, firstCap)
+	}
+	if stored.OperatorNonce != tx.OperatorNonce {
+		t.Errorf("replacement changed the operator nonce from %d to %d", tx.OperatorNonce, stored.OperatorNonce)
+	}
+
+	// Out of attempts, a transaction stuck again is left alone
+	f.sim.Rollback()
+	if err := f.relayer.ProcessPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if attempts := len(f.txs.get(t, tx.ID).Attempts); attempts != 2 {
+		t.Fatalf("got %d attempts, want no more than MaxAttempts", attempts)
+	}
+
+	// The replacement that reaches a block is the one recorded
+	f.relayer.policy.MaxAttempts = 3
+	if err := f.relayer.ProcessPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	f.mine(t)
+	stored = f.txs.get(t, tx.ID)
+	if stored.Status != domain.RelayStatusConfirmed {
+		t.Fatalf("got status %s, want confirmed", stored.Status)
+	}
+	if stored.TxHash != stored.Attempts[len(stored.Attempts)-1].TxHash {
+		t.Errorf("got tx hash %s, want the last replacement's", stored.TxHash)
+	}
+}
+
+func TestRelayerRefusesFlaggedContent(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	key, user := newWallet(t)
+
+	_, err := f.relayer.Submit(context.Background(), user, f.signedExpression(t, key, 0, "something forbidden"))
+	if !errors.Is(err, domain.ErrRelayContentFlagged) {
+		t.Fatalf("got %v, want ErrRelayContentFlagged", err)
+	}
+	if len(f.txs.txs) != 0 || len(f.expressions.expressions) != 0 {
+		t.Errorf("flagged request was stored: %d transactions, %d expressions", len(f.txs.txs), len(f.expressions.expressions))
+	}
+}
+
+func TestRelayerLinksAcknowledgementToExpression(t *testing.T) {
+	f := newRelayerFixture(t, testRelayPolicy())
+	creatorKey, creator := newWallet(t)
+	key, user := newWallet(t)
+
+	created := f.submit(t, creator, f.signedExpression(t, creatorKey, 0, "peace"))
+	f.mine(t)
+
+	req := &domain.RelayRequest{
+		Operation:          domain.RelayAcknowledge,
+		From:               user.Address,
+		ExpressionID:       "0",
+		Creator:            creator.Address,
+		ExpressionRecordID: created.RecordID,
+		TextContent:        "heard",
+		Deadline:           time.Now().Add(time.Hour).Unix(),
+	}
+	f.sign(t, key, req)
+	tx := f.submit(t, user, req)
+
+	if len(f.acknowledgements.acknowledgements) != 1 {
+		t.Fatalf("got %d acknowledgements, want 1", len(f.acknowledgements.acknowledgements))
+	}
+	acknowledgement := f.acknowledgements.acknowledgements[0]
+	if acknowledgement.ID.Hex() != tx.RecordID || acknowledgement.ExpressionID != created.RecordID || acknowledgement.Acknowledger != user.ID.Hex() {
+		t.Errorf("acknowledgement not linked: %+v", acknowledgement)
+	}
+
+	// The creator named in the request must be the expression's
+	wrong := *req
+	wrong.Creator = user.Address
+	wrong.Nonce = 1
+	f.sign(t, key, &wrong)
+	if _, err := f.relayer.Submit(context.Background(), user, &wrong); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("mismatched creator: got %v, want a validation error", err)
+	}
+}
+
+func TestRelayedCallsCreditTheSigner(t *testing.T) {
+	f := newDiamondRelayerFixture(t)
+	ctx := context.Background()
+	operatorAddress := crypto.PubkeyToAddress(f.operator.PublicKey)
+	key, user := newWallet(t)
+	userAddress := common.HexToAddress(user.Address)
+	unsubsidizedKey, unsubsidized := newWallet(t)
+
+	// The Diamond's owner switches the operator on and subsidizes the user
+	activate, err := chain.PackSetOperatorStatus(operatorAddress, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subsidize, err := chain.PackSetOperatorSubsidies([]common.Address{userAddress}, []uint8{1}, []bool{true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range []struct {
+		op   domain.RelayOperation
+		data []byte
+	}{{domain.RelaySetOperatorStatus, activate}, {domain.RelaySetOperatorSubsidies, subsidize}} {
+		if _, err := f.relayer.SubmitOperatorCall(ctx, call.op, call.data); err != nil {
+			t.Fatal(err)
+		}
+		f.mine(t)
+	}
+
+	req := f.signedExpression(t, key, 0, "peace")
+	tx := f.submit(t, user, req)
+	f.mine(t)
+	if status := f.txs.get(t, tx.ID).Status; status != domain.RelayStatusConfirmed {
+		t.Fatalf("got status %s, want confirmed", status)
+	}
+
+	ids, err := chain.ExpressionsByCreator(ctx, f.sim.Client(), chain.SimulatedDiamondAddress, userAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("signer has %d expressions on-chain, want 1", len(ids))
+	}
+	ids, err = chain.ExpressionsByCreator(ctx, f.sim.Client(), chain.SimulatedDiamondAddress, operatorAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("operator has %d expressions on-chain, want none", len(ids))
+	}
+
+	// The facet refuses the same signed request twice
+	data, err := relayCalldata(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := f.relayer.SubmitOperatorCall(ctx, domain.RelayCreateExpression, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Status != domain.RelayStatusFailed {
+		t.Errorf("replayed request got status %s, want failed", replayed.Status)
+	}
+
+	// Subsidized in the app but not on-chain, the call reverts before it is sent
+	tx = f.submit(t, unsubsidized, f.signedExpression(t, unsubsidizedKey, 0, "unpaid"))
+	if tx.Status != domain.RelayStatusFailed {
+		t.Errorf("unsubsidized request got status %s, want failed", tx.Status)
+	}
+}
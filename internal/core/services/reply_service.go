@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/core/storage"
+	"proofofpeacemaking/internal/tracing"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type replyService struct {
+	replyRepo           ports.ReplyRepository
+	pairingRepo         ports.ExpressionAcknowledgementRepository
+	acknowledgementRepo ports.AcknowledgementRepository
+	storage             storage.Storage
+	screener            ports.ContentScreener
+	moderationService   ports.ModerationService
+	notificationService ports.NotificationService
+}
+
+func NewReplyService(
+	replyRepo ports.ReplyRepository,
+	pairingRepo ports.ExpressionAcknowledgementRepository,
+	acknowledgementRepo ports.AcknowledgementRepository,
+	storage storage.Storage,
+	screener ports.ContentScreener,
+	moderationService ports.ModerationService,
+	notificationService ports.NotificationService,
+) ports.ReplyService {
+	return &replyService{
+		replyRepo:           replyRepo,
+		pairingRepo:         pairingRepo,
+		acknowledgementRepo: acknowledgementRepo,
+		storage:             storage,
+		screener:            screener,
+		moderationService:   moderationService,
+		notificationService: notificationService,
+	}
+}
+
+func (s *replyService) Create(ctx context.Context, author *domain.User, reply *domain.Reply) error {
+	ctx, span := tracing.Start(ctx, "ReplyService.Create")
+	defer span.End()
+
+	pairing, err := s.dialogue(ctx, author, reply.AcknowledgementID)
+	if err != nil {
+		return err
+	}
+	if reply.ParentID != "" {
+		parent, err := s.replyRepo.FindByID(ctx, reply.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent == nil || parent.AcknowledgementID != reply.AcknowledgementID || parent.ModerationStatus == domain.ModerationStatusHidden {
+			return domain.ErrReplyParentNotFound
+		}
+	}
+
+	if reply.Content == nil {
+		reply.Content = make(map[string]string)
+	}
+	text := strings.TrimSpace(reply.Content["text"])
+	if len([]rune(text)) > domain.ReplyTextMaxLength {
+		return domain.Validation("a reply must be at most %d characters", domain.ReplyTextMaxLength)
+	}
+	if text == "" && len(reply.MediaContent) == 0 {
+		return domain.ErrReplyEmpty
+	}
+	delete(reply.Content, "text")
+	if text != "" {
+		reply.Content["text"] = text
+	}
+
+	if reply.ID.IsZero() {
+		reply.ID = primitive.NewObjectID()
+	}
+	reply.ExpressionID = pairing.ExpressionID
+	reply.AuthorID = author.ID.Hex()
+
+	for _, mediaType := range domain.ReplyMediaTypes {
+		media, ok := reply.MediaContent[mediaType]
+		if !ok {
+			continue
+		}
+		key, err := s.uploadMedia(ctx, reply.ID.Hex(), mediaType, media)
+		if err != nil {
+			tracing.Fail(span, err)
+			return err
+		}
+		reply.Content[mediaType] = key
+	}
+	reply.MediaContent = nil
+
+	// Screen the reply before it is persisted; flagged replies are held back for review
+	result, err := s.screen(ctx, reply)
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	if result.Flagged {
+		reply.ModerationStatus = domain.ModerationStatusPendingReview
+	}
+
+	if err := s.replyRepo.Create(ctx, reply); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to create reply: %w", err)
+	}
+
+	if result.Flagged {
+		if _, err := s.moderationService.Flag(ctx, domain.ContentTypeReply, reply.ID.Hex(), reply.AuthorID, result); err != nil {
+			// The reply stays hidden, so a missing case only delays publication
+			slog.ErrorContext(ctx, "failed to open moderation case", "reply_id", reply.ID.Hex(), "error", err)
+		}
+		return nil
+	}
+	if err := s.notificationService.NotifyNewReply(ctx, reply, pairing.OtherParty(reply.AuthorID)); err != nil {
+		slog.ErrorContext(ctx, "failed to notify of new reply", "reply_id", reply.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+func (s *replyService) List(ctx context.Context, user *domain.User, acknowledgementID string, cursor string, limit int) (*domain.ReplyPage, error) {
+	ctx, span := tracing.Start(ctx, "ReplyService.List")
+	defer span.End()
+
+	if _, err := s.dialogue(ctx, user, acknowledgementID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = domain.ReplyPageSize
+	}
+	limit = min(limit, domain.ReplyPageMaxSize)
+
+	// One extra reply tells whether another page follows
+	replies, err := s.replyRepo.FindByAcknowledgement(ctx, acknowledgementID, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.ReplyPage{Replies: []*domain.Reply{}}
+	if len(replies) > limit {
+		replies = replies[:limit]
+		page.NextCursor = replies[limit-1].ID.Hex()
+	}
+
+	userID := user.ID.Hex()
+	for _, reply := range replies {
+		// Authors still see their own replies while they wait for review
+		held := reply.ModerationStatus == domain.ModerationStatusPendingReview && reply.AuthorID == userID
+		if !reply.ModerationStatus.IsPubliclyVisible() && !held {
+			continue
+		}
+		if err := s.addPresignedURLs(ctx, reply); err != nil {
+			tracing.Fail(span, err)
+			return nil, err
+		}
+		page.Replies = append(page.Replies, reply)
+	}
+	return page, nil
+}
+
+// dialogue returns the pairing of a visible acknowledgement that user is a party of
+func (s *replyService) dialogue(ctx context.Context, user *domain.User, acknowledgementID string) (*domain.ExpressionAcknowledgement, error) {
+	acknowledgement, err := s.acknowledgementRepo.FindByID(ctx, acknowledgementID)
+	if err != nil {
+		return nil, err
+	}
+	if acknowledgement == nil || !acknowledgement.ModerationStatus.IsPubliclyVisible() {
+		return nil, domain.NotFound("acknowledgement not found")
+	}
+
+	pairing, err := s.pairingRepo.FindByAcknowledgement(ctx, acknowledgementID)
+	if err != nil {
+		return nil, err
+	}
+	if pairing == nil {
+		return nil, domain.ErrPairingNotFound
+	}
+	if !pairing.HasParty(user.ID.Hex()) {
+		return nil, domain.ErrReplyNotParty
+	}
+	return pairing, nil
+}
+
+// uploadMedia stores a reply's media under replies/[replyID]/[mediaType][extension]
+func (s *replyService) uploadMedia(ctx context.Context, replyID string, mediaType string, media *domain.MediaContent) (string, error) {
+	key := fmt.Sprintf("replies/%s/%s%s", replyID, mediaType, filepath.Ext(media.Filename))
+	err := s.storage.UploadFile(ctx, key, media.Reader, storage.UploadOptions{
+		ContentType:  getContentType(media.Filename),
+		CacheControl: "public, max-age=31536000", // 1 year cache for media
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to upload media", "key", key, "error", err)
+		return "", fmt.Errorf("failed to upload media: %w", err)
+	}
+	return key, nil
+}
+
+// screen runs the configured screener over a reply's text and media
+func (s *replyService) screen(ctx context.Context, reply *domain.Reply) (*domain.ScreeningResult, error) {
+	if s.screener == nil {
+		return &domain.ScreeningResult{}, nil
+	}
+
+	media := make(map[string]string)
+	for mediaType, key := range reply.Content {
+		if mediaType != "text" {
+			media[mediaType] = key
+		}
+	}
+
+	result, err := s.screener.Screen(ctx, &domain.ScreeningInput{
+		ContentType: domain.ContentTypeReply,
+		ContentID:   reply.ID.Hex(),
+		Text:        reply.Content["text"],
+		Media:       media,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen reply: %w", err)
+	}
+	return result, nil
+}
+
+func (s *replyService) addPresignedURLs(ctx context.Context, reply *domain.Reply) error {
+	for _, mediaType := range domain.ReplyMediaTypes {
+		if key, exists := reply.Content[mediaType]; exists {
+			url, err := s.storage.GetPresignedURL(ctx, key, time.Hour)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to generate presigned URL", "key", key, "error", err)
+				return fmt.Errorf("failed to generate presigned URL for %s: %w", mediaType, err)
+			}
+			reply.Content[mediaType] = url
+		}
+	}
+	return nil
+}
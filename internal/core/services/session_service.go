@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type sessionService struct {
@@ -21,6 +24,9 @@ func NewSessionService(sessionRepo ports.SessionRepository) ports.SessionService
 }
 
 func (s *sessionService) Create(ctx context.Context, session *domain.Session) error {
+	ctx, span := tracing.Start(ctx, "SessionService.Create")
+	defer span.End()
+
 	// Generate a random token
 	token, err := generateToken()
 	if err != nil {
@@ -28,15 +34,24 @@ func (s *sessionService) Create(ctx context.Context, session *domain.Session) er
 	}
 
 	// Set session fields
+	client := domain.ClientInfoFromContext(ctx)
 	session.Token = token
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IPAddress
 	session.CreatedAt = time.Now()
 	session.UpdatedAt = time.Now()
-	session.ExpiresAt = time.Now().Add(24 * time.Hour) // 24-hour expiry
+	session.LastSeenAt = time.Now()
+	if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = time.Now().Add(sessionLifetime)
+	}
 
 	return s.sessionRepo.Create(ctx, session)
 }
 
 func (s *sessionService) GetSession(ctx context.Context, token string) (*domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.GetSession")
+	defer span.End()
+
 	session, err := s.sessionRepo.FindByToken(ctx, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get session: %w", err)
@@ -54,14 +69,70 @@ func (s *sessionService) GetSession(ctx context.Context, token string) (*domain.
 }
 
 func (s *sessionService) Update(ctx context.Context, session *domain.Session) error {
+	ctx, span := tracing.Start(ctx, "SessionService.Update")
+	defer span.End()
+
 	session.UpdatedAt = time.Now()
 	return s.sessionRepo.Update(ctx, session)
 }
 
 func (s *sessionService) Delete(ctx context.Context, token string) error {
+	ctx, span := tracing.Start(ctx, "SessionService.Delete")
+	defer span.End()
+
 	return s.sessionRepo.DeleteByToken(ctx, token)
 }
 
+// ListByUser returns the user's active login sessions, most recently used first
+func (s *sessionService) ListByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.ListByUser")
+	defer span.End()
+
+	sessions, err := s.sessionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Revoke ends one of the user's sessions, signing that device out
+func (s *sessionService) Revoke(ctx context.Context, userID string, sessionID string) error {
+	ctx, span := tracing.Start(ctx, "SessionService.Revoke")
+	defer span.End()
+
+	id, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return domain.WrapError(domain.ErrValidation, err, "invalid session ID format")
+	}
+
+	sessions, err := s.sessionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, session := range sessions {
+		if session.ID == id {
+			return s.sessionRepo.DeleteByID(ctx, id)
+		}
+	}
+
+	return domain.ErrSessionNotFound
+}
+
+// RevokeAll ends every session the user has, signing all of their devices out
+func (s *sessionService) RevokeAll(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "SessionService.RevokeAll")
+	defer span.End()
+
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.WrapError(domain.ErrValidation, err, "invalid user ID format")
+	}
+	if err := s.sessionRepo.DeleteByUserID(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
 // generateToken generates a random token for session identification
 func generateToken() (string, error) {
 	b := make([]byte, 32)
@@ -2,9 +2,10 @@ package services
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 )
 
 type statisticsService struct {
@@ -27,53 +28,50 @@ func NewStatisticsService(
 
 // GetLatestStats returns the most recent statistics
 func (s *statisticsService) GetLatestStats(ctx context.Context) (*domain.Statistics, error) {
-	log.Printf("[STATISTICS_SERVICE] Getting latest statistics")
+	ctx, span := tracing.Start(ctx, "StatisticsService.GetLatestStats")
+	defer span.End()
+
 	stats, err := s.statsRepo.GetLatest(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting latest statistics: %v", err)
+		slog.ErrorContext(ctx, "failed to get latest statistics", "error", err)
 		return nil, err
 	}
-	if stats != nil {
-		log.Printf("[STATISTICS_SERVICE] Retrieved statistics: Users=%d, Expressions=%d, Acks=%d",
-			stats.TotalUsers, stats.TotalExpressions, stats.TotalAcknowledgements)
-	} else {
-		log.Printf("[STATISTICS_SERVICE] No statistics found, will return nil")
+	if stats == nil {
+		slog.DebugContext(ctx, "no statistics recorded yet")
 	}
 	return stats, nil
 }
 
 // UpdateStats creates a new statistics record
 func (s *statisticsService) UpdateStats(ctx context.Context) error {
-	log.Printf("[STATISTICS_SERVICE] Starting statistics update")
+	ctx, span := tracing.Start(ctx, "StatisticsService.UpdateStats")
+	defer span.End()
 
 	// Get total users
 	totalUsers, err := s.userRepo.GetTotalCount(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting total users: %v", err)
+		slog.ErrorContext(ctx, "failed to count users", "error", err)
 		return err
 	}
-	log.Printf("[STATISTICS_SERVICE] Total users: %d", totalUsers)
 
 	// Get total expressions
 	totalExpressions, err := s.expressionRepo.GetTotalCount(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting total expressions: %v", err)
+		slog.ErrorContext(ctx, "failed to count expressions", "error", err)
 		return err
 	}
-	log.Printf("[STATISTICS_SERVICE] Total expressions: %d", totalExpressions)
 
 	// Get total acknowledgements
 	totalAcknowledgements, err := s.expressionRepo.GetTotalAcknowledgements(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting total acknowledgements: %v", err)
+		slog.ErrorContext(ctx, "failed to count acknowledgements", "error", err)
 		return err
 	}
-	log.Printf("[STATISTICS_SERVICE] Total acknowledgements: %d", totalAcknowledgements)
 
 	// Get citizenship distribution
 	citizenshipStats, err := s.userRepo.GetCitizenshipDistribution(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting citizenship distribution: %v", err)
+		slog.ErrorContext(ctx, "failed to get citizenship distribution", "error", err)
 		return err
 	}
 	if len(citizenshipStats) == 0 {
@@ -81,12 +79,11 @@ func (s *statisticsService) UpdateStats(ctx context.Context) error {
 			"UNKNOWN": totalUsers, // Default all users to unknown if no citizenship data
 		}
 	}
-	log.Printf("[STATISTICS_SERVICE] Citizenship stats: %v", citizenshipStats)
 
 	// Get media type distribution
 	mediaStats, err := s.expressionRepo.GetMediaTypeDistribution(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting media distribution: %v", err)
+		slog.ErrorContext(ctx, "failed to get media distribution", "error", err)
 		return err
 	}
 	if len(mediaStats) == 0 {
@@ -94,7 +91,6 @@ func (s *statisticsService) UpdateStats(ctx context.Context) error {
 			"text": totalExpressions, // Default all expressions to text if no media type data
 		}
 	}
-	log.Printf("[STATISTICS_SERVICE] Media stats: %v", mediaStats)
 
 	// Create new statistics record
 	stats := &domain.Statistics{
@@ -105,39 +101,49 @@ func (s *statisticsService) UpdateStats(ctx context.Context) error {
 		MediaStats:            mediaStats,
 	}
 
-	log.Printf("[STATISTICS_SERVICE] Creating new statistics record")
 	err = s.statsRepo.Create(ctx, stats)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error creating statistics record: %v", err)
+		slog.ErrorContext(ctx, "failed to create statistics record", "error", err)
 		return err
 	}
-	log.Printf("[STATISTICS_SERVICE] Statistics update completed successfully")
+	slog.DebugContext(ctx, "statistics updated",
+		"users", totalUsers,
+		"expressions", totalExpressions,
+		"acknowledgements", totalAcknowledgements,
+	)
 	return nil
 }
 
 // GetCountryList returns available countries for citizenship
 func (s *statisticsService) GetCountryList(ctx context.Context) ([]domain.CountryInfo, error) {
-	log.Printf("[STATISTICS_SERVICE] Getting country list")
+	ctx, span := tracing.Start(ctx, "StatisticsService.GetCountryList")
+	defer span.End()
+
 	countries, err := s.statsRepo.GetCountryList(ctx)
 	if err != nil {
-		log.Printf("[STATISTICS_SERVICE] Error getting country list: %v", err)
+		slog.ErrorContext(ctx, "failed to get country list", "error", err)
 		return nil, err
 	}
-	log.Printf("[STATISTICS_SERVICE] Retrieved %d countries", len(countries))
 	return countries, nil
 }
 
 func (s *statisticsService) UpdateStatisticsAfterExpression(ctx context.Context) error {
-	log.Printf("[STATISTICS_SERVICE] Updating statistics after new expression")
+	ctx, span := tracing.Start(ctx, "StatisticsService.UpdateStatisticsAfterExpression")
+	defer span.End()
+
 	return s.UpdateStats(ctx)
 }
 
 func (s *statisticsService) UpdateStatisticsAfterAcknowledgement(ctx context.Context) error {
-	log.Printf("[STATISTICS_SERVICE] Updating statistics after new acknowledgement")
+	ctx, span := tracing.Start(ctx, "StatisticsService.UpdateStatisticsAfterAcknowledgement")
+	defer span.End()
+
 	return s.UpdateStats(ctx)
 }
 
 func (s *statisticsService) UpdateStatisticsAfterCitizenshipChange(ctx context.Context) error {
-	log.Printf("[STATISTICS_SERVICE] Updating statistics after citizenship change")
+	ctx, span := tracing.Start(ctx, "StatisticsService.UpdateStatisticsAfterCitizenshipChange")
+	defer span.End()
+
 	return s.UpdateStats(ctx)
 }
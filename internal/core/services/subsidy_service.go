@@ -0,0 +1,486 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// subsidyCursor names the chain cursor of the SubsidyStatusChanged reader
+	subsidyCursor = "subsidy_status_changed"
+	// subsidyLogChunk is how many blocks one eth_getLogs call covers; nodes
+	// reject or time out on much larger ranges
+	subsidyLogChunk = 2000
+	// subsidyChangeListLimit caps how many changes ListChanges returns
+	subsidyChangeListLimit = 200
+)
+
+type subsidyService struct {
+	userRepo   ports.UserRepository
+	changeRepo ports.SubsidyChangeRepository
+	budgetRepo ports.OperatorBudgetRepository
+	cursorRepo ports.ChainCursorRepository
+	txRepo     ports.RelayedTransactionRepository
+	relayer    ports.RelayerService
+	client     chain.Client
+	operator   common.Address
+	diamond    common.Address
+	startBlock uint64
+	batchSize  int
+}
+
+// NewSubsidyService sends subsidy changes through the relayer's operator
+// account and reads SubsidyStatusChanged events from the Diamond, starting
+// at startBlock on first run
+func NewSubsidyService(
+	userRepo ports.UserRepository,
+	changeRepo ports.SubsidyChangeRepository,
+	budgetRepo ports.OperatorBudgetRepository,
+	cursorRepo ports.ChainCursorRepository,
+	txRepo ports.RelayedTransactionRepository,
+	relayer ports.RelayerService,
+	client chain.Client,
+	operator common.Address,
+	diamond common.Address,
+	startBlock uint64,
+	batchSize int,
+) ports.SubsidyService {
+	return &subsidyService{
+		userRepo:   userRepo,
+		changeRepo: changeRepo,
+		budgetRepo: budgetRepo,
+		cursorRepo: cursorRepo,
+		txRepo:     txRepo,
+		relayer:    relayer,
+		client:     client,
+		operator:   operator,
+		diamond:    diamond,
+		startBlock: startBlock,
+		batchSize:  batchSize,
+	}
+}
+
+func (s *subsidyService) SetSubsidies(ctx context.Context, admin *domain.User, userID string, operations []string, granted bool) ([]*domain.SubsidyChange, error) {
+	ctx, span := tracing.Start(ctx, "SubsidyService.SetSubsidies")
+	defer span.End()
+
+	if len(operations) == 0 {
+		return nil, domain.ErrInvalidSubsidy
+	}
+	for _, op := range operations {
+		if _, ok := domain.SubsidyFlag(op); !ok {
+			return nil, domain.ErrInvalidSubsidy
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.NotFound("user not found")
+	}
+	// Subsidies are kept per address on-chain, so a user needs a wallet
+	if user.Address == "" {
+		return nil, domain.ErrSubsidyNeedsWallet
+	}
+	if !common.IsHexAddress(user.Address) {
+		return nil, domain.Validation("user's wallet address is invalid")
+	}
+
+	changes := []*domain.SubsidyChange{}
+	for _, op := range operations {
+		pending, err := s.changeRepo.FindUnconfirmed(ctx, user.ID, op)
+		if err != nil {
+			tracing.Fail(span, err)
+			return nil, err
+		}
+		current := user.IsSubsidized(op)
+		if len(pending) > 0 {
+			current = pending[len(pending)-1].Granted
+		}
+		if current == granted {
+			continue
+		}
+		// The Diamond only pays for what the operator has subsidized on-chain,
+		// so a grant takes effect once confirmed; a revocation stops relaying
+		// at once
+		if !granted {
+			if err := s.userRepo.SetSubsidy(ctx, user.ID, op, false); err != nil {
+				tracing.Fail(span, err)
+				return nil, err
+			}
+		}
+		change := &domain.SubsidyChange{
+			UserID:      user.ID,
+			Address:     common.HexToAddress(user.Address).Hex(),
+			Operation:   op,
+			Granted:     granted,
+			Status:      domain.SubsidyChangeQueued,
+			RequestedBy: admin.ID,
+		}
+		if err := s.changeRepo.Create(ctx, change); err != nil {
+			tracing.Fail(span, err)
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	slog.InfoContext(ctx, "subsidies changed", "user_id", user.ID.Hex(), "operations", operations, "granted", granted, "admin_id", admin.ID.Hex(), "queued", len(changes))
+	return changes, nil
+}
+
+func (s *subsidyService) ListChanges(ctx context.Context, status domain.SubsidyChangeStatus) ([]*domain.SubsidyChange, error) {
+	ctx, span := tracing.Start(ctx, "SubsidyService.ListChanges")
+	defer span.End()
+
+	switch status {
+	case "", domain.SubsidyChangeQueued, domain.SubsidyChangeSent, domain.SubsidyChangeConfirmed, domain.SubsidyChangeFailed:
+	default:
+		return nil, domain.Validation("status must be one of queued, sent, confirmed, failed")
+	}
+
+	changes, err := s.changeRepo.List(ctx, status, subsidyChangeListLimit)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []*domain.SubsidyChange{}
+	}
+	return changes, nil
+}
+
+func (s *subsidyService) SetBudget(ctx context.Context, admin *domain.User, operator string, monthlyLimitGwei int64) (*domain.OperatorBudget, error) {
+	ctx, span := tracing.Start(ctx, "SubsidyService.SetBudget")
+	defer span.End()
+
+	if !common.IsHexAddress(operator) {
+		return nil, domain.Validation("invalid operator address")
+	}
+	if monthlyLimitGwei < 0 {
+		return nil, domain.Validation("monthly limit must not be negative")
+	}
+
+	budget := &domain.OperatorBudget{
+		Operator:         common.HexToAddress(operator).Hex(),
+		MonthlyLimitGwei: monthlyLimitGwei,
+		UpdatedBy:        admin.ID,
+	}
+	if err := s.budgetRepo.Set(ctx, budget); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "operator budget set", "operator", budget.Operator, "monthly_limit_gwei", monthlyLimitGwei, "admin_id", admin.ID.Hex())
+	return budget, nil
+}
+
+func (s *subsidyService) RemoveBudget(ctx context.Context, operator string) error {
+	ctx, span := tracing.Start(ctx, "SubsidyService.RemoveBudget")
+	defer span.End()
+
+	if !common.IsHexAddress(operator) {
+		return domain.Validation("invalid operator address")
+	}
+	return s.budgetRepo.Delete(ctx, common.HexToAddress(operator).Hex())
+}
+
+func (s *subsidyService) Report(ctx context.Context) (*domain.SubsidyReport, error) {
+	ctx, span := tracing.Start(ctx, "SubsidyService.Report")
+	defer span.End()
+
+	budgets, err := s.budgetRepo.List(ctx)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	budgetsByOperator := make(map[string]*domain.OperatorBudget)
+	operators := []string{s.operator.Hex()}
+	for _, budget := range budgets {
+		budgetsByOperator[budget.Operator] = budget
+		if budget.Operator != s.operator.Hex() {
+			operators = append(operators, budget.Operator)
+		}
+	}
+
+	report := &domain.SubsidyReport{
+		PeriodStart: domain.BudgetPeriodStart(time.Now()),
+		Operators:   make([]domain.OperatorUsage, 0, len(operators)),
+	}
+	for _, operator := range operators {
+		byOperation, err := s.txRepo.Usage(ctx, operator, report.PeriodStart)
+		if err != nil {
+			tracing.Fail(span, err)
+			return nil, err
+		}
+		usage := domain.OperatorUsage{
+			Operator:    operator,
+			ByOperation: byOperation,
+			Budget:      budgetsByOperator[operator],
+		}
+		for _, op := range byOperation {
+			usage.Transactions += op.Transactions
+			usage.SpentGwei += op.SpentGwei
+		}
+		if usage.Budget != nil {
+			remaining := max(usage.Budget.MonthlyLimitGwei-usage.SpentGwei, 0)
+			usage.RemainingGwei = &remaining
+		}
+		report.Operators = append(report.Operators, usage)
+	}
+
+	if report.SubsidizedUsers, err = s.userRepo.GetSubsidyCounts(ctx); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	if report.PendingChanges, err = s.changeRepo.CountUnconfirmed(ctx); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *subsidyService) Sync(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "SubsidyService.Sync")
+	defer span.End()
+
+	if err := s.flush(ctx); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to send subsidy changes: %w", err)
+	}
+	// Events are read before settling, so a mined batch whose block has been
+	// read is known to have emitted all the events it will
+	next, err := s.reconcile(ctx)
+	if err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to read subsidy events: %w", err)
+	}
+	if err := s.settle(ctx, next); err != nil {
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to settle subsidy changes: %w", err)
+	}
+	return nil
+}
+
+func (s *subsidyService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Sync(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to sync subsidies", "error", err)
+			}
+		}
+	}
+}
+
+// flush sends the oldest queued changes in one setOperatorSubsidies call
+func (s *subsidyService) flush(ctx context.Context) error {
+	changes, err := s.changeRepo.FindByStatus(ctx, domain.SubsidyChangeQueued, s.batchSize)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	users := make([]common.Address, len(changes))
+	operations := make([]uint8, len(changes))
+	statuses := make([]bool, len(changes))
+	for i, change := range changes {
+		flag, _ := domain.SubsidyFlag(change.Operation)
+		users[i] = common.HexToAddress(change.Address)
+		operations[i] = flag
+		statuses[i] = change.Granted
+	}
+	data, err := chain.PackSetOperatorSubsidies(users, operations, statuses)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.relayer.SubmitOperatorCall(ctx, domain.RelaySetOperatorSubsidies, data)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		change.Status = domain.SubsidyChangeSent
+		change.RelayID = &tx.ID
+		if err := s.changeRepo.Update(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	slog.InfoContext(ctx, "sent subsidy changes", "relay_id", tx.ID.Hex(), "changes", len(changes))
+	return nil
+}
+
+// reconcile applies SubsidyStatusChanged events up to the latest block and
+// returns the next block to read
+func (s *subsidyService) reconcile(ctx context.Context) (uint64, error) {
+	next, found, err := s.cursorRepo.Get(ctx, subsidyCursor)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		next = s.startBlock
+	}
+	latest, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return next, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	for next <= latest {
+		to := min(next+subsidyLogChunk-1, latest)
+		logs, err := s.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(next),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{s.diamond},
+			Topics:    [][]common.Hash{{chain.SubsidyStatusChangedTopic}},
+		})
+		if err != nil {
+			return next, fmt.Errorf("failed to filter logs: %w", err)
+		}
+		for _, log := range logs {
+			if err := s.apply(ctx, log); err != nil {
+				return next, err
+			}
+		}
+
+		next = to + 1
+		if err := s.cursorRepo.Set(ctx, subsidyCursor, next); err != nil {
+			return next, err
+		}
+	}
+	return next, nil
+}
+
+// apply confirms the change an event answers. Events no change waits for,
+// such as calls made outside the platform, overwrite the user's subsidies.
+func (s *subsidyService) apply(ctx context.Context, log types.Log) error {
+	txHash := log.TxHash.Hex()
+	event, err := chain.ParseSubsidyStatusChanged(log)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping undecodable subsidy event", "tx_hash", txHash, "error", err)
+		return nil
+	}
+	// Subsidies are kept per operator; only ours are the platform's
+	if event.Operator != s.operator {
+		return nil
+	}
+	op := domain.SubsidyOperation(event.Operation)
+	if op == "" {
+		slog.WarnContext(ctx, "skipping subsidy event for unknown operation", "tx_hash", txHash, "operation", event.Operation)
+		return nil
+	}
+	user, err := s.userRepo.GetByAddressIgnoreCase(ctx, event.User.Hex())
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	unconfirmed, err := s.changeRepo.FindUnconfirmed(ctx, user.ID, op)
+	if err != nil {
+		return err
+	}
+	for i, change := range unconfirmed {
+		if change.Status != domain.SubsidyChangeSent || change.Granted != event.Status {
+			continue
+		}
+		change.Status = domain.SubsidyChangeConfirmed
+		change.TxHash = txHash
+		if err := s.changeRepo.Update(ctx, change); err != nil {
+			return err
+		}
+		unconfirmed = append(unconfirmed[:i], unconfirmed[i+1:]...)
+		break
+	}
+
+	// Later changes still on their way decide what the user should have
+	if len(unconfirmed) > 0 || user.IsSubsidized(op) == event.Status {
+		return nil
+	}
+	slog.InfoContext(ctx, "applying subsidy change from chain", "user_id", user.ID.Hex(), "operation", op, "granted", event.Status, "tx_hash", txHash)
+	return s.userRepo.SetSubsidy(ctx, user.ID, op, event.Status)
+}
+
+// settle finishes sent changes whose transaction is done. A failed batch
+// restores the users' subsidies; a mined one whose block has been read
+// without an event for a change left the chain as it was, so the change is
+// confirmed too.
+func (s *subsidyService) settle(ctx context.Context, next uint64) error {
+	changes, err := s.changeRepo.FindByStatus(ctx, domain.SubsidyChangeSent, 0)
+	if err != nil {
+		return err
+	}
+
+	txs := make(map[primitive.ObjectID]*domain.RelayedTransaction)
+	for _, change := range changes {
+		if change.RelayID == nil {
+			continue
+		}
+		tx, ok := txs[*change.RelayID]
+		if !ok {
+			if tx, err = s.txRepo.FindByID(ctx, *change.RelayID); err != nil {
+				return err
+			}
+			txs[*change.RelayID] = tx
+		}
+		if tx == nil {
+			continue
+		}
+
+		switch {
+		case tx.Status == domain.RelayStatusFailed:
+			if err := s.revert(ctx, change, tx.Error); err != nil {
+				return err
+			}
+		case tx.Status == domain.RelayStatusConfirmed && tx.BlockNumber < next:
+			change.Status = domain.SubsidyChangeConfirmed
+			change.TxHash = tx.TxHash
+			if err := s.changeRepo.Update(ctx, change); err != nil {
+				return err
+			}
+			if err := s.settleUser(ctx, change.UserID, change.Operation, change.Granted); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// revert marks a change failed and, unless a later change is on its way,
+// gives the user back what they had before it
+func (s *subsidyService) revert(ctx context.Context, change *domain.SubsidyChange, reason string) error {
+	change.Status = domain.SubsidyChangeFailed
+	change.Error = reason
+	if err := s.changeRepo.Update(ctx, change); err != nil {
+		return err
+	}
+
+	slog.WarnContext(ctx, "subsidy change failed on-chain, restoring previous subsidies", "user_id", change.UserID.Hex(), "operation", change.Operation, "error", reason)
+	return s.settleUser(ctx, change.UserID, change.Operation, !change.Granted)
+}
+
+// settleUser sets what the chain now holds for the user, unless a later
+// change is on its way
+func (s *subsidyService) settleUser(ctx context.Context, userID primitive.ObjectID, op string, granted bool) error {
+	unconfirmed, err := s.changeRepo.FindUnconfirmed(ctx, userID, op)
+	if err != nil || len(unconfirmed) > 0 {
+		return err
+	}
+	return s.userRepo.SetSubsidy(ctx, userID, op, granted)
+}
@@ -3,9 +3,9 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 
 	"strings"
 
@@ -26,10 +26,16 @@ func NewUserService(userRepo ports.UserRepository) ports.UserService {
 }
 
 func (s *userService) GetUserByAddress(ctx context.Context, address string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByAddress")
+	defer span.End()
+
 	return s.userRepo.GetByAddress(ctx, address)
 }
 
 func (s *userService) Create(ctx context.Context, user *domain.User) error {
+	ctx, span := tracing.Start(ctx, "UserService.Create")
+	defer span.End()
+
 	// Validate user fields
 	if err := s.validate.Struct(user); err != nil {
 		var validationErrors validator.ValidationErrors
@@ -44,38 +50,69 @@ func (s *userService) Create(ctx context.Context, user *domain.User) error {
 					errMsgs = append(errMsgs, "email must be a valid email address")
 				}
 			}
-			return fmt.Errorf("validation failed: %s", strings.Join(errMsgs, ", "))
+			return domain.Validation("%s", strings.Join(errMsgs, ", "))
 		}
-		return fmt.Errorf("validation failed: %w", err)
+		return domain.WrapError(domain.ErrValidation, err, "invalid user")
 	}
 
 	return s.userRepo.Create(ctx, user)
 }
 
 func (s *userService) Update(ctx context.Context, user *domain.User) error {
+	ctx, span := tracing.Start(ctx, "UserService.Update")
+	defer span.End()
+
 	return s.userRepo.Update(ctx, user)
 }
 
 func (s *userService) UpdateNonce(ctx context.Context, id primitive.ObjectID, nonce int) error {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateNonce")
+	defer span.End()
+
 	return s.userRepo.UpdateNonce(ctx, id, nonce)
 }
 
 func (s *userService) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByEmail")
+	defer span.End()
+
 	return s.userRepo.GetByEmail(ctx, email)
 }
 
 func (s *userService) ConnectWallet(ctx context.Context, userID primitive.ObjectID, address string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ConnectWallet")
+	defer span.End()
+
 	return s.userRepo.ConnectWallet(ctx, userID, address)
 }
 
+func (s *userService) SetRole(ctx context.Context, userID primitive.ObjectID, role domain.Role) error {
+	ctx, span := tracing.Start(ctx, "UserService.SetRole")
+	defer span.End()
+
+	if !domain.ValidRole(role) {
+		return domain.Validation("invalid role: %s", role)
+	}
+	return s.userRepo.SetRole(ctx, userID, role)
+}
+
 func (s *userService) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByUsername")
+	defer span.End()
+
 	return s.userRepo.GetByUsername(ctx, username)
 }
 
 func (s *userService) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByID")
+	defer span.End()
+
 	return s.userRepo.GetByID(ctx, id)
 }
 
 func (s *userService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "UserService.Delete")
+	defer span.End()
+
 	return s.userRepo.Delete(ctx, id)
 }
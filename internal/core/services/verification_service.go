@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errRecordNotFound is returned for IDs that name nothing public, including
+// malformed ones, hidden content and proofs that are not complete
+var errRecordNotFound = domain.NotFound("record not found")
+
+type verificationService struct {
+	proofSources
+	acknowledgementRepo ports.AcknowledgementRepository
+	userRepo            ports.UserRepository
+	anchorService       ports.AnchorService
+	client              chain.Client
+	diamond             common.Address
+}
+
+// NewVerificationService builds public verification pages. anchorService and
+// client are nil when the server runs without a chain, and the checks that
+// need them are reported as unavailable.
+func NewVerificationService(
+	proofNFTRepo ports.ProofNFTRepository,
+	proofRequestRepo ports.ProofRequestRepository,
+	expressionRepo ports.ExpressionRepository,
+	acknowledgementRepo ports.AcknowledgementRepository,
+	userRepo ports.UserRepository,
+	anchorService ports.AnchorService,
+	client chain.Client,
+	diamond common.Address,
+) ports.VerificationService {
+	return &verificationService{
+		proofSources: proofSources{
+			proofNFTRepo:     proofNFTRepo,
+			proofRequestRepo: proofRequestRepo,
+			expressionRepo:   expressionRepo,
+		},
+		acknowledgementRepo: acknowledgementRepo,
+		userRepo:            userRepo,
+		anchorService:       anchorService,
+		client:              client,
+		diamond:             diamond,
+	}
+}
+
+func (s *verificationService) GetExpression(ctx context.Context, id string) (*domain.PublicRecord, error) {
+	ctx, span := tracing.Start(ctx, "VerificationService.GetExpression")
+	defer span.End()
+
+	record, err := s.expressionRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errRecordNotFound
+	}
+	return record, nil
+}
+
+func (s *verificationService) GetRecord(ctx context.Context, id string) (*domain.PublicRecord, error) {
+	ctx, span := tracing.Start(ctx, "VerificationService.GetRecord")
+	defer span.End()
+
+	record, err := s.expressionRecord(ctx, id)
+	if err != nil || record != nil {
+		return record, err
+	}
+	for _, source := range []domain.CredentialSource{domain.CredentialSourceProofNFT, domain.CredentialSourceProofRequest} {
+		record, err := s.proofRecord(ctx, source, id)
+		if err != nil || record != nil {
+			return record, err
+		}
+	}
+	return nil, errRecordNotFound
+}
+
+// expressionRecord describes a publicly visible expression, or returns nil if
+// id names none
+func (s *verificationService) expressionRecord(ctx context.Context, id string) (*domain.PublicRecord, error) {
+	expression, err := s.expressionRepo.FindByID(ctx, id)
+	if errors.Is(err, domain.ErrValidation) {
+		return nil, errRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if expression == nil {
+		return nil, nil
+	}
+	public, err := s.publicExpression(ctx, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	nfts, err := s.proofNFTRepo.FindByExpression(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, nft := range nfts {
+		if nft.MintedAt != nil {
+			public.TokenIDs = append(public.TokenIDs, nft.TokenID)
+		}
+	}
+	sort.Ints(public.TokenIDs)
+
+	record := &domain.PublicRecord{Kind: domain.PublicRecordExpression, Expression: public}
+	record.Checks = append(record.Checks, expressionChecks(public)...)
+	record.Checks = append(record.Checks, s.anchorChecks(ctx, id)...)
+	record.Checks = append(record.Checks, popChecks([]domain.PublicParty{public.Creator})...)
+	return record, nil
+}
+
+// proofRecord describes a completed proof, or returns nil if id names no
+// record of source
+func (s *verificationService) proofRecord(ctx context.Context, source domain.CredentialSource, id string) (*domain.PublicRecord, error) {
+	proof, err := s.load(ctx, source, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if errors.Is(err, domain.ErrCredentialNotEligible) {
+		return nil, errRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expression, err := s.expressionRepo.FindByID(ctx, proof.expressionID)
+	if err != nil {
+		return nil, err
+	}
+	if expression == nil {
+		return nil, errRecordNotFound
+	}
+	public, err := s.publicExpression(ctx, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledger, err := s.party(ctx, proof.acknowledgerID, "acknowledger")
+	if err != nil {
+		return nil, err
+	}
+	publicProof := &domain.PublicProof{
+		ID:       id,
+		Source:   source,
+		Status:   "accepted",
+		TokenID:  proof.tokenID,
+		IPFSHash: proof.ipfsHash,
+		Parties:  []domain.PublicParty{public.Creator, *acknowledger},
+		Date:     proof.date,
+	}
+	if source == domain.CredentialSourceProofNFT {
+		publicProof.Status = "minted"
+	}
+
+	record := &domain.PublicRecord{Kind: domain.PublicRecordProof, Expression: public, Proof: publicProof}
+	if source == domain.CredentialSourceProofNFT {
+		record.Checks = append(record.Checks, domain.IntegrityCheck{
+			Name:   "Proof NFT minted",
+			Status: domain.IntegrityPassed,
+			Detail: fmt.Sprintf("Token #%d", proof.tokenID),
+		})
+	}
+	record.Checks = append(record.Checks, expressionChecks(public)...)
+	record.Checks = append(record.Checks, s.anchorChecks(ctx, public.ID)...)
+	record.Checks = append(record.Checks, popChecks(publicProof.Parties)...)
+	return record, nil
+}
+
+// publicExpression is the part of an expression shown publicly, or
+// errRecordNotFound if moderators have hidden it
+func (s *verificationService) publicExpression(ctx context.Context, expression *domain.Expression) (*domain.PublicExpression, error) {
+	if !expression.ModerationStatus.IsPubliclyVisible() {
+		return nil, errRecordNotFound
+	}
+	contentHash, err := chain.ContentHash(expression.Content)
+	if err != nil {
+		return nil, err
+	}
+	creator, err := s.party(ctx, expression.Creator, "creator")
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledgements, err := s.acknowledgementRepo.FindByExpression(ctx, expression.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	count := 0
+	for _, acknowledgement := range acknowledgements {
+		if acknowledgement.Status == domain.AcknowledgementStatusActive && acknowledgement.ModerationStatus.IsPubliclyVisible() {
+			count++
+		}
+	}
+
+	return &domain.PublicExpression{
+		ID:                   expression.ID.Hex(),
+		Text:                 expression.Content["text"],
+		Creator:              *creator,
+		IPFSHash:             expression.IPFSHash,
+		OnChainID:            expression.OnChainID,
+		ContentHash:          contentHash.Hex(),
+		AcknowledgementCount: count,
+		CreatedAt:            expression.CreatedAt,
+	}, nil
+}
+
+// party names a user and asks the chain whether they hold a valid POP
+func (s *verificationService) party(ctx context.Context, userID, role string) (*domain.PublicParty, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	party := &domain.PublicParty{Role: role}
+	if user == nil {
+		return party, nil
+	}
+
+	party.Name = user.DisplayName
+	if party.Name == "" {
+		party.Name = user.Username
+	}
+	if user.Address == "" || s.client == nil {
+		return party, nil
+	}
+	party.Address = common.HexToAddress(user.Address).Hex()
+	valid, err := chain.HasValidPOP(ctx, s.client, s.diamond, common.HexToAddress(user.Address))
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read POP status", "address", party.Address, "error", err)
+		return party, nil
+	}
+	party.HasValidPOP = &valid
+	return party, nil
+}
+
+// expressionChecks report where an expression is stored
+func expressionChecks(expression *domain.PublicExpression) []domain.IntegrityCheck {
+	checks := make([]domain.IntegrityCheck, 0, 2)
+	if expression.IPFSHash != "" {
+		checks = append(checks, domain.IntegrityCheck{Name: "Stored on IPFS", Status: domain.IntegrityPassed, Detail: expression.IPFSHash})
+	} else {
+		checks = append(checks, domain.IntegrityCheck{Name: "Stored on IPFS", Status: domain.IntegrityPending, Detail: "Not pinned yet"})
+	}
+	if expression.OnChainID > 0 {
+		checks = append(checks, domain.IntegrityCheck{Name: "Registered on-chain", Status: domain.IntegrityPassed, Detail: fmt.Sprintf("Expression #%d", expression.OnChainID)})
+	} else {
+		checks = append(checks, domain.IntegrityCheck{Name: "Registered on-chain", Status: domain.IntegrityPending, Detail: "Not registered yet"})
+	}
+	return checks
+}
+
+// anchorChecks report whether a record is in an anchored Merkle root and
+// still matches the leaf committed to
+func (s *verificationService) anchorChecks(ctx context.Context, id string) []domain.IntegrityCheck {
+	const name = "Anchored on-chain"
+	if s.anchorService == nil {
+		return []domain.IntegrityCheck{{Name: name, Status: domain.IntegrityUnavailable, Detail: "Anchoring is not enabled"}}
+	}
+
+	inclusion, err := s.anchorService.GetInclusionProof(ctx, id)
+	if errors.Is(err, domain.ErrNotAnchored) {
+		return []domain.IntegrityCheck{{Name: name, Status: domain.IntegrityPending, Detail: "Waiting for the next anchor batch"}}
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to check anchor", "document_id", id, "error", err)
+		return []domain.IntegrityCheck{{Name: name, Status: domain.IntegrityUnavailable, Detail: "Could not be checked"}}
+	}
+
+	anchored := domain.IntegrityCheck{Name: name, Status: domain.IntegrityPending, Detail: "Batch " + inclusion.Root + " is not confirmed yet"}
+	if inclusion.Status == domain.AnchorStatusConfirmed {
+		anchored.Status = domain.IntegrityPassed
+		anchored.Detail = fmt.Sprintf("Root %s in transaction %s", inclusion.Root, inclusion.TxHash)
+	}
+	intact := domain.IntegrityCheck{Name: "Unchanged since anchoring", Status: domain.IntegrityPassed, Detail: "Leaf " + inclusion.Leaf}
+	if !inclusion.Intact {
+		intact.Status = domain.IntegrityFailed
+		intact.Detail = "The record no longer matches the anchored leaf"
+	}
+	return []domain.IntegrityCheck{anchored, intact}
+}
+
+// popChecks report the POPNFTFacet's hasValidPOP for each party with a wallet
+func popChecks(parties []domain.PublicParty) []domain.IntegrityCheck {
+	var checks []domain.IntegrityCheck
+	for _, party := range parties {
+		if party.Address == "" {
+			continue
+		}
+		name := party.Name
+		if name == "" {
+			name = "The " + party.Role
+		}
+		check := domain.IntegrityCheck{Name: name + " holds a valid POP", Detail: party.Address}
+		switch {
+		case party.HasValidPOP == nil:
+			check.Status = domain.IntegrityUnavailable
+		case *party.HasValidPOP:
+			check.Status = domain.IntegrityPassed
+		default:
+			check.Status = domain.IntegrityFailed
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
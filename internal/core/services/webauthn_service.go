@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/tracing"
 
 	"time"
 
@@ -18,13 +18,23 @@ import (
 
 // WebAuthnService handles WebAuthn operations for passkey authentication
 type WebAuthnService struct {
-	webauthn          *webauthn.WebAuthn
-	passkeyRepository ports.PasskeyRepository
-	userRepository    ports.UserRepository
+	webauthn                *webauthn.WebAuthn
+	passkeyRepository       ports.PasskeyRepository
+	userRepository          ports.UserRepository
+	securityEventRepository ports.SecurityEventRepository
+	notificationService     ports.NotificationService
+	signCountPolicy         domain.SignCountPolicy
 }
 
 // NewWebAuthnService creates a new WebAuthn service
-func NewWebAuthnService(passkeyRepo ports.PasskeyRepository, userRepo ports.UserRepository) (*WebAuthnService, error) {
+func NewWebAuthnService(
+	passkeyRepo ports.PasskeyRepository,
+	userRepo ports.UserRepository,
+	securityEventRepo ports.SecurityEventRepository,
+	notificationService ports.NotificationService,
+	relyingParty string,
+	signCountPolicy domain.SignCountPolicy,
+) (*WebAuthnService, error) {
 
 	// var rpID string
 	// var rpOrigins []string
@@ -39,8 +49,8 @@ func NewWebAuthnService(passkeyRepo ports.PasskeyRepository, userRepo ports.User
 
 	wconfig := &webauthn.Config{
 		RPDisplayName: "Expressions of Peace",
-		RPID:          os.Getenv("RELYING_PARTY"),
-		RPOrigins:     []string{fmt.Sprintf("https://%s", os.Getenv("RELYING_PARTY"))},
+		RPID:          relyingParty,
+		RPOrigins:     []string{fmt.Sprintf("https://%s", relyingParty)},
 		Timeouts: webauthn.TimeoutsConfig{
 			Login: webauthn.TimeoutConfig{
 				Timeout: time.Second * 60,
@@ -61,9 +71,12 @@ func NewWebAuthnService(passkeyRepo ports.PasskeyRepository, userRepo ports.User
 	}
 
 	return &WebAuthnService{
-		webauthn:          w,
-		passkeyRepository: passkeyRepo,
-		userRepository:    userRepo,
+		webauthn:                w,
+		passkeyRepository:       passkeyRepo,
+		userRepository:          userRepo,
+		securityEventRepository: securityEventRepo,
+		notificationService:     notificationService,
+		signCountPolicy:         signCountPolicy,
 	}, nil
 }
 
@@ -109,12 +122,15 @@ func (u *WebAuthnUser) WebAuthnCredentials() []webauthn.Credential {
 
 // BeginRegistration starts the passkey registration process
 func (s *WebAuthnService) BeginRegistration(ctx context.Context, userID primitive.ObjectID) (*protocol.CredentialCreation, webauthn.SessionData, error) {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.BeginRegistration")
+	defer span.End()
+
 	user, err := s.userRepository.GetByID(ctx, userID.Hex())
 	if err != nil {
 		return nil, webauthn.SessionData{}, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return nil, webauthn.SessionData{}, fmt.Errorf("user not found")
+		return nil, webauthn.SessionData{}, domain.NotFound("user not found")
 	}
 
 	// Get existing credentials for the user
@@ -160,12 +176,15 @@ func (s *WebAuthnService) BeginRegistration(ctx context.Context, userID primitiv
 
 // FinishRegistration completes the passkey registration process
 func (s *WebAuthnService) FinishRegistration(ctx context.Context, userID primitive.ObjectID, sessionData webauthn.SessionData, response *protocol.ParsedCredentialCreationData) error {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.FinishRegistration")
+	defer span.End()
+
 	user, err := s.userRepository.GetByID(ctx, userID.Hex())
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return fmt.Errorf("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	// Get existing credentials for the user
@@ -225,12 +244,15 @@ func (s *WebAuthnService) FinishRegistration(ctx context.Context, userID primiti
 
 // BeginAuthentication starts the passkey authentication process
 func (s *WebAuthnService) BeginAuthentication(ctx context.Context, userID primitive.ObjectID) (*protocol.CredentialAssertion, webauthn.SessionData, error) {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.BeginAuthentication")
+	defer span.End()
+
 	user, err := s.userRepository.GetByID(ctx, userID.Hex())
 	if err != nil {
 		return nil, webauthn.SessionData{}, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return nil, webauthn.SessionData{}, fmt.Errorf("user not found")
+		return nil, webauthn.SessionData{}, domain.NotFound("user not found")
 	}
 
 	// Get existing credentials for the user
@@ -279,39 +301,37 @@ func (s *WebAuthnService) BeginAuthentication(ctx context.Context, userID primit
 
 // FinishAuthentication completes the passkey authentication process
 func (s *WebAuthnService) FinishAuthentication(ctx context.Context, userID primitive.ObjectID, sessionData webauthn.SessionData, response *protocol.ParsedCredentialAssertionData) error {
-	log.Printf("[WEBAUTHN-SERVICE] Starting FinishAuthentication for user: %s", userID.Hex())
+	ctx, span := tracing.Start(ctx, "WebAuthnService.FinishAuthentication")
+	defer span.End()
 
 	user, err := s.userRepository.GetByID(ctx, userID.Hex())
 	if err != nil {
-		log.Printf("[WEBAUTHN-SERVICE] Failed to get user: %v", err)
+		slog.ErrorContext(ctx, "failed to get user", "error", err)
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		log.Printf("[WEBAUTHN-SERVICE] User not found: %s", userID.Hex())
-		return fmt.Errorf("user not found")
+		slog.WarnContext(ctx, "passkey authentication for missing user", "user_id", userID.Hex())
+		return domain.NotFound("user not found")
 	}
-	log.Printf("[WEBAUTHN-SERVICE] Found user: %s", user.Email)
 
 	// Get existing credentials for the user
 	userPasskeys, err := s.passkeyRepository.GetActiveUserPasskeys(ctx, userID)
 	if err != nil {
-		log.Printf("[WEBAUTHN-SERVICE] Failed to get user passkeys: %v", err)
+		slog.ErrorContext(ctx, "failed to get user passkeys", "error", err)
 		return fmt.Errorf("failed to get user passkeys: %w", err)
 	}
-	log.Printf("[WEBAUTHN-SERVICE] Found %d active passkeys for user", len(userPasskeys))
 
 	var credentials []*domain.PasskeyCredential
 	for _, up := range userPasskeys {
 		cred, err := s.passkeyRepository.GetCredentialByID(ctx, up.CredentialID)
 		if err != nil {
-			log.Printf("[WEBAUTHN-SERVICE] Failed to get credential %s: %v", up.CredentialID, err)
+			slog.ErrorContext(ctx, "failed to get credential", "credential_id", up.CredentialID.Hex(), "error", err)
 			return fmt.Errorf("failed to get credential: %w", err)
 		}
 		if cred != nil {
 			credentials = append(credentials, cred)
 		}
 	}
-	log.Printf("[WEBAUTHN-SERVICE] Retrieved %d credentials", len(credentials))
 
 	webAuthnUser := &WebAuthnUser{
 		User:        user,
@@ -320,55 +340,297 @@ func (s *WebAuthnService) FinishAuthentication(ctx context.Context, userID primi
 
 	credential, err := s.webauthn.ValidateLogin(webAuthnUser, sessionData, response)
 	if err != nil {
-		log.Printf("[WEBAUTHN-SERVICE] Failed to validate login: %v", err)
-		// Always proceed with authentication if we found a matching credential
-		// This helps with cross-browser compatibility
-		for _, cred := range credentials {
-			if bytes.Equal(cred.CredentialID, response.RawID) {
-				credential = &webauthn.Credential{
-					ID:              cred.CredentialID,
-					PublicKey:       cred.PublicKey,
-					AttestationType: "",
-					Transport:       []protocol.AuthenticatorTransport{},
-					Authenticator: webauthn.Authenticator{
-						AAGUID:    cred.AAGUID,
-						SignCount: uint32(0), // Reset sign count to handle cross-browser issues
-					},
-				}
+		slog.InfoContext(ctx, "passkey login validation failed", "user_id", userID.Hex(), "error", err)
+		return fmt.Errorf("failed to validate login: %w", err)
+	}
+
+	if err := s.recordCredentialUse(ctx, userID, credentials, userPasskeys, credential, response); err != nil {
+		slog.ErrorContext(ctx, "failed to record credential use", "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "passkey sign-in", "user_id", userID.Hex())
+	return nil
+}
+
+// BeginDiscoverableLogin starts a usernameless passkey login. No user is known
+// yet, so the assertion carries no allowed credentials and the authenticator
+// offers any resident key it holds for this relying party.
+func (s *WebAuthnService) BeginDiscoverableLogin(ctx context.Context) (*protocol.CredentialAssertion, webauthn.SessionData, error) {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.BeginDiscoverableLogin")
+	defer span.End()
+
+	options, session, err := s.webauthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationPreferred),
+	)
+	if err != nil {
+		return nil, webauthn.SessionData{}, fmt.Errorf("failed to begin discoverable login: %w", err)
+	}
+
+	return options, *session, nil
+}
+
+// FinishDiscoverableLogin completes a usernameless passkey login and returns the
+// user resolved from the credential's userHandle
+func (s *WebAuthnService) FinishDiscoverableLogin(ctx context.Context, sessionData webauthn.SessionData, response *protocol.ParsedCredentialAssertionData) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.FinishDiscoverableLogin")
+	defer span.End()
+
+	var webAuthnUser *WebAuthnUser
+	var userPasskeys []*domain.UserPasskey
+
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		credential, err := s.passkeyRepository.GetCredentialByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential: %w", err)
+		}
+		if credential == nil {
+			return nil, domain.Unauthorized("credential not found")
+		}
+
+		// The userHandle is the user ID we issued in WebAuthnID at registration
+		userID, err := primitive.ObjectIDFromHex(string(userHandle))
+		if err != nil {
+			return nil, domain.Unauthorized("invalid user handle")
+		}
+
+		user, passkeys, err := s.loadWebAuthnUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		// The credential must be an active passkey of the user named by the handle
+		owned := false
+		for _, up := range passkeys {
+			if up.CredentialID == credential.ID {
+				owned = true
 				break
 			}
 		}
-		if credential == nil {
-			return fmt.Errorf("failed to find matching credential")
+		if !owned {
+			return nil, domain.Unauthorized("credential does not belong to user")
 		}
+
+		webAuthnUser = user
+		userPasskeys = passkeys
+		return user, nil
 	}
-	log.Printf("[WEBAUTHN-SERVICE] Successfully validated login")
 
-	// Update sign count
+	credential, err := s.webauthn.ValidateDiscoverableLogin(handler, sessionData, response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate discoverable login: %w", err)
+	}
+
+	if err := s.recordCredentialUse(ctx, webAuthnUser.ID, webAuthnUser.credentials, userPasskeys, credential, response); err != nil {
+		return nil, err
+	}
+
+	return webAuthnUser.User, nil
+}
+
+// loadWebAuthnUser fetches a user together with their active passkey credentials
+func (s *WebAuthnService) loadWebAuthnUser(ctx context.Context, userID primitive.ObjectID) (*WebAuthnUser, []*domain.UserPasskey, error) {
+	user, err := s.userRepository.GetByID(ctx, userID.Hex())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil, domain.NotFound("user not found")
+	}
+
+	userPasskeys, err := s.passkeyRepository.GetActiveUserPasskeys(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user passkeys: %w", err)
+	}
+
+	var credentials []*domain.PasskeyCredential
+	for _, up := range userPasskeys {
+		cred, err := s.passkeyRepository.GetCredentialByID(ctx, up.CredentialID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get credential: %w", err)
+		}
+		if cred != nil {
+			credentials = append(credentials, cred)
+		}
+	}
+
+	return &WebAuthnUser{User: user, credentials: credentials}, userPasskeys, nil
+}
+
+// recordCredentialUse stores the new sign count and last-used time for the
+// credential that was just asserted, applying the sign count policy if the
+// authenticator's counter did not increase. The library keeps the stored count
+// when it raises a clone warning, so the presented count comes from the response.
+func (s *WebAuthnService) recordCredentialUse(ctx context.Context, userID primitive.ObjectID, credentials []*domain.PasskeyCredential, userPasskeys []*domain.UserPasskey, credential *webauthn.Credential, response *protocol.ParsedCredentialAssertionData) error {
 	for _, cred := range credentials {
-		if bytes.Equal(cred.CredentialID, credential.ID) {
+		if !bytes.Equal(cred.CredentialID, credential.ID) {
+			continue
+		}
+
+		var userPasskey *domain.UserPasskey
+		for _, up := range userPasskeys {
+			if up.CredentialID == cred.ID {
+				userPasskey = up
+				break
+			}
+		}
 
-			// Update the credential's sign count
-			if err := s.passkeyRepository.UpdateCredentialSignCount(ctx, cred.ID, credential.Authenticator.SignCount); err != nil {
-				log.Printf("[WEBAUTHN-SERVICE] Failed to update credential sign count: %v", err)
-				return fmt.Errorf("failed to update sign count: %w", err)
+		if credential.Authenticator.CloneWarning {
+			return s.handleSignCountRegression(ctx, userID, cred, userPasskey, response.Response.AuthenticatorData.Counter)
+		}
+
+		if err := s.passkeyRepository.UpdateCredentialSignCount(ctx, cred.ID, credential.Authenticator.SignCount); err != nil {
+			return fmt.Errorf("failed to update sign count: %w", err)
+		}
+
+		if userPasskey != nil {
+			if err := s.passkeyRepository.UpdateUserPasskeyLastUsed(ctx, userPasskey.ID, userPasskey.DeviceInfo); err != nil {
+				return fmt.Errorf("failed to update last used: %w", err)
 			}
+		}
+		break
+	}
 
-			// Find the user-passkey relationship and update last used
-			for _, up := range userPasskeys {
-				if up.CredentialID == cred.ID {
-					deviceInfo := "" // Get device info from the client
-					if err := s.passkeyRepository.UpdateUserPasskeyLastUsed(ctx, up.ID, deviceInfo); err != nil {
-						log.Printf("[WEBAUTHN-SERVICE] Failed to update last used: %v", err)
-						return fmt.Errorf("failed to update last used: %w", err)
-					}
-					break
-				}
+	return nil
+}
+
+// handleSignCountRegression records a possible cloned authenticator, notifies the
+// user and applies the configured policy. The stored counter is left untouched so
+// the original authenticator keeps validating.
+func (s *WebAuthnService) handleSignCountRegression(ctx context.Context, userID primitive.ObjectID, cred *domain.PasskeyCredential, userPasskey *domain.UserPasskey, presentedCount uint32) error {
+	slog.WarnContext(ctx, "passkey sign count did not increase",
+		"credential_id", cred.ID.Hex(),
+		"stored_count", cred.SignCount,
+		"presented_count", presentedCount,
+		"policy", s.signCountPolicy,
+	)
+
+	details := map[string]interface{}{
+		"credentialId":   cred.ID,
+		"storedCount":    cred.SignCount,
+		"presentedCount": presentedCount,
+	}
+	if userPasskey != nil {
+		details["passkeyId"] = userPasskey.ID
+		details["passkeyName"] = userPasskey.Name
+	}
+
+	event := &domain.SecurityEvent{
+		UserID:    userID,
+		Type:      domain.SecurityEventSignCountRegression,
+		Action:    string(s.signCountPolicy),
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+	if err := s.securityEventRepository.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record security event: %w", err)
+	}
+
+	if err := s.notificationService.NotifySecurityAlert(ctx, event); err != nil {
+		// The event is already recorded, so don't fail the login flow over the notification
+		slog.ErrorContext(ctx, "failed to send security alert", "error", err)
+	}
+
+	switch s.signCountPolicy {
+	case domain.SignCountPolicyDeactivate:
+		if userPasskey != nil {
+			if err := s.passkeyRepository.DeactivateUserPasskey(ctx, userPasskey.ID); err != nil {
+				return fmt.Errorf("failed to deactivate passkey: %w", err)
 			}
-			break
+		}
+		return domain.ErrPasskeyDeactivated
+	case domain.SignCountPolicyRequireSecondFactor:
+		return domain.ErrSecondFactorRequired
+	}
+
+	if userPasskey != nil {
+		if err := s.passkeyRepository.UpdateUserPasskeyLastUsed(ctx, userPasskey.ID, userPasskey.DeviceInfo); err != nil {
+			return fmt.Errorf("failed to update last used: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListPasskeys returns all passkeys registered by a user, including revoked ones
+func (s *WebAuthnService) ListPasskeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.UserPasskey, error) {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.ListPasskeys")
+	defer span.End()
+
+	userPasskeys, err := s.passkeyRepository.GetUserPasskeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user passkeys: %w", err)
+	}
+	if userPasskeys == nil {
+		userPasskeys = []*domain.UserPasskey{}
+	}
+
+	return userPasskeys, nil
+}
+
+// RenamePasskey changes the display name of one of the user's passkeys
+func (s *WebAuthnService) RenamePasskey(ctx context.Context, userID primitive.ObjectID, passkeyID primitive.ObjectID, name string) error {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.RenamePasskey")
+	defer span.End()
+
+	if _, err := s.getOwnedPasskey(ctx, userID, passkeyID); err != nil {
+		return err
+	}
+
+	if err := s.passkeyRepository.RenameUserPasskey(ctx, passkeyID, name); err != nil {
+		return fmt.Errorf("failed to rename passkey: %w", err)
+	}
+
+	return nil
+}
+
+// RevokePasskey deactivates one of the user's passkeys. A passkey cannot be
+// revoked if the user would be left without a password, wallet or other passkey.
+func (s *WebAuthnService) RevokePasskey(ctx context.Context, userID primitive.ObjectID, passkeyID primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "WebAuthnService.RevokePasskey")
+	defer span.End()
+
+	userPasskey, err := s.getOwnedPasskey(ctx, userID, passkeyID)
+	if err != nil {
+		return err
+	}
+	if !userPasskey.IsActive {
+		return nil // Already revoked
+	}
+
+	user, err := s.userRepository.GetByID(ctx, userID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return domain.NotFound("user not found")
+	}
+
+	if user.Password == "" && user.Address == "" {
+		activePasskeys, err := s.passkeyRepository.GetActiveUserPasskeys(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get user passkeys: %w", err)
+		}
+		if len(activePasskeys) <= 1 {
+			return domain.ErrLastLoginMethod
 		}
 	}
 
-	log.Printf("[WEBAUTHN-SERVICE] Authentication completed successfully")
+	if err := s.passkeyRepository.DeactivateUserPasskey(ctx, passkeyID); err != nil {
+		return fmt.Errorf("failed to revoke passkey: %w", err)
+	}
+
 	return nil
 }
+
+// getOwnedPasskey loads a user-passkey relationship and checks it belongs to the user
+func (s *WebAuthnService) getOwnedPasskey(ctx context.Context, userID primitive.ObjectID, passkeyID primitive.ObjectID) (*domain.UserPasskey, error) {
+	userPasskey, err := s.passkeyRepository.GetUserPasskeyByID(ctx, passkeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+	if userPasskey == nil || userPasskey.UserID != userID {
+		return nil, domain.ErrPasskeyNotFound
+	}
+
+	return userPasskey, nil
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// LocalURLPrefix is where the server serves files kept by LocalStorage
+const LocalURLPrefix = "/media"
+
+// LocalStorage keeps files in a directory on disk. It is meant for local
+// development without an R2 bucket; URLs point at LocalURLPrefix, which the
+// server maps to the same directory.
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates dir if needed and stores files under it
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Dir is the directory files are kept in
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// path maps a key to a file inside the storage directory
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
+
+// UploadFile writes the file, replacing any file with the same key
+func (s *LocalStorage) UploadFile(ctx context.Context, key string, reader io.Reader, opts ...UploadOptions) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial upload
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, reader); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+// GetFile opens the file for reading
+func (s *LocalStorage) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	return file, nil
+}
+
+// DeleteFile removes the file; deleting a missing file is not an error
+func (s *LocalStorage) DeleteFile(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetPresignedURL returns the path the server serves the file at. Local
+// files are not access-controlled, so expires is ignored.
+func (s *LocalStorage) GetPresignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	return LocalURLPrefix + (&url.URL{Path: path.Clean("/" + key)}).EscapedPath(), nil
+}
+
+// Name identifies local storage in readiness checks
+func (s *LocalStorage) Name() string {
+	return "storage"
+}
+
+// Check confirms the storage directory is still there
+func (s *LocalStorage) Check(ctx context.Context) error {
+	info, err := os.Stat(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to reach storage directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("storage path %s is not a directory", s.dir)
+	}
+	return nil
+}
@@ -6,15 +6,19 @@ import (
 	"io"
 	"mime"
 	"path/filepath"
+	"proofofpeacemaking/internal/metrics"
+	"proofofpeacemaking/internal/tracing"
 	"time"
 
-	"log"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -93,50 +97,94 @@ func shouldCompress(contentType string) bool {
 
 // UploadFile uploads a file to R2 storage with optimizations
 func (s *R2Storage) UploadFile(ctx context.Context, key string, reader io.Reader, opts ...UploadOptions) error {
-	log.Printf("[R2] Starting upload for key: %s", key)
+	ctx, span := s.startSpan(ctx, "UploadFile", key)
+	defer span.End()
 
 	var uploadOpts UploadOptions
 	if len(opts) > 0 {
 		uploadOpts = opts[0]
-		log.Printf("[R2] Upload options - ContentType: %s, CacheControl: %s", uploadOpts.ContentType, uploadOpts.CacheControl)
 	}
 
 	// Set default content type if not provided
 	if uploadOpts.ContentType == "" {
 		uploadOpts.ContentType = getContentType(key)
-		log.Printf("[R2] Using default content type: %s", uploadOpts.ContentType)
 	}
 
 	// Set default cache control if not provided
 	if uploadOpts.CacheControl == "" {
 		uploadOpts.CacheControl = fmt.Sprintf("public, max-age=%d", defaultCacheAge)
-		log.Printf("[R2] Using default cache control: %s", uploadOpts.CacheControl)
 	}
 
+	counter := &countingReader{reader: reader}
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 
-		Body:         reader,
+		Body:         counter,
 		ContentType:  aws.String(uploadOpts.ContentType),
 		CacheControl: aws.String(uploadOpts.CacheControl),
 	}
 
 	// Use uploader for efficient multipart upload
-	log.Printf("[R2] Starting multipart upload to bucket: %s", s.bucket)
+	start := time.Now()
 	result, err := s.uploader.Upload(ctx, input)
+	outcome := metrics.Outcome(err)
+	metrics.StorageUploadDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	metrics.StorageUploadBytes.WithLabelValues(outcome).Add(float64(counter.n))
 	if err != nil {
-		log.Printf("[R2] Upload failed for key %s: %v", key, err)
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "R2 upload failed", "key", key, "error", err)
 		return fmt.Errorf("failed to upload file: %v", err)
 	}
-	log.Printf("[R2] Successfully uploaded file. Key: %s, ETag: %s", key, *result.ETag)
+	span.SetAttributes(attribute.Int64("storage.bytes", counter.n))
+	slog.DebugContext(ctx, "uploaded file to R2", "key", key, "etag", aws.ToString(result.ETag), "content_type", uploadOpts.ContentType)
 
 	return nil
 }
 
+// countingReader counts the bytes read through it for the upload metrics
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// startSpan opens a client span for one bucket operation
+func (s *R2Storage) startSpan(ctx context.Context, operation, key string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "R2Storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.bucket", s.bucket),
+			attribute.String("storage.key", key),
+		),
+	)
+}
+
+// Name identifies R2 in readiness checks
+func (s *R2Storage) Name() string {
+	return "storage"
+}
+
+// Check confirms the bucket is reachable with the configured credentials
+func (s *R2Storage) Check(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reach bucket: %w", err)
+	}
+	return nil
+}
+
 // GetFile retrieves a file from R2 storage with optimized settings
 func (s *R2Storage) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
-	log.Printf("[R2] Retrieving file with key: %s", key)
+	ctx, span := s.startSpan(ctx, "GetFile", key)
+	defer span.End()
 
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
@@ -145,21 +193,26 @@ func (s *R2Storage) GetFile(ctx context.Context, key string) (io.ReadCloser, err
 
 	result, err := s.client.GetObject(ctx, input)
 	if err != nil {
-		log.Printf("[R2] Failed to get file with key %s: %v", key, err)
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "R2 get failed", "key", key, "error", err)
 		return nil, fmt.Errorf("failed to get file: %v", err)
 	}
-	log.Printf("[R2] Successfully retrieved file. Key: %s, Size: %d", key, result.ContentLength)
+	slog.DebugContext(ctx, "retrieved file from R2", "key", key, "size", aws.ToInt64(result.ContentLength))
 
 	return result.Body, nil
 }
 
 // DeleteFile removes a file from R2 storage
 func (s *R2Storage) DeleteFile(ctx context.Context, key string) error {
+	ctx, span := s.startSpan(ctx, "DeleteFile", key)
+	defer span.End()
+
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
+		tracing.Fail(span, err)
 		return fmt.Errorf("failed to delete file: %v", err)
 	}
 	return nil
@@ -167,6 +220,9 @@ func (s *R2Storage) DeleteFile(ctx context.Context, key string) error {
 
 // ListFiles lists all files in the bucket with the given prefix
 func (s *R2Storage) ListFiles(ctx context.Context, prefix string) ([]string, error) {
+	ctx, span := s.startSpan(ctx, "ListFiles", prefix)
+	defer span.End()
+
 	input := &s3.ListObjectsV2Input{
 		Bucket: aws.String(s.bucket),
 		Prefix: aws.String(prefix),
@@ -177,6 +233,7 @@ func (s *R2Storage) ListFiles(ctx context.Context, prefix string) ([]string, err
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
+			tracing.Fail(span, err)
 			return nil, fmt.Errorf("failed to list files: %v", err)
 		}
 		for _, obj := range page.Contents {
@@ -188,7 +245,8 @@ func (s *R2Storage) ListFiles(ctx context.Context, prefix string) ([]string, err
 
 // GetPresignedURL generates a presigned URL for direct client access
 func (s *R2Storage) GetPresignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
-	log.Printf("[R2] Generating presigned URL for key: %s with expiry: %v", key, expires)
+	ctx, span := s.startSpan(ctx, "GetPresignedURL", key)
+	defer span.End()
 
 	presignClient := s3.NewPresignClient(s.client)
 
@@ -198,10 +256,10 @@ func (s *R2Storage) GetPresignedURL(ctx context.Context, key string, expires tim
 	}, s3.WithPresignExpires(expires))
 
 	if err != nil {
-		log.Printf("[R2] Failed to generate presigned URL for key %s: %v", key, err)
+		tracing.Fail(span, err)
+		slog.ErrorContext(ctx, "failed to generate presigned URL", "key", key, "error", err)
 		return "", fmt.Errorf("failed to generate presigned URL: %v", err)
 	}
-	log.Printf("[R2] Successfully generated presigned URL for key: %s", key)
 
 	return request.URL, nil
 }
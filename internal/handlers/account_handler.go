@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"strconv"
@@ -33,12 +34,12 @@ func (h *AccountHandler) HandleAccount(c *fiber.Ctx) error {
 	var err error
 
 	// Try to get user by wallet address first
-	user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+	user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	if err != nil || user == nil {
 		// If not found by address, try by email
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 		if err != nil {
-			log.Printf("Error getting user: %v", err)
+			slog.ErrorContext(c.UserContext(), "failed to get user", "error", err)
 			return c.Render("error", fiber.Map{
 				"Error": "Failed to get user data",
 			})
@@ -46,7 +47,7 @@ func (h *AccountHandler) HandleAccount(c *fiber.Ctx) error {
 	}
 
 	if user == nil {
-		log.Printf("User not found for identifier: %s", userIdentifier)
+		slog.WarnContext(c.UserContext(), "user not found", "identifier", userIdentifier)
 		return c.Render("error", fiber.Map{
 			"Error": "User not found",
 		})
@@ -62,23 +63,18 @@ func (h *AccountHandler) UpdateProfile(c *fiber.Ctx) error {
 	userIdentifier := c.Locals("userAddress").(string)
 
 	// Try to get user by wallet address first
-	user, err := h.userService.GetUserByAddress(c.Context(), userIdentifier)
+	user, err := h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	if err != nil || user == nil {
 		// If not found by address, try by email
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 		if err != nil {
-			log.Printf("Error getting user: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to get user data",
-			})
+			return fmt.Errorf("failed to get user data: %w", err)
 		}
 	}
 
 	if user == nil {
-		log.Printf("User not found for identifier: %s", userIdentifier)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		slog.WarnContext(c.UserContext(), "user not found", "identifier", userIdentifier)
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
 	var updateData struct {
@@ -88,9 +84,7 @@ func (h *AccountHandler) UpdateProfile(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&updateData); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request data",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request data")
 	}
 
 	// Store old citizenship to check if it changed
@@ -102,17 +96,14 @@ func (h *AccountHandler) UpdateProfile(c *fiber.Ctx) error {
 	user.City = updateData.City
 
 	// Validate and update user
-	if err := h.userService.Update(c.Context(), user); err != nil {
-		log.Printf("Error updating user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update user",
-		})
+	if err := h.userService.Update(c.UserContext(), user); err != nil {
+		return err
 	}
 
 	// If citizenship changed, update statistics
 	if oldCitizenship != user.Citizenship {
-		if err := h.statsService.UpdateStatisticsAfterCitizenshipChange(c.Context()); err != nil {
-			log.Printf("Error updating statistics after citizenship change: %v", err)
+		if err := h.statsService.UpdateStatisticsAfterCitizenshipChange(c.UserContext()); err != nil {
+			slog.WarnContext(c.UserContext(), "failed to update statistics after citizenship change", "error", err)
 			// Don't return error here as the user update was successful
 		}
 	}
@@ -128,33 +119,23 @@ func (h *AccountHandler) GetWalletNonce(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&data); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request data",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request data")
 	}
 
 	// Check if wallet is already connected to another user
-	existingUser, err := h.userService.GetUserByAddress(c.Context(), data.Address)
+	existingUser, err := h.userService.GetUserByAddress(c.UserContext(), data.Address)
 	if err != nil {
-		log.Printf("Error checking existing wallet: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to check wallet status",
-		})
+		return fmt.Errorf("failed to check wallet status: %w", err)
 	}
 
 	if existingUser != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Wallet is already connected to another account",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Wallet is already connected to another account")
 	}
 
 	// Generate nonce for the wallet
-	nonce, err := h.authService.GenerateNonce(c.Context(), data.Address)
+	nonce, err := h.authService.GenerateNonce(c.UserContext(), data.Address)
 	if err != nil {
-		log.Printf("Error generating nonce: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to generate nonce",
-		})
+		return fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -170,74 +151,67 @@ func (h *AccountHandler) ConnectWallet(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&data); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request data",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request data")
 	}
 
 	userIdentifier := c.Locals("userAddress").(string)
 
 	// Try to get user by wallet address first
-	user, err := h.userService.GetUserByAddress(c.Context(), userIdentifier)
+	user, err := h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	if err != nil || user == nil {
 		// If not found by address, try by email
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 		if err != nil {
-			log.Printf("Error getting user: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to get user data",
-			})
+			return fmt.Errorf("failed to get user data: %w", err)
 		}
 	}
 
 	if user == nil {
-		log.Printf("User not found for identifier: %s", userIdentifier)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		slog.WarnContext(c.UserContext(), "user not found", "identifier", userIdentifier)
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
 	// Verify signature
-	verified, storedNonce, err := h.authService.VerifySignature(c.Context(), data.Address, data.Signature)
+	verified, storedNonce, err := h.authService.VerifySignature(c.UserContext(), data.Address, data.Signature)
 	if err != nil {
-		log.Printf("Error verifying signature: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to verify wallet ownership",
-		})
+		return fmt.Errorf("failed to verify wallet ownership: %w", err)
 	}
 
 	if !verified {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid signature",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid signature")
 	}
 
 	// Convert stored nonce to int
 	storedNonceInt, err := strconv.Atoi(storedNonce)
 	if err != nil {
-		log.Printf("Error converting nonce: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to verify nonce",
-		})
+		return fmt.Errorf("failed to verify nonce: %w", err)
 	}
 
 	// Verify nonce
 	if storedNonceInt != data.Nonce {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid nonce",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid nonce")
 	}
 
-	if err := h.userService.ConnectWallet(c.Context(), user.ID, data.Address); err != nil {
-		if err.Error() == "wallet already connected to another account" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Wallet is already connected to another account",
+	if err := h.userService.ConnectWallet(c.UserContext(), user.ID, data.Address); err != nil {
+		return err
+	}
+
+	// Linking a wallet changes what this session can do, so issue a fresh token
+	if token := c.Cookies("session"); token != "" {
+		newToken, err := h.authService.RotateSession(c.UserContext(), token)
+		if err != nil {
+			slog.ErrorContext(c.UserContext(), "failed to rotate session", "error", err)
+		} else {
+			c.Cookie(&fiber.Cookie{
+				Name:     "session",
+				Value:    newToken,
+				Path:     "/",
+				MaxAge:   24 * 60 * 60, // 24 hours
+				Secure:   true,
+				HTTPOnly: true,
+				SameSite: "Strict",
 			})
 		}
-		log.Printf("Error connecting wallet: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to connect wallet",
-		})
 	}
 
 	return c.SendStatus(fiber.StatusOK)
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"fmt"
+	"proofofpeacemaking/internal/core/chain"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"strings"
 	"time"
 
-	"log"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -33,83 +35,69 @@ func NewAcknowledgementHandler(
 	}
 }
 
+// Create acknowledges an expression, or toggles an existing acknowledgement
+// between active and refuted. An optional attestation signs the result, see
+// GetAttestationDomain.
 func (h *AcknowledgementHandler) Create(c *fiber.Ctx) error {
 	var body struct {
 		ExpressionID string            `json:"expressionId"`
 		Content      map[string]string `json:"content"`
+		Attestation  *struct {
+			Timestamp int64  `json:"timestamp"`
+			Signature string `json:"signature"`
+		} `json:"attestation"`
 	}
 
 	if err := c.BodyParser(&body); err != nil {
-		log.Printf("[ACK] Error parsing body: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		slog.InfoContext(c.UserContext(), "invalid acknowledgement body", "error", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	log.Printf("[ACK] Creating/updating acknowledgement for expression: %s", body.ExpressionID)
 	userIdentifier := c.Locals("userAddress").(string)
-	log.Printf("[ACK] User identifier from context: %s", userIdentifier)
 
 	// Get user by email or address
 	var user *domain.User
 	var err error
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		log.Printf("[ACK] Error getting user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user",
-		})
+		return fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		log.Printf("[ACK] User not found for identifier: %s", userIdentifier)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		slog.WarnContext(c.UserContext(), "user not found", "identifier", userIdentifier)
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
-	log.Printf("[ACK] Found user with ID: %s", user.ID.Hex())
 
-	// Get the expression to check ownership
-	expression, err := h.expressionService.Get(c.Context(), body.ExpressionID)
-	if err != nil {
-		if strings.Contains(err.Error(), "invalid expression ID format") {
-			log.Printf("[ACK] Invalid expression ID format: %v", err)
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid expression ID format",
-			})
+	// The signer is always the account's own wallet
+	var attestation *domain.AcknowledgementAttestation
+	if body.Attestation != nil {
+		attestation = &domain.AcknowledgementAttestation{
+			Signer:    user.Address,
+			Timestamp: body.Attestation.Timestamp,
+			Signature: body.Attestation.Signature,
 		}
-		log.Printf("[ACK] Error getting expression: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get expression",
-		})
 	}
-	if expression == nil {
-		log.Printf("[ACK] Expression not found: %s", body.ExpressionID)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Expression not found",
-		})
+
+	// Get the expression to check ownership
+	expression, err := h.expressionService.Get(c.UserContext(), body.ExpressionID)
+	if err != nil {
+		return err
 	}
-	log.Printf("[ACK] Found expression with creator: %s", expression.CreatorAddress)
 
 	// Prevent self-acknowledgements - compare user IDs instead of addresses
 	if expression.Creator == user.ID.Hex() {
-		log.Printf("[ACK] Attempted self-acknowledgement")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot acknowledge your own expression",
-		})
+		slog.InfoContext(c.UserContext(), "rejected self-acknowledgement", "expression_id", body.ExpressionID)
+		return fiber.NewError(fiber.StatusBadRequest, "Cannot acknowledge your own expression")
 	}
 
 	// Check if user has already acknowledged this expression
-	existingAcks, err := h.acknowledgementService.ListByExpression(c.Context(), body.ExpressionID)
+	existingAcks, err := h.acknowledgementService.ListByExpression(c.UserContext(), body.ExpressionID)
 	if err != nil {
-		log.Printf("[ACK] Error checking existing acknowledgements: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to check existing acknowledgements",
-		})
+		return fmt.Errorf("failed to check existing acknowledgements: %w", err)
 	}
 
 	var existingAck *domain.Acknowledgement
@@ -128,12 +116,11 @@ func (h *AcknowledgementHandler) Create(c *fiber.Ctx) error {
 			existingAck.Status = domain.AcknowledgementStatusActive
 		}
 		existingAck.UpdatedAt = time.Now()
+		// A signature covers the status it was made for, so an old one is dropped
+		existingAck.Attestation = attestation
 
-		if err := h.acknowledgementService.Update(c.Context(), existingAck); err != nil {
-			log.Printf("[ACK] Error updating acknowledgement: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to update acknowledgement",
-			})
+		if err := h.acknowledgementService.Update(c.UserContext(), existingAck); err != nil {
+			return err
 		}
 
 		return c.JSON(existingAck)
@@ -146,22 +133,20 @@ func (h *AcknowledgementHandler) Create(c *fiber.Ctx) error {
 		Acknowledger: user.ID.Hex(),
 		Content:      body.Content,
 		Status:       domain.AcknowledgementStatusActive,
+		Attestation:  attestation,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := h.acknowledgementService.Create(c.Context(), acknowledgement); err != nil {
-		log.Printf("[ACK] Error creating acknowledgement: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create acknowledgement",
-		})
+	if err := h.acknowledgementService.Create(c.UserContext(), acknowledgement); err != nil {
+		return err
 	}
 
-	log.Printf("[ACK] Successfully created acknowledgement: %s", acknowledgement.ID.Hex())
+	slog.InfoContext(c.UserContext(), "acknowledgement created", "acknowledgement_id", acknowledgement.ID.Hex(), "expression_id", body.ExpressionID)
 
 	// After creating/updating acknowledgement, update statistics
-	if err := h.statsService.UpdateStatisticsAfterAcknowledgement(c.Context()); err != nil {
-		log.Printf("[ACK] Warning: Failed to update statistics: %v", err)
+	if err := h.statsService.UpdateStatisticsAfterAcknowledgement(c.UserContext()); err != nil {
+		slog.WarnContext(c.UserContext(), "failed to update statistics", "error", err)
 		// Don't return error here, as the acknowledgement was created successfully
 	}
 
@@ -170,11 +155,48 @@ func (h *AcknowledgementHandler) Create(c *fiber.Ctx) error {
 
 func (h *AcknowledgementHandler) ListByExpression(c *fiber.Ctx) error {
 	expressionID := c.Params("id")
-	acknowledgements, err := h.acknowledgementService.ListByExpression(c.Context(), expressionID)
+	acknowledgements, err := h.acknowledgementService.ListByExpression(c.UserContext(), expressionID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch acknowledgements",
-		})
+		return fmt.Errorf("failed to fetch acknowledgements: %w", err)
 	}
 	return c.JSON(acknowledgements)
 }
+
+// GetAttestationDomain returns the EIP-712 domain and types acknowledgements
+// are signed with. The contentHash field is the keccak256 hash of the
+// content as JSON with sorted keys.
+func (h *AcknowledgementHandler) GetAttestationDomain(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"domain":      chain.AttestationDomain,
+		"types":       chain.AttestationTypes,
+		"primaryType": chain.AttestationPrimaryType,
+	})
+}
+
+// VerifyAttestation checks an attestation presented on its own, so anyone
+// holding one can confirm who signed it
+func (h *AcknowledgementHandler) VerifyAttestation(c *fiber.Ctx) error {
+	var body struct {
+		Message   domain.AttestationMessage `json:"message"`
+		Signature string                    `json:"signature"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	verification, err := h.acknowledgementService.VerifyAttestation(c.UserContext(), body.Message, body.Signature)
+	if err != nil {
+		return err
+	}
+	return c.JSON(verification)
+}
+
+// GetAttestation returns an acknowledgement's attestation and whether it
+// still matches the acknowledgement
+func (h *AcknowledgementHandler) GetAttestation(c *fiber.Ctx) error {
+	verification, err := h.acknowledgementService.GetAttestation(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(verification)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AdminHandler struct {
+	userService    ports.UserService
+	sessionService ports.SessionService
+}
+
+func NewAdminHandler(userService ports.UserService, sessionService ports.SessionService) *AdminHandler {
+	return &AdminHandler{
+		userService:    userService,
+		sessionService: sessionService,
+	}
+}
+
+// SetUserRole assigns a role to a user, letting admins appoint moderators. The
+// user's sessions are revoked so they sign in again under the new role.
+func (h *AdminHandler) SetUserRole(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
+	}
+
+	var req struct {
+		Role domain.Role `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil || !domain.ValidRole(req.Role) {
+		return fiber.NewError(fiber.StatusBadRequest, "role must be one of user, moderator, admin")
+	}
+
+	target, err := h.userService.GetUserByID(c.UserContext(), userID.Hex())
+	if err != nil || target == nil {
+		return fiber.NewError(fiber.StatusNotFound, "user not found")
+	}
+
+	// Admins cannot demote themselves, so there is always someone left to manage roles
+	if actor, _ := c.Locals("userAddress").(string); actor != "" && (actor == target.Address || actor == target.Email) {
+		return fiber.NewError(fiber.StatusBadRequest, "you cannot change your own role")
+	}
+
+	if err := h.userService.SetRole(c.UserContext(), userID, req.Role); err != nil {
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+	if target.EffectiveRole() != req.Role {
+		if err := h.sessionService.RevokeAll(c.UserContext(), userID.Hex()); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	slog.InfoContext(c.UserContext(), "user role changed", "user_id", userID.Hex(), "role", req.Role)
+	return c.JSON(fiber.Map{
+		"id":   userID.Hex(),
+		"role": req.Role,
+	})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errAnchoringDisabled is returned when no chain is configured to anchor to
+var errAnchoringDisabled = fiber.NewError(fiber.StatusServiceUnavailable, "anchoring is not enabled")
+
+type AnchorHandler struct {
+	anchorService ports.AnchorService
+}
+
+// NewAnchorHandler serves inclusion proofs. anchorService is nil when the
+// server runs without a chain.
+func NewAnchorHandler(anchorService ports.AnchorService) *AnchorHandler {
+	return &AnchorHandler{
+		anchorService: anchorService,
+	}
+}
+
+// GetInclusionProof returns the Merkle proof that an expression or
+// acknowledgement is in an anchored batch
+func (h *AnchorHandler) GetInclusionProof(c *fiber.Ctx) error {
+	if h.anchorService == nil {
+		return errAnchoringDisabled
+	}
+
+	proof, err := h.anchorService.GetInclusionProof(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(proof)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultAPITokenDays is the lifetime used when a token request does not specify one
+const defaultAPITokenDays = 90
+
+type APITokenHandler struct {
+	apiTokenService ports.APITokenService
+	userService     ports.UserService
+}
+
+func NewAPITokenHandler(apiTokenService ports.APITokenService, userService ports.UserService) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenService: apiTokenService,
+		userService:     userService,
+	}
+}
+
+func (h *APITokenHandler) GetAPITokenService() ports.APITokenService {
+	return h.apiTokenService
+}
+
+// ListTokens returns the current user's personal access tokens
+func (h *APITokenHandler) ListTokens(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := h.apiTokenService.List(c.UserContext(), user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	return c.JSON(tokens)
+}
+
+// CreateToken issues a new personal access token. The plaintext token is only returned here.
+func (h *APITokenHandler) CreateToken(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Name          string                 `json:"name"`
+		Scopes        []domain.APITokenScope `json:"scopes"`
+		ExpiresInDays int                    `json:"expiresInDays"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 64 {
+		return fiber.NewError(fiber.StatusBadRequest, "name must be between 1 and 64 characters")
+	}
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = defaultAPITokenDays
+	}
+
+	token, plaintext, err := h.apiTokenService.Create(c.UserContext(), user.ID, req.Name, req.Scopes, time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"token":    plaintext,
+		"apiToken": token,
+	})
+}
+
+// RevokeToken permanently disables one of the current user's tokens
+func (h *APITokenHandler) RevokeToken(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	tokenID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid token ID")
+	}
+
+	if err := h.apiTokenService.Revoke(c.UserContext(), user.ID, tokenID); err != nil {
+		if errors.Is(err, domain.ErrAPITokenNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "token not found")
+		}
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+	})
+}
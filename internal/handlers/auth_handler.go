@@ -1,47 +1,49 @@
 package handlers
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/metrics"
+	"proofofpeacemaking/internal/middleware"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type AuthHandler struct {
-	authService ports.AuthService
-	userService ports.UserService
+	authService      ports.AuthService
+	userService      ports.UserService
+	rateLimitService ports.RateLimitService
 }
 
-func NewAuthHandler(authService ports.AuthService, userService ports.UserService) *AuthHandler {
+func NewAuthHandler(authService ports.AuthService, userService ports.UserService, rateLimitService ports.RateLimitService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		userService: userService,
+		authService:      authService,
+		userService:      userService,
+		rateLimitService: rateLimitService,
 	}
 }
 
 func (h *AuthHandler) GenerateNonce(c *fiber.Ctx) error {
-	log.Printf("[AUTH] Generating nonce - Request Method: %s", c.Method())
 	address := c.Query("address")
-	log.Printf("[AUTH] Address from query: %s", address)
 
 	if address == "" {
-		log.Printf("[AUTH] Error: Missing address in request")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Address is required",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Address is required")
 	}
 
-	nonce, err := h.authService.GenerateNonce(c.Context(), address)
+	nonce, err := h.authService.GenerateNonce(c.UserContext(), address)
 	if err != nil {
-		log.Printf("[AUTH] Error generating nonce: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	log.Printf("[AUTH] Successfully generated nonce %d for address %s", nonce, address)
+	slog.DebugContext(c.UserContext(), "generated nonce", "address", address)
 	return c.JSON(fiber.Map{
 		"nonce": nonce,
 	})
@@ -54,23 +56,26 @@ func (h *AuthHandler) VerifySignature(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	isValid, token, err := h.authService.VerifySignature(c.Context(), body.Address, body.Signature)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	lockoutSubject := "wallet:" + strings.ToLower(strings.TrimSpace(body.Address))
+	if err := checkLockout(c, h.rateLimitService, lockoutSubject); err != nil {
+		return err
 	}
 
-	if !isValid {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid signature",
-		})
+	isValid, token, err := h.authService.VerifySignature(middleware.ClientContext(c), body.Address, body.Signature)
+	metrics.ObserveAuth(metrics.AuthMethodWallet, err == nil && isValid)
+	if err != nil || !isValid {
+		if lockErr := recordFailedSignIn(c, h.rateLimitService, lockoutSubject); lockErr != nil {
+			return lockErr
+		}
+		if err != nil {
+			return err
+		}
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid signature")
 	}
+	recordSignIn(c, h.rateLimitService, lockoutSubject)
 
 	// Set session cookie
 	cookie := fiber.Cookie{
@@ -98,22 +103,16 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	if body.Address == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Address is required",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Address is required")
 	}
 
-	user, token, err := h.authService.Register(c.Context(), body.Address, body.Email)
+	user, token, err := h.authService.Register(middleware.ClientContext(c), body.Address, body.Email)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to register user",
-		})
+		return fmt.Errorf("failed to register user: %w", err)
 	}
 
 	// Set secure HTTP-only cookie
@@ -144,15 +143,15 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 		}
 
 		// Invalidate session in database
-		if err := h.authService.Logout(c.Context(), sessionToken); err != nil {
-			log.Printf("[AUTH] Error invalidating session: %v", err)
+		if err := h.authService.Logout(c.UserContext(), sessionToken); err != nil {
+			slog.ErrorContext(c.UserContext(), "failed to invalidate session", "error", err)
 			// Continue with cookie cleanup even if session invalidation fails
 		}
 
 		// Only try to delete all sessions if we have a user identifier
 		if userIdentifier != "" {
-			if err := h.authService.DeleteAllUserSessions(c.Context(), userIdentifier); err != nil {
-				log.Printf("[AUTH] Error deleting user sessions: %v", err)
+			if err := h.authService.DeleteAllUserSessions(c.UserContext(), userIdentifier); err != nil {
+				slog.ErrorContext(c.UserContext(), "failed to delete user sessions", "error", err)
 				// Continue as this is not critical
 			}
 		}
@@ -184,16 +183,16 @@ func (h *AuthHandler) GetSession(c *fiber.Ctx) error {
 	// Get session token from cookie
 	sessionCookie := c.Cookies("session")
 	if sessionCookie == "" {
-		log.Printf("[AUTH] No session cookie found")
+		slog.DebugContext(c.UserContext(), "no session cookie")
 		return c.JSON(fiber.Map{
 			"authenticated": false,
 		})
 	}
 
 	// Verify session token and get address
-	address, err := h.authService.VerifyToken(c.Context(), sessionCookie)
+	address, err := h.authService.VerifyToken(middleware.ClientContext(c), sessionCookie)
 	if err != nil {
-		log.Printf("[AUTH] Session verification failed: %v", err)
+		slog.InfoContext(c.UserContext(), "session verification failed", "error", err)
 		// Clear invalid cookie
 		c.Cookie(&fiber.Cookie{
 			Name:     "session",
@@ -210,7 +209,6 @@ func (h *AuthHandler) GetSession(c *fiber.Ctx) error {
 		})
 	}
 
-	// log.Printf("[AUTH] Session verified for address: %s", address)
 	return c.JSON(fiber.Map{
 		"authenticated": true,
 		"address":       address,
@@ -221,6 +219,10 @@ func (h *AuthHandler) GetAuthService() ports.AuthService {
 	return h.authService
 }
 
+func (h *AuthHandler) GetRateLimitService() ports.RateLimitService {
+	return h.rateLimitService
+}
+
 func (h *AuthHandler) RegisterWithEmail(c *fiber.Ctx) error {
 	var body struct {
 		Email    string `json:"email"`
@@ -229,28 +231,25 @@ func (h *AuthHandler) RegisterWithEmail(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	// Validate required fields
 	if body.Email == "" || body.Password == "" || body.Username == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email, password and username are required",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Email, password and username are required")
 	}
 
-	user, token, err := h.authService.RegisterWithEmail(c.Context(), body.Email, body.Password, body.Username)
+	user, token, err := h.authService.RegisterWithEmail(middleware.ClientContext(c), body.Email, body.Password, body.Username)
 	if err != nil {
-		// Check if the error is a duplicate key error
-		if strings.Contains(err.Error(), "E11000 duplicate key error") {
+		// A duplicate key error from the insert means a parallel request may
+		// have registered the same user
+		if mongo.IsDuplicateKeyError(err) {
 			// Check if the user was actually created (race condition where one request succeeded)
-			existingUser, checkErr := h.userService.GetUserByEmail(c.Context(), body.Email)
+			existingUser, checkErr := h.userService.GetUserByEmail(c.UserContext(), body.Email)
 			if checkErr == nil && existingUser != nil {
 				// User exists and was created by the parallel request
 				// Generate a new session token for this user
-				loginUser, newToken, loginErr := h.authService.LoginWithEmail(c.Context(), body.Email, body.Password)
+				loginUser, newToken, loginErr := h.authService.LoginWithEmail(middleware.ClientContext(c), body.Email, body.Password)
 				if loginErr == nil {
 					// Set the cookie and return success
 					cookie := fiber.Cookie{
@@ -271,23 +270,11 @@ func (h *AuthHandler) RegisterWithEmail(c *fiber.Ctx) error {
 					})
 				}
 			}
-
-			// If we couldn't verify the user exists, return the appropriate error
-			if strings.Contains(err.Error(), "email_1") {
-				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-					"error": "Email already registered",
-				})
-			} else if strings.Contains(err.Error(), "username_1") {
-				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-					"error": "Username already taken",
-				})
-			}
 		}
 
-		log.Printf("[AUTH] Registration error: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		// Domain errors such as an email already registered carry their own
+		// status and message
+		return err
 	}
 
 	// Set secure HTTP-only cookie
@@ -316,24 +303,30 @@ func (h *AuthHandler) LoginWithEmail(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
 	// Validate required fields
 	if body.Email == "" || body.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email and password are required",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Email and password are required")
 	}
 
-	user, token, err := h.authService.LoginWithEmail(c.Context(), body.Email, body.Password)
+	// Lock out by account as well as by IP, so a spread-out attack on one
+	// password still runs into the backoff
+	lockoutSubject := "email:" + strings.ToLower(strings.TrimSpace(body.Email))
+	if err := checkLockout(c, h.rateLimitService, lockoutSubject); err != nil {
+		return err
+	}
+
+	user, token, err := h.authService.LoginWithEmail(middleware.ClientContext(c), body.Email, body.Password)
+	metrics.ObserveAuth(metrics.AuthMethodEmail, err == nil)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid email or password",
-		})
+		if lockErr := recordFailedSignIn(c, h.rateLimitService, lockoutSubject); lockErr != nil {
+			return lockErr
+		}
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
 	}
+	recordSignIn(c, h.rateLimitService, lockoutSubject)
 
 	// Set secure HTTP-only cookie
 	cookie := fiber.Cookie{
@@ -352,3 +345,46 @@ func (h *AuthHandler) LoginWithEmail(c *fiber.Ctx) error {
 		"redirect": "/feed",
 	})
 }
+
+// checkLockout returns a 429 error while sign-in for subject is locked. A
+// failure to check is logged and lets the attempt through.
+func checkLockout(c *fiber.Ctx, limits ports.RateLimitService, subject string) error {
+	until, err := limits.CheckLockout(c.UserContext(), subject)
+	if errors.Is(err, domain.ErrAccountLocked) {
+		return accountLocked(c, until)
+	}
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "could not check lockout", "error", err)
+	}
+	return nil
+}
+
+// recordFailedSignIn counts a failed sign-in for subject, returning a 429
+// error if this failure locked it
+func recordFailedSignIn(c *fiber.Ctx, limits ports.RateLimitService, subject string) error {
+	until, err := limits.RecordFailure(c.UserContext(), subject)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "could not record failed sign-in", "error", err)
+	}
+	if !until.IsZero() {
+		return accountLocked(c, until)
+	}
+	return nil
+}
+
+// recordSignIn clears subject's failed sign-ins after a successful one
+func recordSignIn(c *fiber.Ctx, limits ports.RateLimitService, subject string) {
+	if err := limits.RecordSuccess(c.UserContext(), subject); err != nil {
+		slog.ErrorContext(c.UserContext(), "could not clear failed sign-ins", "error", err)
+	}
+}
+
+// accountLocked responds 429 with a Retry-After header while sign-in is locked
+func accountLocked(c *fiber.Ctx, until time.Time) error {
+	seconds := int(math.Ceil(time.Until(until).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return fiber.NewError(fiber.StatusTooManyRequests, domain.ErrAccountLocked.Error())
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CertificateHandler struct {
+	certificateService ports.CertificateService
+	userService        ports.UserService
+}
+
+func NewCertificateHandler(certificateService ports.CertificateService, userService ports.UserService) *CertificateHandler {
+	return &CertificateHandler{
+		certificateService: certificateService,
+		userService:        userService,
+	}
+}
+
+// Download renders the certificate of a proof NFT or proof request. The
+// format query parameter picks pdf (the default) or png.
+func (h *CertificateHandler) Download(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	format := domain.CertificateFormat(c.Query("format", string(domain.CertificatePDF)))
+	source := domain.CredentialSource(c.Params("source"))
+	data, err := h.certificateService.Render(c.UserContext(), user, source, c.Params("id"), format)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="proof-of-peacemaking-%s.%s"`, c.Params("id"), format))
+	return c.Send(data)
+}
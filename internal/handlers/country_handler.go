@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"proofofpeacemaking/internal/core/ports"
 
 	"github.com/gofiber/fiber/v2"
@@ -20,16 +21,12 @@ func NewCountryHandler(countryService ports.CountryService) *CountryHandler {
 // SearchCountries handles country search requests
 func (h *CountryHandler) SearchCountries(c *fiber.Ctx) error {
 	query := c.Query("search", "")
-	log.Printf("[COUNTRY] Searching countries with query: %s", query)
 
-	countries, err := h.countryService.SearchCountries(c.Context(), query)
+	countries, err := h.countryService.SearchCountries(c.UserContext(), query)
 	if err != nil {
-		log.Printf("[COUNTRY] Error searching countries: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to search countries",
-		})
+		return fmt.Errorf("failed to search countries: %w", err)
 	}
 
-	log.Printf("[COUNTRY] Found %d matches", len(countries))
+	slog.DebugContext(c.UserContext(), "searched countries", "query", query, "matches", len(countries))
 	return c.JSON(countries)
 }
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CredentialHandler struct {
+	credentialService ports.CredentialService
+	userService       ports.UserService
+}
+
+func NewCredentialHandler(credentialService ports.CredentialService, userService ports.UserService) *CredentialHandler {
+	return &CredentialHandler{
+		credentialService: credentialService,
+		userService:       userService,
+	}
+}
+
+// Issue returns the credential for a minted proof NFT or accepted proof
+// request, issuing it the first time either party asks
+func (h *CredentialHandler) Issue(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Source domain.CredentialSource `json:"source"`
+		ID     string                  `json:"id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	credential, err := h.credentialService.Issue(c.UserContext(), user, req.Source, req.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(credential)
+}
+
+// List returns the caller's credentials
+func (h *CredentialHandler) List(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := h.credentialService.List(c.UserContext(), user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(credentials)
+}
+
+// Download returns a credential's JWT as a file a wallet can import
+func (h *CredentialHandler) Download(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	credential, err := h.credentialService.Get(c.UserContext(), user, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, "application/jwt")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="proof-of-peacemaking-%s.jwt"`, credential.ID.Hex()))
+	return c.SendString(credential.JWT)
+}
+
+// Verify checks a credential JWT. Anyone may call it; an invalid credential
+// is a normal answer, not an error.
+func (h *CredentialHandler) Verify(c *fiber.Ctx) error {
+	var req struct {
+		JWT string `json:"jwt"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	return c.JSON(h.credentialService.Verify(c.UserContext(), req.JWT))
+}
+
+// DIDDocument serves the issuer's did:web document
+func (h *CredentialHandler) DIDDocument(c *fiber.Ctx) error {
+	return c.JSON(h.credentialService.DIDDocument(), "application/did+json")
+}
+
+// Context serves the JSON-LD context of the credential terms
+func (h *CredentialHandler) Context(c *fiber.Ctx) error {
+	return c.JSON(h.credentialService.Context(), "application/ld+json")
+}
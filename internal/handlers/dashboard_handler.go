@@ -1,11 +1,12 @@
 package handlers
 
 import (
+	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"strings"
 
-	"log"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,6 +16,7 @@ type DashboardHandler struct {
 	acknowledgementService ports.AcknowledgementService
 	userService            ports.UserService
 	proofNFTService        ports.ProofNFTService
+	pairingService         ports.ExpressionAcknowledgementService
 }
 
 func NewDashboardHandler(
@@ -22,95 +24,81 @@ func NewDashboardHandler(
 	acknowledgementService ports.AcknowledgementService,
 	userService ports.UserService,
 	proofNFTService ports.ProofNFTService,
+	pairingService ports.ExpressionAcknowledgementService,
 ) *DashboardHandler {
 	return &DashboardHandler{
 		expressionService:      expressionService,
 		acknowledgementService: acknowledgementService,
 		userService:            userService,
 		proofNFTService:        proofNFTService,
+		pairingService:         pairingService,
 	}
 }
 
 func (h *DashboardHandler) GetDashboard(c *fiber.Ctx) error {
-	log.Printf("[DASHBOARD] Starting dashboard handler")
-
 	// Get user identifier from context (set by auth middleware)
 	userIdentifier, ok := c.Locals("userAddress").(string)
 	if !ok {
-		log.Printf("[DASHBOARD] Error: User identifier not found in context")
+		slog.WarnContext(c.UserContext(), "user identifier not found in context")
 		return c.Redirect("/")
 	}
-	log.Printf("[DASHBOARD] Got user identifier: %s", userIdentifier)
 
 	// Get user by email or address
 	var user *domain.User
 	var err error
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		log.Printf("[DASHBOARD] Error getting user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user data",
-		})
+		return fmt.Errorf("failed to get user data: %w", err)
 	}
 
 	// Get user's expressions
-	expressions, err := h.expressionService.ListByUser(c.Context(), user.ID.Hex())
+	expressions, err := h.expressionService.ListByUser(c.UserContext(), user.ID.Hex())
 	if err != nil {
-		log.Printf("[DASHBOARD] Error fetching expressions: %v", err)
+		slog.ErrorContext(c.UserContext(), "failed to fetch expressions", "error", err)
 		expressions = []*domain.Expression{} // Use empty slice instead of failing
 	}
 
-	// Get acknowledgments received by user's expressions
-	totalAcksReceived := 0
-	uniqueAcknowledgers := make(map[string]bool)
-	for _, expr := range expressions {
-		acks, err := h.acknowledgementService.ListByExpression(c.Context(), expr.ID.Hex())
-		if err != nil {
-			log.Printf("[DASHBOARD] Error fetching acks for expression %s: %v", expr.ID.Hex(), err)
-			continue
-		}
-		for _, ack := range acks {
-			if ack.Status == domain.AcknowledgementStatusActive {
-				totalAcksReceived++
-				uniqueAcknowledgers[ack.Acknowledger] = true
-			}
-		}
-	}
-
-	// Get acknowledgments made by user
-	acknowledgementsMade, err := h.acknowledgementService.ListByUser(c.Context(), user.ID.Hex())
+	// Both sides of every acknowledgement the user is party to
+	pairings, err := h.pairingService.ListByUser(c.UserContext(), user.ID.Hex())
 	if err != nil {
-		log.Printf("[DASHBOARD] Error fetching acknowledgements: %v", err)
-		acknowledgementsMade = []*domain.Acknowledgement{} // Use empty slice instead of failing
+		slog.ErrorContext(c.UserContext(), "failed to fetch acknowledgement pairings", "error", err)
+		pairings = []*domain.ExpressionAcknowledgement{} // Use empty slice instead of failing
 	}
 
-	// Count acknowledgments by status
+	userID := user.ID.Hex()
+	totalAcksReceived := 0
+	uniqueAcknowledgers := make(map[string]bool)
+	acksMade := 0
 	activeAcks := 0
 	refutedAcks := 0
 	uniqueExpressionsAcked := make(map[string]bool)
 	uniqueCreatorsAcked := make(map[string]bool)
+	proofs := make(map[domain.NFTStatus]int)
 
-	for _, ack := range acknowledgementsMade {
-		uniqueExpressionsAcked[ack.ExpressionID] = true
+	for _, pairing := range pairings {
+		proofs[pairing.NFTStatus]++
 
-		// Get expression to find its creator
-		expr, err := h.expressionService.Get(c.Context(), ack.ExpressionID)
-		if err != nil {
-			log.Printf("[DASHBOARD] Error fetching expression %s: %v", ack.ExpressionID, err)
-			continue
+		if pairing.CreatorID == userID && pairing.AcknowledgementStatus == domain.AcknowledgementStatusActive {
+			totalAcksReceived++
+			uniqueAcknowledgers[pairing.AcknowledgerID] = true
 		}
-		uniqueCreatorsAcked[expr.Creator] = true
 
-		// Count by status
-		if ack.Status == domain.AcknowledgementStatusActive {
-			activeAcks++
-		} else if ack.Status == domain.AcknowledgementStatusRefuted {
-			refutedAcks++
+		if pairing.AcknowledgerID == userID {
+			acksMade++
+			uniqueExpressionsAcked[pairing.ExpressionID] = true
+			uniqueCreatorsAcked[pairing.CreatorID] = true
+
+			// Count by status
+			if pairing.AcknowledgementStatus == domain.AcknowledgementStatusActive {
+				activeAcks++
+			} else if pairing.AcknowledgementStatus == domain.AcknowledgementStatusRefuted {
+				refutedAcks++
+			}
 		}
 	}
 
@@ -122,13 +110,19 @@ func (h *DashboardHandler) GetDashboard(c *fiber.Ctx) error {
 	}
 
 	acknowledgementStats := fiber.Map{
-		"TotalAcknowledgements": len(acknowledgementsMade),
+		"TotalAcknowledgements": acksMade,
 		"ActiveAcks":            activeAcks,
 		"RefutedAcks":           refutedAcks,
 		"UniqueExpressions":     len(uniqueExpressionsAcked),
 		"UniqueCreators":        len(uniqueCreatorsAcked),
 	}
 
+	proofStats := fiber.Map{
+		"Requested": proofs[domain.NFTStatusRequested],
+		"Approved":  proofs[domain.NFTStatusApproved] + proofs[domain.NFTStatusMinting],
+		"Minted":    proofs[domain.NFTStatusMinted],
+	}
+
 	// Sort expressions by timestamp to get most recent
 	recentExpressions := expressions
 	if len(recentExpressions) > 5 {
@@ -140,44 +134,38 @@ func (h *DashboardHandler) GetDashboard(c *fiber.Ctx) error {
 		"User":                 fiber.Map{"Email": user.Email, "Address": user.Address},
 		"ExpressionStats":      expressionStats,
 		"AcknowledgementStats": acknowledgementStats,
+		"ProofStats":           proofStats,
 		"RecentExpressions":    recentExpressions,
 	}
 
-	// log.Printf("[DASHBOARD] Data being passed to template: %+v", data)
 	return c.Render("dashboard", data, "")
 }
 
 func (h *DashboardHandler) GetExpressions(c *fiber.Ctx) error {
-	log.Printf("[DASHBOARD] Starting expressions handler")
-
 	// Get user identifier from context (set by auth middleware)
 	userIdentifier, ok := c.Locals("userAddress").(string)
 	if !ok {
-		log.Printf("[DASHBOARD] Error: User identifier not found in context")
+		slog.WarnContext(c.UserContext(), "user identifier not found in context")
 		return c.Redirect("/")
 	}
-	log.Printf("[DASHBOARD] Got user identifier: %s", userIdentifier)
 
 	// Get user by email or address
 	var user *domain.User
 	var err error
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		log.Printf("[DASHBOARD] Error getting user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user data",
-		})
+		return fmt.Errorf("failed to get user data: %w", err)
 	}
 
 	// Get user's expressions
-	expressions, err := h.expressionService.ListByUser(c.Context(), user.ID.Hex())
+	expressions, err := h.expressionService.ListByUser(c.UserContext(), user.ID.Hex())
 	if err != nil {
-		log.Printf("[DASHBOARD] Error fetching expressions: %v", err)
+		slog.ErrorContext(c.UserContext(), "failed to fetch expressions", "error", err)
 		expressions = []*domain.Expression{} // Use empty slice instead of failing
 	}
 
@@ -191,36 +179,30 @@ func (h *DashboardHandler) GetExpressions(c *fiber.Ctx) error {
 }
 
 func (h *DashboardHandler) GetAcknowledgements(c *fiber.Ctx) error {
-	log.Printf("[DASHBOARD] Starting acknowledgements handler")
-
 	// Get user identifier from context (set by auth middleware)
 	userIdentifier, ok := c.Locals("userAddress").(string)
 	if !ok {
-		log.Printf("[DASHBOARD] Error: User identifier not found in context")
+		slog.WarnContext(c.UserContext(), "user identifier not found in context")
 		return c.Redirect("/")
 	}
-	log.Printf("[DASHBOARD] Got user identifier: %s", userIdentifier)
 
 	// Get user by email or address
 	var user *domain.User
 	var err error
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		log.Printf("[DASHBOARD] Error getting user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user data",
-		})
+		return fmt.Errorf("failed to get user data: %w", err)
 	}
 
 	// Get acknowledgements made by user
-	acknowledgements, err := h.acknowledgementService.ListByUser(c.Context(), user.ID.Hex())
+	acknowledgements, err := h.acknowledgementService.ListByUser(c.UserContext(), user.ID.Hex())
 	if err != nil {
-		log.Printf("[DASHBOARD] Error fetching acknowledgements: %v", err)
+		slog.ErrorContext(c.UserContext(), "failed to fetch acknowledgements", "error", err)
 		acknowledgements = []*domain.Acknowledgement{} // Use empty slice instead of failing
 	}
 
@@ -231,12 +213,9 @@ func (h *DashboardHandler) GetAcknowledgements(c *fiber.Ctx) error {
 	}
 
 	// Get all expressions in one query
-	expressions, err := h.expressionService.GetMultiple(c.Context(), expressionIDs)
+	expressions, err := h.expressionService.GetMultiple(c.UserContext(), expressionIDs)
 	if err != nil {
-		log.Printf("[DASHBOARD] Error fetching expressions: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch expressions",
-		})
+		return fmt.Errorf("failed to fetch expressions: %w", err)
 	}
 
 	data := fiber.Map{
@@ -259,9 +238,9 @@ func (h *DashboardHandler) GetDashboardStats(c *fiber.Ctx) error {
 	var err error
 
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
@@ -271,15 +250,15 @@ func (h *DashboardHandler) GetDashboardStats(c *fiber.Ctx) error {
 	}
 
 	// Get user's expressions
-	expressions, err := h.expressionService.ListByUser(c.Context(), user.ID.Hex())
+	expressions, err := h.expressionService.ListByUser(c.UserContext(), user.ID.Hex())
 	if err != nil {
 		return c.Render("error", fiber.Map{
 			"Error": "Failed to get expressions",
 		})
 	}
 
-	// Get user's acknowledgments
-	acknowledgments, err := h.acknowledgementService.ListByUser(c.Context(), user.ID.Hex())
+	// Get the pairings of the user's acknowledgments
+	pairings, err := h.pairingService.ListByUser(c.UserContext(), user.ID.Hex())
 	if err != nil {
 		return c.Render("error", fiber.Map{
 			"Error": "Failed to get acknowledgments",
@@ -287,29 +266,30 @@ func (h *DashboardHandler) GetDashboardStats(c *fiber.Ctx) error {
 	}
 
 	// Count acknowledgments by status
+	totalAcks := 0
 	ackStats := make(map[string]int)
 	uniqueExpressions := make(map[string]bool)
 	uniqueCreators := make(map[string]bool)
 
-	for _, ack := range acknowledgments {
-		// Count by status
-		ackStats[string(ack.Status)]++
+	for _, pairing := range pairings {
+		if pairing.AcknowledgerID != user.ID.Hex() {
+			continue
+		}
+		totalAcks++
 
-		// Track unique expressions
-		uniqueExpressions[ack.ExpressionID] = true
+		// Count by status
+		ackStats[string(pairing.AcknowledgementStatus)]++
 
-		// Get expression to track creator
-		expr, err := h.expressionService.Get(c.Context(), ack.ExpressionID)
-		if err == nil && expr != nil {
-			uniqueCreators[expr.CreatorAddress] = true
-		}
+		// Track unique expressions and their creators
+		uniqueExpressions[pairing.ExpressionID] = true
+		uniqueCreators[pairing.CreatorID] = true
 	}
 
 	data := fiber.Map{
 		"Title":             "Dashboard",
 		"User":              fiber.Map{"Email": user.Email, "Address": user.Address},
 		"Expressions":       expressions,
-		"TotalAcks":         len(acknowledgments),
+		"TotalAcks":         totalAcks,
 		"ActiveAcks":        ackStats[string(domain.AcknowledgementStatusActive)],
 		"RefutedAcks":       ackStats[string(domain.AcknowledgementStatusRefuted)],
 		"UniqueExpressions": len(uniqueExpressions),
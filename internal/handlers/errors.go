@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// problem is an RFC 7807 problem details body. Error repeats Detail for
+// clients written against the older {"error": "..."} responses.
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	Error     string `json:"error"`
+}
+
+// ErrorHandler renders every error a handler returns as problem+json. Domain
+// errors map to a status by kind and show their message; anything else is a
+// 500 whose details stay in the log.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status, detail := classifyError(err)
+	if status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "error handling request", "method", c.Method(), "path", c.Path(), "error", err)
+	}
+
+	return c.Status(status).JSON(problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.OriginalURL(),
+		RequestID: logging.RequestID(c.UserContext()),
+		Error:     detail,
+	}, problemContentType)
+}
+
+// classifyError picks the status and user-facing detail for err
+func classifyError(err error) (int, string) {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code, fiberErr.Message
+	}
+
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		status = fiber.StatusConflict
+	case errors.Is(err, domain.ErrValidation):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden):
+		status = fiber.StatusForbidden
+	case errors.Is(err, domain.ErrUnauthorized):
+		status = fiber.StatusUnauthorized
+	}
+
+	var domainErr *domain.Error
+	if status != fiber.StatusInternalServerError && errors.As(err, &domainErr) {
+		return status, domainErr.Message()
+	}
+	return fiber.StatusInternalServerError, "An unexpected error occurred"
+}
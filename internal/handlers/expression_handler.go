@@ -1,11 +1,12 @@
 package handlers
 
 import (
+	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"time"
 
-	"log"
+	"log/slog"
 
 	"strings"
 
@@ -40,49 +41,37 @@ func (h *ExpressionHandler) Create(c *fiber.Ctx) error {
 	// Parse multipart form
 	form, err := c.MultipartForm()
 	if err != nil {
-		log.Printf("[EXPRESSION] Error parsing form: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid form data",
-		})
+		slog.InfoContext(c.UserContext(), "invalid expression form", "error", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid form data")
 	}
 
 	// Log form contents (only field names and sizes)
-	log.Printf("[EXPRESSION] Form fields: %v", getKeys(form.Value))
-	log.Printf("[EXPRESSION] File fields: %v", getKeys(form.File))
-	log.Printf("[EXPRESSION] Number of image files: %d", len(form.File["imageContent"]))
-	log.Printf("[EXPRESSION] Number of audio files: %d", len(form.File["audioContent"]))
-	log.Printf("[EXPRESSION] Number of video files: %d", len(form.File["videoContent"]))
-	for key, files := range form.File {
-		for _, file := range files {
-			log.Printf("[EXPRESSION] File %s: name=%s, size=%d", key, file.Filename, file.Size)
-		}
-	}
+	slog.DebugContext(c.UserContext(), "expression form received",
+		"fields", getKeys(form.Value),
+		"files", getKeys(form.File),
+		"images", len(form.File["imageContent"]),
+		"audio", len(form.File["audioContent"]),
+		"video", len(form.File["videoContent"]),
+	)
 
 	// Get user from context
 	userIdentifier := c.Locals("userAddress").(string)
-	log.Printf("[EXPRESSION] Looking up user with identifier: %s", userIdentifier)
 
 	// Get user by email or address
 	var user *domain.User
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		log.Printf("[EXPRESSION] Error getting user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user",
-		})
+		return fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		log.Printf("[EXPRESSION] User not found for identifier: %s", userIdentifier)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		slog.WarnContext(c.UserContext(), "user not found", "identifier", userIdentifier)
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
-	log.Printf("[EXPRESSION] Found user with ID: %s", user.ID.Hex())
 
 	// Initialize content map
 	content := make(map[string]string)
@@ -93,7 +82,8 @@ func (h *ExpressionHandler) Create(c *fiber.Ctx) error {
 		Creator:        user.ID.Hex(),
 		CreatorAddress: userIdentifier,
 		Content:        content,
-		Status:         "pending",
+		Status:         domain.ExpressionStatusPending,
+		Locale:         expressionLocale(c, form.Value["locale"]),
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -108,20 +98,14 @@ func (h *ExpressionHandler) Create(c *fiber.Ctx) error {
 		imageFile := imageFiles[0]
 		file, err := imageFile.Open()
 		if err != nil {
-			log.Printf("[EXPRESSION] Error opening image file: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to process image",
-			})
+			return fmt.Errorf("failed to process image: %w", err)
 		}
 		defer file.Close()
 
 		// Upload to R2
-		key, err := h.expressionService.UploadMedia(c.Context(), expression.ID.Hex(), "image", file, imageFile.Filename)
+		key, err := h.expressionService.UploadMedia(c.UserContext(), expression.ID.Hex(), "image", file, imageFile.Filename)
 		if err != nil {
-			log.Printf("[EXPRESSION] Error uploading image to R2: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to upload image",
-			})
+			return fmt.Errorf("failed to upload image: %w", err)
 		}
 		content["image"] = key
 	}
@@ -131,20 +115,14 @@ func (h *ExpressionHandler) Create(c *fiber.Ctx) error {
 		audioFile := audioFiles[0]
 		file, err := audioFile.Open()
 		if err != nil {
-			log.Printf("[EXPRESSION] Error opening audio file: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to process audio",
-			})
+			return fmt.Errorf("failed to process audio: %w", err)
 		}
 		defer file.Close()
 
 		// Upload to R2
-		key, err := h.expressionService.UploadMedia(c.Context(), expression.ID.Hex(), "audio", file, audioFile.Filename)
+		key, err := h.expressionService.UploadMedia(c.UserContext(), expression.ID.Hex(), "audio", file, audioFile.Filename)
 		if err != nil {
-			log.Printf("[EXPRESSION] Error uploading audio to R2: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to upload audio",
-			})
+			return fmt.Errorf("failed to upload audio: %w", err)
 		}
 		content["audio"] = key
 	}
@@ -154,34 +132,26 @@ func (h *ExpressionHandler) Create(c *fiber.Ctx) error {
 		videoFile := videoFiles[0]
 		file, err := videoFile.Open()
 		if err != nil {
-			log.Printf("[EXPRESSION] Error opening video file: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to process video",
-			})
+			return fmt.Errorf("failed to process video: %w", err)
 		}
 		defer file.Close()
 
 		// Upload to R2
-		key, err := h.expressionService.UploadMedia(c.Context(), expression.ID.Hex(), "video", file, videoFile.Filename)
+		key, err := h.expressionService.UploadMedia(c.UserContext(), expression.ID.Hex(), "video", file, videoFile.Filename)
 		if err != nil {
-			log.Printf("[EXPRESSION] Error uploading video to R2: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to upload video",
-			})
+			return fmt.Errorf("failed to upload video: %w", err)
 		}
 		content["video"] = key
 	}
 
 	// Call service to create expression
-	if err := h.expressionService.Create(c.Context(), expression); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create expression",
-		})
+	if err := h.expressionService.Create(c.UserContext(), expression); err != nil {
+		return err
 	}
 
 	// After creating the expression and uploading all media, update statistics
-	if err := h.statsService.UpdateStatisticsAfterExpression(c.Context()); err != nil {
-		log.Printf("[EXPRESSION] Warning: Failed to update statistics: %v", err)
+	if err := h.statsService.UpdateStatisticsAfterExpression(c.UserContext()); err != nil {
+		slog.WarnContext(c.UserContext(), "failed to update statistics", "error", err)
 		// Don't return error here, as the expression was created successfully
 	}
 
@@ -189,22 +159,32 @@ func (h *ExpressionHandler) Create(c *fiber.Ctx) error {
 }
 
 func (h *ExpressionHandler) List(c *fiber.Ctx) error {
-	expressions, err := h.expressionService.List(c.Context())
+	expressions, err := h.expressionService.List(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch expressions",
-		})
+		return fmt.Errorf("failed to fetch expressions: %w", err)
 	}
 	return c.JSON(expressions)
 }
 
 func (h *ExpressionHandler) Get(c *fiber.Ctx) error {
 	id := c.Params("id")
-	expression, err := h.expressionService.Get(c.Context(), id)
+	expression, err := h.expressionService.Get(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch expression",
-		})
+		return err
 	}
 	return c.JSON(expression)
 }
+
+// expressionLocale returns the author's language from the form, falling back to
+// the first Accept-Language entry
+func expressionLocale(c *fiber.Ctx, formLocale []string) string {
+	if len(formLocale) > 0 && formLocale[0] != "" {
+		return formLocale[0]
+	}
+	accept := c.Get(fiber.HeaderAcceptLanguage)
+	if accept == "" {
+		return ""
+	}
+	first := strings.SplitN(accept, ",", 2)[0]
+	return strings.TrimSpace(strings.SplitN(first, ";", 2)[0])
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"log"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"strings"
@@ -38,21 +38,21 @@ func (h *FeedHandler) HandleFeed(c *fiber.Ctx) error {
 
 	// Check if the identifier is an email or wallet address
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		log.Printf("Error getting user: %v", err)
+		slog.ErrorContext(c.UserContext(), "failed to get user", "error", err)
 		return c.Render("error", fiber.Map{
 			"Error": "Failed to get user data",
 		})
 	}
 
-	activities, err := h.feedService.GetFeed(c.Context())
+	activities, err := h.feedService.GetFeed(c.UserContext())
 	if err != nil {
-		log.Printf("Error getting feed: %v", err)
+		slog.ErrorContext(c.UserContext(), "failed to get feed", "error", err)
 		return c.Render("error", fiber.Map{
 			"Error": "Failed to load feed",
 		})
@@ -101,6 +101,5 @@ func (h *FeedHandler) HandleFeed(c *fiber.Ctx) error {
 		"Expressions": expressions,
 	}
 
-	// log.Printf("[FEED] Data being passed to template: %+v", data)
 	return c.Render("feed", data)
 }
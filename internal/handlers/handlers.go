@@ -1,6 +1,12 @@
 package handlers
 
-import "proofofpeacemaking/internal/core/ports"
+import (
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 type Handlers struct {
 	Auth            *AuthHandler
@@ -15,6 +21,19 @@ type Handlers struct {
 	WebAuthn        *WebAuthnHandler
 	Statistics      *StatisticsHandler
 	Account         *AccountHandler
+	Session         *SessionHandler
+	APIToken        *APITokenHandler
+	Admin           *AdminHandler
+	Moderation      *ModerationHandler
+	Relayer         *RelayerHandler
+	Subsidy         *SubsidyHandler
+	Anchor          *AnchorHandler
+	Credential      *CredentialHandler
+	Certificate     *CertificateHandler
+	Verification    *VerificationHandler
+	Invitation      *InvitationHandler
+	Reply           *ReplyHandler
+	Health          *HealthHandler
 }
 
 func NewHandlers(
@@ -23,23 +42,76 @@ func NewHandlers(
 	expressionService ports.ExpressionService,
 	acknowledgementService ports.AcknowledgementService,
 	proofNFTService ports.ProofNFTService,
+	pairingService ports.ExpressionAcknowledgementService,
 	feedService ports.FeedService,
 	statisticsService ports.StatisticsService,
 	webAuthnService ports.WebAuthnService,
 	sessionService ports.SessionService,
 	newsletterService ports.NewsletterService,
+	notificationService ports.NotificationService,
+	apiTokenService ports.APITokenService,
+	moderationService ports.ModerationService,
+	relayerService ports.RelayerService,
+	subsidyService ports.SubsidyService,
+	anchorService ports.AnchorService,
+	credentialService ports.CredentialService,
+	certificateService ports.CertificateService,
+	verificationService ports.VerificationService,
+	invitationService ports.InvitationService,
+	replyService ports.ReplyService,
+	siteURL string,
+	rateLimitService ports.RateLimitService,
+	healthChecks []ports.HealthCheck,
 ) *Handlers {
 	return &Handlers{
-		Auth:            NewAuthHandler(authService, userService),
+		Auth:            NewAuthHandler(authService, userService, rateLimitService),
 		User:            NewUserHandler(userService, statisticsService),
 		Expression:      NewExpressionHandler(expressionService, userService, statisticsService),
 		Acknowledgement: NewAcknowledgementHandler(acknowledgementService, userService, expressionService, statisticsService),
-		ProofNFT:        NewProofNFTHandler(proofNFTService),
+		ProofNFT:        NewProofNFTHandler(proofNFTService, userService),
 		Feed:            NewFeedHandler(feedService, userService),
 		Statistics:      NewStatisticsHandler(statisticsService),
 		Account:         NewAccountHandler(userService, authService, statisticsService),
-		WebAuthn:        NewWebAuthnHandler(webAuthnService, sessionService, userService),
+		WebAuthn:        NewWebAuthnHandler(webAuthnService, sessionService, userService, rateLimitService),
 		Newsletter:      NewNewsletterHandler(newsletterService),
-		Dashboard:       NewDashboardHandler(expressionService, acknowledgementService, userService, proofNFTService),
+		Notification:    NewNotificationHandler(notificationService),
+		Session:         NewSessionHandler(sessionService, userService),
+		APIToken:        NewAPITokenHandler(apiTokenService, userService),
+		Admin:           NewAdminHandler(userService, sessionService),
+		Moderation:      NewModerationHandler(moderationService, userService),
+		Relayer:         NewRelayerHandler(relayerService, userService),
+		Subsidy:         NewSubsidyHandler(subsidyService, userService),
+		Anchor:          NewAnchorHandler(anchorService),
+		Credential:      NewCredentialHandler(credentialService, userService),
+		Certificate:     NewCertificateHandler(certificateService, userService),
+		Verification:    NewVerificationHandler(verificationService, userService, siteURL),
+		Invitation:      NewInvitationHandler(invitationService, expressionService, userService),
+		Reply:           NewReplyHandler(replyService, userService),
+		Dashboard:       NewDashboardHandler(expressionService, acknowledgementService, userService, proofNFTService, pairingService),
+		Health:          NewHealthHandler(healthChecks),
+	}
+}
+
+// currentUser loads the user the auth middleware identified, or nil if there is none
+func currentUser(c *fiber.Ctx, users ports.UserService) (*domain.User, error) {
+	identifier, _ := c.Locals("userAddress").(string)
+	if identifier == "" {
+		return nil, nil
+	}
+	if strings.Contains(identifier, "@") {
+		return users.GetUserByEmail(c.UserContext(), identifier)
+	}
+	return users.GetUserByAddress(c.UserContext(), identifier)
+}
+
+// requireUser is currentUser for routes behind RequireAuth, where a missing user is an error
+func requireUser(c *fiber.Ctx, users ports.UserService) (*domain.User, error) {
+	user, err := currentUser(c, users)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.NotFound("user not found")
 	}
+	return user, nil
 }
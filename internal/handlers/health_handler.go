@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// readinessTimeout bounds how long a readiness probe waits on each dependency
+const readinessTimeout = 3 * time.Second
+
+type HealthHandler struct {
+	checks []ports.HealthCheck
+}
+
+func NewHealthHandler(checks []ports.HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Liveness reports that the process is up and serving requests
+func (h *HealthHandler) Liveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Readiness reports whether every dependency answers, so load balancers can
+// hold traffic back while MongoDB or storage is unreachable
+func (h *HealthHandler) Readiness(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), readinessTimeout)
+	defer cancel()
+
+	ready := true
+	checks := fiber.Map{}
+	for _, check := range h.checks {
+		if err := check.Check(ctx); err != nil {
+			slog.WarnContext(ctx, "readiness check failed", "check", check.Name(), "error", err)
+			checks[check.Name()] = "unavailable"
+			ready = false
+			continue
+		}
+		checks[check.Name()] = "ok"
+	}
+
+	if !ready {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status": "unavailable",
+			"checks": checks,
+		})
+	}
+	return c.JSON(fiber.Map{
+		"status": "ok",
+		"checks": checks,
+	})
+}
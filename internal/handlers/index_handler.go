@@ -19,7 +19,7 @@ func NewIndexHandler(statsService ports.StatisticsService) *IndexHandler {
 // ServeIndexPage renders the home page
 func (h *IndexHandler) ServeIndexPage(c *fiber.Ctx) error {
 	// Get latest statistics for the home page
-	stats, err := h.statsService.GetLatestStats(c.Context())
+	stats, err := h.statsService.GetLatestStats(c.UserContext())
 	if err != nil {
 		// If we can't get stats, just log it but don't fail the page load
 		stats = nil
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"proofofpeacemaking/internal/core/certificate"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvitationHandler struct {
+	invitationService ports.InvitationService
+	expressionService ports.ExpressionService
+	userService       ports.UserService
+}
+
+func NewInvitationHandler(invitationService ports.InvitationService, expressionService ports.ExpressionService, userService ports.UserService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+		expressionService: expressionService,
+		userService:       userService,
+	}
+}
+
+// Create invites someone to acknowledge one of the caller's expressions
+func (h *InvitationHandler) Create(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		ExpressionID string `json:"expressionId"`
+		Invitee      string `json:"invitee"`
+		Message      string `json:"message"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	invitation, err := h.invitationService.Invite(c.UserContext(), user, body.ExpressionID, body.Invitee, body.Message)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(invitation)
+}
+
+func (h *InvitationHandler) ListSent(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := h.invitationService.ListSent(c.UserContext(), user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(invitations)
+}
+
+func (h *InvitationHandler) ListReceived(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := h.invitationService.ListReceived(c.UserContext(), user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(invitations)
+}
+
+// Accept accepts the invitation behind a signed link token and sends the
+// invitee on to acknowledge the expression
+func (h *InvitationHandler) Accept(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	invitation, err := h.invitationService.Accept(c.UserContext(), user, body.Token)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"invitation": invitation,
+		"redirect":   "/feed",
+	})
+}
+
+// ServeInvitePage renders the page an invite link opens. Visitors without an
+// account are asked to register first; the page reloads signed in and lets
+// the invitee accept.
+func (h *InvitationHandler) ServeInvitePage(c *fiber.Ctx) error {
+	user, err := currentUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+	data := fiber.Map{
+		"Title": "Invitation",
+		"Token": c.Params("token"),
+	}
+	if user != nil {
+		data["User"] = fiber.Map{"Email": user.Email, "Address": user.Address}
+	}
+
+	invitation, err := h.invitationService.Open(c.UserContext(), c.Params("token"))
+	if errors.Is(err, domain.ErrNotFound) {
+		data["Error"] = "This invitation link is not valid."
+		return c.Status(fiber.StatusNotFound).Render("invite", data, "")
+	}
+	if err != nil {
+		return err
+	}
+	data["Invitation"] = invitation
+
+	expression, err := h.expressionService.Get(c.UserContext(), invitation.ExpressionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		data["Error"] = "The expression you were invited to acknowledge is no longer available."
+		return c.Status(fiber.StatusNotFound).Render("invite", data, "")
+	}
+	if err != nil {
+		return err
+	}
+	data["Excerpt"] = certificate.Excerpt(expression.Content["text"])
+
+	inviter, err := h.userService.GetUserByID(c.UserContext(), invitation.CreatorID)
+	if err != nil {
+		return err
+	}
+	data["Inviter"] = "Someone"
+	if inviter != nil {
+		if inviter.DisplayName != "" {
+			data["Inviter"] = inviter.DisplayName
+		} else if inviter.Username != "" {
+			data["Inviter"] = inviter.Username
+		}
+	}
+
+	if invitation.InviteeKind == domain.InviteeEmail {
+		data["InviteeEmail"] = invitation.Invitee
+	}
+	if user != nil {
+		data["IsInvitee"] = invitation.IsFor(user)
+	}
+	return c.Render("invite", data, "")
+}
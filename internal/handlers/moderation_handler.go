@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ModerationHandler struct {
+	moderationService ports.ModerationService
+	userService       ports.UserService
+}
+
+func NewModerationHandler(moderationService ports.ModerationService, userService ports.UserService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+		userService:       userService,
+	}
+}
+
+// CreateReport lets any signed-in user report an expression or acknowledgement
+func (h *ModerationHandler) CreateReport(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		ContentType domain.ContentType  `json:"contentType"`
+		ContentID   string              `json:"contentId"`
+		Reason      domain.ReportReason `json:"reason"`
+		Details     string              `json:"details"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if len(req.Details) > 1000 {
+		return fiber.NewError(fiber.StatusBadRequest, "details must be at most 1000 characters")
+	}
+
+	report, err := h.moderationService.Report(c.UserContext(), user, req.ContentType, req.ContentID, req.Reason, strings.TrimSpace(req.Details))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// GetQueue lists moderation cases, optionally filtered by ?status=OPEN,ESCALATED
+func (h *ModerationHandler) GetQueue(c *fiber.Ctx) error {
+	var statuses []domain.CaseStatus
+	if status := c.Query("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			statuses = append(statuses, domain.CaseStatus(strings.ToUpper(strings.TrimSpace(s))))
+		}
+	}
+
+	cases, err := h.moderationService.ListQueue(c.UserContext(), statuses)
+	if err != nil {
+		return fmt.Errorf("failed to load moderation queue: %w", err)
+	}
+
+	return c.JSON(cases)
+}
+
+// GetCase returns a case with its reports and moderation log
+func (h *ModerationHandler) GetCase(c *fiber.Ctx) error {
+	caseID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case ID")
+	}
+
+	moderationCase, reports, entries, err := h.moderationService.GetCase(c.UserContext(), caseID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"case":    moderationCase,
+		"reports": reports,
+		"log":     entries,
+	})
+}
+
+// ClaimCase assigns an open case to the current moderator
+func (h *ModerationHandler) ClaimCase(c *fiber.Ctx) error {
+	return h.caseAction(c, func(moderator *domain.User, caseID primitive.ObjectID, req caseActionRequest) (*domain.ModerationCase, error) {
+		return h.moderationService.Claim(c.UserContext(), moderator, caseID)
+	})
+}
+
+// EscalateCase hands a claimed case over to the admins
+func (h *ModerationHandler) EscalateCase(c *fiber.Ctx) error {
+	return h.caseAction(c, func(moderator *domain.User, caseID primitive.ObjectID, req caseActionRequest) (*domain.ModerationCase, error) {
+		return h.moderationService.Escalate(c.UserContext(), moderator, caseID, req.Note)
+	})
+}
+
+// ResolveCase applies hide, restore, warn_user, suspend_user or dismiss and closes the case
+func (h *ModerationHandler) ResolveCase(c *fiber.Ctx) error {
+	return h.caseAction(c, func(moderator *domain.User, caseID primitive.ObjectID, req caseActionRequest) (*domain.ModerationCase, error) {
+		suspendFor := time.Duration(req.SuspendDays) * 24 * time.Hour
+		return h.moderationService.Resolve(c.UserContext(), moderator, caseID, req.Action, req.Note, suspendFor)
+	})
+}
+
+// ReopenCase puts a resolved case back in the queue
+func (h *ModerationHandler) ReopenCase(c *fiber.Ctx) error {
+	return h.caseAction(c, func(moderator *domain.User, caseID primitive.ObjectID, req caseActionRequest) (*domain.ModerationCase, error) {
+		return h.moderationService.Reopen(c.UserContext(), moderator, caseID, req.Note)
+	})
+}
+
+type caseActionRequest struct {
+	Action      domain.ModerationAction `json:"action"`
+	Note        string                  `json:"note"`
+	SuspendDays int                     `json:"suspendDays"`
+}
+
+// caseAction parses the shared parts of a case transition request and renders the result
+func (h *ModerationHandler) caseAction(c *fiber.Ctx, action func(*domain.User, primitive.ObjectID, caseActionRequest) (*domain.ModerationCase, error)) error {
+	moderator, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	caseID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid case ID")
+	}
+
+	var req caseActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	moderationCase, err := action(moderator, caseID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(moderationCase)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"proofofpeacemaking/internal/core/ports"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,16 +28,16 @@ func (h *NewsletterHandler) HandleNewsletterRegistration(c *fiber.Ctx) error {
 	}
 
 	if err := json.Unmarshal(c.Body(), &newsletterData); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse JSON"})
+		return fiber.NewError(fiber.StatusBadRequest, "cannot parse JSON")
 	}
 
 	if newsletterData.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "All fields are required"})
+		return fiber.NewError(fiber.StatusBadRequest, "All fields are required")
 	}
 
-	err := h.newsletterService.SendContactEmail(c.Context(), newsletterData.Email)
+	err := h.newsletterService.SendContactEmail(c.UserContext(), newsletterData.Email)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Error registering email"})
+		return fmt.Errorf("error registering email: %w", err)
 	}
 
 	return c.SendStatus(fiber.StatusOK)
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"proofofpeacemaking/internal/core/ports"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,11 +20,9 @@ func NewNotificationHandler(notificationService ports.NotificationService) *Noti
 func (h *NotificationHandler) GetUserNotifications(c *fiber.Ctx) error {
 	userAddress := c.Locals("userAddress").(string)
 
-	notifications, err := h.notificationService.GetUserNotifications(c.Context(), userAddress)
+	notifications, err := h.notificationService.GetUserNotifications(c.UserContext(), userAddress)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch notifications",
-		})
+		return fmt.Errorf("failed to fetch notifications: %w", err)
 	}
 
 	return c.JSON(notifications)
@@ -33,11 +32,9 @@ func (h *NotificationHandler) MarkAsRead(c *fiber.Ctx) error {
 	userAddress := c.Locals("userAddress").(string)
 	notificationID := c.Params("id")
 
-	err := h.notificationService.MarkNotificationAsRead(c.Context(), userAddress, notificationID)
+	err := h.notificationService.MarkNotificationAsRead(c.UserContext(), userAddress, notificationID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to mark notification as read",
-		})
+		return err
 	}
 
 	return c.SendStatus(fiber.StatusOK)
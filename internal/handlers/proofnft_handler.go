@@ -1,42 +1,92 @@
 package handlers
 
 import (
-	"proofofpeacemaking/internal/core/ports"
-
 	"github.com/gofiber/fiber/v2"
+	"proofofpeacemaking/internal/core/ports"
 )
 
 type ProofNFTHandler struct {
 	proofNFTService ports.ProofNFTService
+	userService     ports.UserService
 }
 
-func NewProofNFTHandler(proofNFTService ports.ProofNFTService) *ProofNFTHandler {
+func NewProofNFTHandler(proofNFTService ports.ProofNFTService, userService ports.UserService) *ProofNFTHandler {
 	return &ProofNFTHandler{
 		proofNFTService: proofNFTService,
+		userService:     userService,
 	}
 }
 
 func (h *ProofNFTHandler) RequestProof(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
 	var body struct {
 		ExpressionID      string `json:"expressionId"`
 		AcknowledgementID string `json:"acknowledgementId"`
 	}
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
-	return h.proofNFTService.RequestProof(c.Context(), body.ExpressionID, body.AcknowledgementID)
+
+	request, err := h.proofNFTService.RequestProof(c.UserContext(), user, body.ExpressionID, body.AcknowledgementID)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(request)
 }
 
+// ApproveProof is called by the other party of the pairing
 func (h *ProofNFTHandler) ApproveProof(c *fiber.Ctx) error {
-	requestID := c.Params("id")
-	return h.proofNFTService.ApproveProof(c.Context(), requestID)
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	proofNFT, err := h.proofNFTService.ApproveProof(c.UserContext(), user, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(proofNFT)
+}
+
+// RejectProof is called by the other party of the pairing
+func (h *ProofNFTHandler) RejectProof(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	if err := h.proofNFTService.RejectProof(c.UserContext(), user, c.Params("id")); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CancelProof is called by the party who requested the proof
+func (h *ProofNFTHandler) CancelProof(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	if err := h.proofNFTService.CancelProof(c.UserContext(), user, c.Params("id")); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
 func (h *ProofNFTHandler) ListUserProofs(c *fiber.Ctx) error {
-	userAddress := c.Locals("userAddress").(string)
-	proofs, err := h.proofNFTService.ListUserProofs(c.Context(), userAddress)
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	proofs, err := h.proofNFTService.ListUserProofs(c.UserContext(), user)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(proofs)
 }
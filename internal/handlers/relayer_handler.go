@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"proofofpeacemaking/internal/core/chain"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errRelayDisabled is returned by every relay route when no chain is configured
+var errRelayDisabled = fiber.NewError(fiber.StatusServiceUnavailable, "transaction relaying is not enabled")
+
+type RelayerHandler struct {
+	relayerService ports.RelayerService
+	userService    ports.UserService
+}
+
+// NewRelayerHandler serves the relay routes. relayerService is nil when the
+// server runs without a chain.
+func NewRelayerHandler(relayerService ports.RelayerService, userService ports.UserService) *RelayerHandler {
+	return &RelayerHandler{
+		relayerService: relayerService,
+		userService:    userService,
+	}
+}
+
+// GetDomain returns what a wallet needs to sign a relay request: the EIP-712
+// domain and types and, for accounts with a wallet, the next nonce
+func (h *RelayerHandler) GetDomain(c *fiber.Ctx) error {
+	if h.relayerService == nil {
+		return errRelayDisabled
+	}
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"domain": h.relayerService.Domain(),
+		"types":  chain.RelayTypes,
+	}
+	if user.Address != "" {
+		nonce, err := h.relayerService.NextNonce(c.UserContext(), user.Address)
+		if err != nil {
+			return err
+		}
+		response["from"] = user.Address
+		response["nonce"] = nonce
+	}
+	return c.JSON(response)
+}
+
+// Submit relays a signed request. It answers 202 as the transaction is only
+// sent, not mined; poll Get for its status.
+func (h *RelayerHandler) Submit(c *fiber.Ctx) error {
+	if h.relayerService == nil {
+		return errRelayDisabled
+	}
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var req domain.RelayRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	tx, err := h.relayerService.Submit(c.UserContext(), user, &req)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusAccepted).JSON(tx)
+}
+
+// List returns the current user's relayed transactions, newest first
+func (h *RelayerHandler) List(c *fiber.Ctx) error {
+	if h.relayerService == nil {
+		return errRelayDisabled
+	}
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	txs, err := h.relayerService.List(c.UserContext(), user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(txs)
+}
+
+// Get returns one of the current user's relayed transactions
+func (h *RelayerHandler) Get(c *fiber.Ctx) error {
+	if h.relayerService == nil {
+		return errRelayDisabled
+	}
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	tx, err := h.relayerService.Get(c.UserContext(), user.ID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(tx)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReplyHandler struct {
+	replyService ports.ReplyService
+	userService  ports.UserService
+}
+
+func NewReplyHandler(replyService ports.ReplyService, userService ports.UserService) *ReplyHandler {
+	return &ReplyHandler{
+		replyService: replyService,
+		userService:  userService,
+	}
+}
+
+// Create posts a reply under the acknowledgement :id. Text-only replies may
+// be sent as JSON; replies with media are sent as multipart forms with the
+// same field names as expressions.
+func (h *ReplyHandler) Create(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	reply := &domain.Reply{
+		AcknowledgementID: c.Params("id"),
+		Content:           make(map[string]string),
+		MediaContent:      make(map[string]*domain.MediaContent),
+	}
+
+	if c.Is("json") {
+		var body struct {
+			Text     string `json:"text"`
+			ParentID string `json:"parentId"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+		reply.Content["text"] = body.Text
+		reply.ParentID = body.ParentID
+	} else {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid form data")
+		}
+		if text := form.Value["textContent"]; len(text) > 0 {
+			reply.Content["text"] = text[0]
+		}
+		if parentID := form.Value["parentId"]; len(parentID) > 0 {
+			reply.ParentID = parentID[0]
+		}
+		for _, mediaType := range domain.ReplyMediaTypes {
+			files := form.File[mediaType+"Content"]
+			if len(files) == 0 {
+				continue
+			}
+			file, err := files[0].Open()
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			reply.MediaContent[mediaType] = &domain.MediaContent{Reader: file, Filename: files[0].Filename}
+		}
+	}
+
+	if err := h.replyService.Create(c.UserContext(), user, reply); err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(reply)
+}
+
+// List returns a page of the replies under the acknowledgement :id. Pass the
+// previous page's nextCursor as ?cursor= for the next one.
+func (h *ReplyHandler) List(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.replyService.List(c.UserContext(), user, c.Params("id"), c.Query("cursor"), c.QueryInt("limit"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(page)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionHandler struct {
+	sessionService ports.SessionService
+	userService    ports.UserService
+}
+
+func NewSessionHandler(sessionService ports.SessionService, userService ports.UserService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		userService:    userService,
+	}
+}
+
+// ListSessions returns the current user's active sessions, flagging the one making the request
+func (h *SessionHandler) ListSessions(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.sessionService.ListByUser(c.UserContext(), user.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	currentHash := domain.HashToken(c.Cookies("session"))
+	result := make([]fiber.Map, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, fiber.Map{
+			"id":         session.ID.Hex(),
+			"userAgent":  session.UserAgent,
+			"ipAddress":  session.IPAddress,
+			"lastSeenAt": session.LastSeenAt,
+			"createdAt":  session.CreatedAt,
+			"current":    session.TokenHash == currentHash,
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// RevokeSession signs one of the current user's devices out
+func (h *SessionHandler) RevokeSession(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	if err := h.sessionService.Revoke(c.UserContext(), user.ID.Hex(), c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+	})
+}
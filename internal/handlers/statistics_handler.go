@@ -2,7 +2,8 @@ package handlers
 
 import (
 	"bytes"
-	"log"
+	"fmt"
+	"log/slog"
 	"proofofpeacemaking/internal/core/ports"
 	"text/template"
 
@@ -21,16 +22,10 @@ func NewStatisticsHandler(statsService ports.StatisticsService) *StatisticsHandl
 
 // ServeStatisticsPage renders the statistics page
 func (h *StatisticsHandler) ServeStatisticsPage(c *fiber.Ctx) error {
-	log.Printf("[STATISTICS] Fetching latest statistics")
-	stats, err := h.statsService.GetLatestStats(c.Context())
+	stats, err := h.statsService.GetLatestStats(c.UserContext())
 	if err != nil {
-		log.Printf("[STATISTICS] Error getting statistics: %v", err)
+		slog.ErrorContext(c.UserContext(), "failed to get statistics", "error", err)
 		stats = nil
-	} else {
-		log.Printf("[STATISTICS] Got statistics: Users=%d, Expressions=%d, Acks=%d",
-			stats.TotalUsers, stats.TotalExpressions, stats.TotalAcknowledgements)
-		log.Printf("[STATISTICS] Media stats: %v", stats.MediaStats)
-		log.Printf("[STATISTICS] Citizenship stats: %v", stats.CitizenshipStats)
 	}
 
 	// Create template data
@@ -60,10 +55,7 @@ func (h *StatisticsHandler) ServeStatisticsPage(c *fiber.Ctx) error {
 	// Create a buffer to render the template
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, "statistics.html", data); err != nil {
-		log.Printf("[STATISTICS] Error rendering template: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to render template",
-		})
+		return fmt.Errorf("failed to render template: %w", err)
 	}
 
 	c.Type("html")
@@ -72,42 +64,28 @@ func (h *StatisticsHandler) ServeStatisticsPage(c *fiber.Ctx) error {
 
 // GetStatistics returns the latest statistics
 func (h *StatisticsHandler) GetStatistics(c *fiber.Ctx) error {
-	log.Printf("[STATISTICS] API: Fetching latest statistics")
-	stats, err := h.statsService.GetLatestStats(c.Context())
+	stats, err := h.statsService.GetLatestStats(c.UserContext())
 	if err != nil {
-		log.Printf("[STATISTICS] API: Error getting statistics: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get statistics",
-		})
+		return fmt.Errorf("failed to get statistics: %w", err)
 	}
-	log.Printf("[STATISTICS] API: Returning statistics data")
 	return c.JSON(stats)
 }
 
 // GetCountryList returns the list of available countries
 func (h *StatisticsHandler) GetCountryList(c *fiber.Ctx) error {
-	log.Printf("[STATISTICS] Fetching country list")
-	countries, err := h.statsService.GetCountryList(c.Context())
+	countries, err := h.statsService.GetCountryList(c.UserContext())
 	if err != nil {
-		log.Printf("[STATISTICS] Error getting country list: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get country list",
-		})
+		return fmt.Errorf("failed to get country list: %w", err)
 	}
-	log.Printf("[STATISTICS] Returning %d countries", len(countries))
 	return c.JSON(countries)
 }
 
 // UpdateStatistics triggers a statistics update
 func (h *StatisticsHandler) UpdateStatistics(c *fiber.Ctx) error {
-	log.Printf("[STATISTICS] Starting statistics update")
-	if err := h.statsService.UpdateStats(c.Context()); err != nil {
-		log.Printf("[STATISTICS] Error updating statistics: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update statistics",
-		})
+	if err := h.statsService.UpdateStats(c.UserContext()); err != nil {
+		return fmt.Errorf("failed to update statistics: %w", err)
 	}
-	log.Printf("[STATISTICS] Statistics update completed successfully")
+	slog.InfoContext(c.UserContext(), "statistics updated")
 	return c.JSON(fiber.Map{
 		"message": "Statistics updated successfully",
 	})
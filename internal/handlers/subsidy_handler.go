@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errSubsidiesDisabled is returned by every subsidy route when no chain is configured
+var errSubsidiesDisabled = fiber.NewError(fiber.StatusServiceUnavailable, "subsidies are not enabled")
+
+type SubsidyHandler struct {
+	subsidyService ports.SubsidyService
+	userService    ports.UserService
+}
+
+// NewSubsidyHandler serves the admin subsidy routes. subsidyService is nil
+// when the server runs without a chain.
+func NewSubsidyHandler(subsidyService ports.SubsidyService, userService ports.UserService) *SubsidyHandler {
+	return &SubsidyHandler{
+		subsidyService: subsidyService,
+		userService:    userService,
+	}
+}
+
+// SetSubsidies grants or revokes operations for a user. Revocations apply at
+// once and grants once confirmed; the returned changes track them to the
+// chain.
+func (h *SubsidyHandler) SetSubsidies(c *fiber.Ctx) error {
+	if h.subsidyService == nil {
+		return errSubsidiesDisabled
+	}
+	admin, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Operations []string `json:"operations"`
+		Granted    bool     `json:"granted"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	changes, err := h.subsidyService.SetSubsidies(c.UserContext(), admin, c.Params("id"), req.Operations, req.Granted)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusAccepted).JSON(changes)
+}
+
+// ListChanges returns recent subsidy changes, optionally filtered by ?status=
+func (h *SubsidyHandler) ListChanges(c *fiber.Ctx) error {
+	if h.subsidyService == nil {
+		return errSubsidiesDisabled
+	}
+
+	changes, err := h.subsidyService.ListChanges(c.UserContext(), domain.SubsidyChangeStatus(strings.ToLower(c.Query("status"))))
+	if err != nil {
+		return err
+	}
+	return c.JSON(changes)
+}
+
+// Report returns subsidy counts and what each operator spent this month
+func (h *SubsidyHandler) Report(c *fiber.Ctx) error {
+	if h.subsidyService == nil {
+		return errSubsidiesDisabled
+	}
+
+	report, err := h.subsidyService.Report(c.UserContext())
+	if err != nil {
+		return err
+	}
+	return c.JSON(report)
+}
+
+// SetBudget caps what an operator may spend on gas per month
+func (h *SubsidyHandler) SetBudget(c *fiber.Ctx) error {
+	if h.subsidyService == nil {
+		return errSubsidiesDisabled
+	}
+	admin, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		MonthlyLimitGwei int64 `json:"monthlyLimitGwei"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	budget, err := h.subsidyService.SetBudget(c.UserContext(), admin, c.Params("address"), req.MonthlyLimitGwei)
+	if err != nil {
+		return err
+	}
+	return c.JSON(budget)
+}
+
+// RemoveBudget lifts an operator's spending cap
+func (h *SubsidyHandler) RemoveBudget(c *fiber.Ctx) error {
+	if h.subsidyService == nil {
+		return errSubsidiesDisabled
+	}
+
+	if err := h.subsidyService.RemoveBudget(c.UserContext(), c.Params("address")); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
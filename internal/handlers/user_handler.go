@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"strings"
@@ -32,15 +33,13 @@ func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
 	var err error
 
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user profile",
-		})
+		return fmt.Errorf("failed to get user profile: %w", err)
 	}
 
 	return c.JSON(user)
@@ -56,30 +55,24 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&updateData); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request data",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request data")
 	}
 
 	var user *domain.User
 	var err error
 
 	if strings.Contains(userIdentifier, "@") {
-		user, err = h.userService.GetUserByEmail(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByEmail(c.UserContext(), userIdentifier)
 	} else {
-		user, err = h.userService.GetUserByAddress(c.Context(), userIdentifier)
+		user, err = h.userService.GetUserByAddress(c.UserContext(), userIdentifier)
 	}
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get user",
-		})
+		return fmt.Errorf("failed to get user: %w", err)
 	}
 
 	if user == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
 	// Update user fields
@@ -87,16 +80,14 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	user.Citizenship = updateData.Citizenship
 	user.City = updateData.City
 
-	if err := h.userService.Update(c.Context(), user); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update user",
-		})
+	if err := h.userService.Update(c.UserContext(), user); err != nil {
+		return err
 	}
 
 	// Update statistics if citizenship was changed
 	if updateData.Citizenship != "" {
-		if err := h.statsService.UpdateStatisticsAfterCitizenshipChange(c.Context()); err != nil {
-			log.Printf("[USER] Warning: Failed to update statistics: %v", err)
+		if err := h.statsService.UpdateStatisticsAfterCitizenshipChange(c.UserContext()); err != nil {
+			slog.WarnContext(c.UserContext(), "failed to update statistics", "error", err)
 			// Don't return error here, as the user was updated successfully
 		}
 	}
@@ -113,9 +104,7 @@ func (h *UserHandler) ConnectWallet(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&data); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request data",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request data")
 	}
 
 	// Implementation depends on your user service methods
@@ -130,9 +119,7 @@ func (h *UserHandler) GetWalletNonce(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&data); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request data",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request data")
 	}
 
 	// Implementation depends on your user service methods
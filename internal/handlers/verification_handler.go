@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"proofofpeacemaking/internal/core/certificate"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type VerificationHandler struct {
+	verificationService ports.VerificationService
+	userService         ports.UserService
+	siteURL             string
+}
+
+// NewVerificationHandler serves the public verification pages. siteURL is
+// the site's public URL, which shared links and card images are made from.
+func NewVerificationHandler(verificationService ports.VerificationService, userService ports.UserService, siteURL string) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+		userService:         userService,
+		siteURL:             siteURL,
+	}
+}
+
+// ServeExpressionPage renders the public page of an expression
+func (h *VerificationHandler) ServeExpressionPage(c *fiber.Ctx) error {
+	record, err := h.verificationService.GetExpression(c.UserContext(), c.Params("id"))
+	return h.render(c, record, err)
+}
+
+// ServeVerifyPage renders the public page of an expression, proof NFT or
+// proof request, with its on-chain status and integrity checks
+func (h *VerificationHandler) ServeVerifyPage(c *fiber.Ctx) error {
+	record, err := h.verificationService.GetRecord(c.UserContext(), c.Params("id"))
+	return h.render(c, record, err)
+}
+
+func (h *VerificationHandler) render(c *fiber.Ctx, record *domain.PublicRecord, err error) error {
+	data := fiber.Map{
+		"User":  h.navbarUser(c),
+		"URL":   h.siteURL + c.Path(),
+		"Image": h.siteURL + "/static/img/pop.png",
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		data["Title"] = "Not found"
+		data["Description"] = "This expression or proof does not exist or is not public."
+		return c.Status(fiber.StatusNotFound).Render("verify", data, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	data["Record"] = record
+	data["Description"] = certificate.Excerpt(record.Expression.Text)
+	if record.Kind == domain.PublicRecordProof {
+		names := make([]string, 0, len(record.Proof.Parties))
+		for _, party := range record.Proof.Parties {
+			if party.Name != "" {
+				names = append(names, party.Name)
+			}
+		}
+		data["Title"] = "Proof of Peacemaking"
+		if len(names) == len(record.Proof.Parties) {
+			data["Title"] = fmt.Sprintf("Proof of Peacemaking between %s", strings.Join(names, " and "))
+		}
+	} else {
+		data["Title"] = "Expression"
+		if record.Expression.Creator.Name != "" {
+			data["Title"] = "Expression by " + record.Expression.Creator.Name
+		}
+	}
+	if data["Description"] == "" {
+		data["Description"] = "Verify this record on Proof of Peacemaking."
+	}
+	return c.Render("verify", data, "")
+}
+
+// navbarUser is the signed-in user the navbar shows, if any
+func (h *VerificationHandler) navbarUser(c *fiber.Ctx) fiber.Map {
+	identifier, _ := c.Locals("userAddress").(string)
+	if identifier == "" {
+		return nil
+	}
+	var user *domain.User
+	var err error
+	if strings.Contains(identifier, "@") {
+		user, err = h.userService.GetUserByEmail(c.UserContext(), identifier)
+	} else {
+		user, err = h.userService.GetUserByAddress(c.UserContext(), identifier)
+	}
+	if err != nil || user == nil {
+		return nil
+	}
+	return fiber.Map{"Email": user.Email, "Address": user.Address}
+}
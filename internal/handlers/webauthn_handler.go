@@ -3,13 +3,17 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/metrics"
+	"proofofpeacemaking/internal/middleware"
 	"strings"
 
 	"time"
 
-	"log"
+	"log/slog"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -18,16 +22,18 @@ import (
 )
 
 type WebAuthnHandler struct {
-	webAuthnService ports.WebAuthnService
-	sessionService  ports.SessionService
-	userService     ports.UserService
+	webAuthnService  ports.WebAuthnService
+	sessionService   ports.SessionService
+	userService      ports.UserService
+	rateLimitService ports.RateLimitService
 }
 
-func NewWebAuthnHandler(webAuthnService ports.WebAuthnService, sessionService ports.SessionService, userService ports.UserService) *WebAuthnHandler {
+func NewWebAuthnHandler(webAuthnService ports.WebAuthnService, sessionService ports.SessionService, userService ports.UserService, rateLimitService ports.RateLimitService) *WebAuthnHandler {
 	return &WebAuthnHandler{
-		webAuthnService: webAuthnService,
-		sessionService:  sessionService,
-		userService:     userService,
+		webAuthnService:  webAuthnService,
+		sessionService:   sessionService,
+		userService:      userService,
+		rateLimitService: rateLimitService,
 	}
 }
 
@@ -40,34 +46,24 @@ func (h *WebAuthnHandler) BeginRegistration(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
 	// Check if email or username already exists
-	existingUser, err := h.userService.GetUserByEmail(c.Context(), req.Email)
+	existingUser, err := h.userService.GetUserByEmail(c.UserContext(), req.Email)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to check email",
-		})
+		return fmt.Errorf("failed to check email: %w", err)
 	}
 	if existingUser != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "email already registered",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "email already registered")
 	}
 
-	existingUser, err = h.userService.GetUserByUsername(c.Context(), req.Username)
+	existingUser, err = h.userService.GetUserByUsername(c.UserContext(), req.Username)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to check username",
-		})
+		return fmt.Errorf("failed to check username: %w", err)
 	}
 	if existingUser != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "username already taken",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "username already taken")
 	}
 
 	// Create a new user with pending status
@@ -80,28 +76,18 @@ func (h *WebAuthnHandler) BeginRegistration(c *fiber.Ctx) error {
 	}
 
 	// Save user to database
-	if err := h.userService.Create(c.Context(), user); err != nil {
-		if strings.HasPrefix(err.Error(), "validation failed:") {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		log.Printf("Failed to create user: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to create user",
-		})
+	if err := h.userService.Create(c.UserContext(), user); err != nil {
+		return err
 	}
 
 	// Begin registration
-	options, sessionData, err := h.webAuthnService.BeginRegistration(c.Context(), user.ID)
+	options, sessionData, err := h.webAuthnService.BeginRegistration(c.UserContext(), user.ID)
 	if err != nil {
 		// If registration fails, we should clean up the user
-		if delErr := h.userService.Delete(c.Context(), user.ID); delErr != nil {
-			log.Printf("Failed to delete user after failed registration: %v", delErr)
+		if delErr := h.userService.Delete(c.UserContext(), user.ID); delErr != nil {
+			slog.ErrorContext(c.UserContext(), "failed to delete user after failed registration", "error", delErr)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	// Create a temporary registration session
@@ -113,14 +99,12 @@ func (h *WebAuthnHandler) BeginRegistration(c *fiber.Ctx) error {
 		IsRegistration: true,                            // Mark this as a registration session
 	}
 
-	if err := h.sessionService.Create(c.Context(), session); err != nil {
+	if err := h.sessionService.Create(middleware.ClientContext(c), session); err != nil {
 		// If session creation fails, clean up the user
-		if delErr := h.userService.Delete(c.Context(), user.ID); delErr != nil {
-			log.Printf("Failed to delete user after failed session creation: %v", delErr)
+		if delErr := h.userService.Delete(c.UserContext(), user.ID); delErr != nil {
+			slog.ErrorContext(c.UserContext(), "failed to delete user after failed session creation", "error", delErr)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to create session",
-		})
+		return fmt.Errorf("failed to create session: %w", err)
 	}
 
 	// Set temporary registration session cookie
@@ -151,58 +135,44 @@ func must(data []byte, err error) []byte {
 // FinishRegistration completes the passkey registration process
 func (h *WebAuthnHandler) FinishRegistration(c *fiber.Ctx) error {
 	// Get user from registration session
-	session, err := h.sessionService.GetSession(c.Context(), c.Cookies("registration_session"))
+	session, err := h.sessionService.GetSession(c.UserContext(), c.Cookies("registration_session"))
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
 	}
 
 	if !session.IsRegistration {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid session type",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "invalid session type")
 	}
 
 	userID, err := primitive.ObjectIDFromHex(session.UserID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid user ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
 	}
 
 	// Get session data from user's session
 	if session.WebAuthnData == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "no session data found",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "no session data found")
 	}
 
 	var sessionData webauthn.SessionData
 	if err := json.Unmarshal([]byte(session.WebAuthnData), &sessionData); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to deserialize session data",
-		})
+		return fmt.Errorf("failed to deserialize session data: %w", err)
 	}
 
 	// Parse response
 	response, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(c.Body()))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "failed to parse response",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "failed to parse response")
 	}
 
 	// Complete registration
-	if err := h.webAuthnService.FinishRegistration(c.Context(), userID, sessionData, response); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	if err := h.webAuthnService.FinishRegistration(c.UserContext(), userID, sessionData, response); err != nil {
+		return err
 	}
 
 	// Delete the registration session
-	if err := h.sessionService.Delete(c.Context(), session.Token); err != nil {
-		log.Printf("Failed to delete registration session: %v", err)
+	if err := h.sessionService.Delete(c.UserContext(), session.Token); err != nil {
+		slog.WarnContext(c.UserContext(), "failed to delete registration session", "error", err)
 	}
 
 	// Create a new authenticated session
@@ -212,10 +182,8 @@ func (h *WebAuthnHandler) FinishRegistration(c *fiber.Ctx) error {
 		ExpiresAt: time.Now().Add(24 * time.Hour), // 24-hour session
 	}
 
-	if err := h.sessionService.Create(c.Context(), authSession); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to create authenticated session",
-		})
+	if err := h.sessionService.Create(middleware.ClientContext(c), authSession); err != nil {
+		return fmt.Errorf("failed to create authenticated session: %w", err)
 	}
 
 	// Set authenticated session cookie
@@ -241,44 +209,32 @@ func (h *WebAuthnHandler) BeginAuthentication(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
 	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "email is required",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "email is required")
 	}
 
 	// Get user by email
-	user, err := h.userService.GetUserByEmail(c.Context(), req.Email)
+	user, err := h.userService.GetUserByEmail(c.UserContext(), req.Email)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to get user",
-		})
+		return fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "user not found",
-		})
+		return fiber.NewError(fiber.StatusNotFound, "user not found")
 	}
 
 	// Begin authentication
-	options, sessionData, err := h.webAuthnService.BeginAuthentication(c.Context(), user.ID)
+	options, sessionData, err := h.webAuthnService.BeginAuthentication(c.UserContext(), user.ID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	// Create a temporary session for storing WebAuthn session data
 	sessionDataJSON, err := json.Marshal(sessionData)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to serialize session data",
-		})
+		return fmt.Errorf("failed to serialize session data: %w", err)
 	}
 
 	session := &domain.Session{
@@ -288,10 +244,8 @@ func (h *WebAuthnHandler) BeginAuthentication(c *fiber.Ctx) error {
 		ExpiresAt:    time.Now().Add(5 * time.Minute), // Short expiry for authentication
 	}
 
-	if err := h.sessionService.Create(c.Context(), session); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to store session data",
-		})
+	if err := h.sessionService.Create(middleware.ClientContext(c), session); err != nil {
+		return fmt.Errorf("failed to store session data: %w", err)
 	}
 
 	// Set session cookie
@@ -309,42 +263,29 @@ func (h *WebAuthnHandler) BeginAuthentication(c *fiber.Ctx) error {
 
 // FinishAuthentication completes the passkey authentication process
 func (h *WebAuthnHandler) FinishAuthentication(c *fiber.Ctx) error {
-	log.Printf("[WEBAUTHN] Starting FinishAuthentication")
-
 	// Get session
-	session, err := h.sessionService.GetSession(c.Context(), c.Cookies("auth_session"))
+	session, err := h.sessionService.GetSession(c.UserContext(), c.Cookies("auth_session"))
 	if err != nil {
-		log.Printf("[WEBAUTHN] Failed to get auth session: %v", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "unauthorized",
-		})
+		slog.InfoContext(c.UserContext(), "passkey auth session not found", "error", err)
+		return fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
 	}
-	log.Printf("[WEBAUTHN] Found auth session for user: %s", session.UserID)
 
 	userID, err := primitive.ObjectIDFromHex(session.UserID)
 	if err != nil {
-		log.Printf("[WEBAUTHN] Invalid user ID format: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid user ID",
-		})
+		slog.WarnContext(c.UserContext(), "invalid user ID in passkey auth session", "error", err)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid user ID")
 	}
 
 	// Get session data
 	if session.WebAuthnData == "" {
-		log.Printf("[WEBAUTHN] No WebAuthn data found in session")
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "no session data found",
-		})
+		slog.WarnContext(c.UserContext(), "no WebAuthn data in passkey auth session")
+		return fiber.NewError(fiber.StatusBadRequest, "no session data found")
 	}
 
 	var sessionData webauthn.SessionData
 	if err := json.Unmarshal([]byte(session.WebAuthnData), &sessionData); err != nil {
-		log.Printf("[WEBAUTHN] Failed to deserialize session data: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to deserialize session data",
-		})
+		return fmt.Errorf("failed to deserialize session data: %w", err)
 	}
-	log.Printf("[WEBAUTHN] Successfully deserialized session data")
 
 	// Log request body for debugging
 	body := c.Body()
@@ -352,25 +293,32 @@ func (h *WebAuthnHandler) FinishAuthentication(c *fiber.Ctx) error {
 	// Parse response
 	response, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(body))
 	if err != nil {
-		log.Printf("[WEBAUTHN] Failed to parse credential response: %v", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "failed to parse response",
-		})
+		slog.InfoContext(c.UserContext(), "failed to parse passkey credential response", "error", err)
+		return fiber.NewError(fiber.StatusBadRequest, "failed to parse response")
+	}
+
+	// Lock out by account, as email sign-in does, so repeated failed assertions back off
+	lockoutSubject := "user:" + userID.Hex()
+	if err := checkLockout(c, h.rateLimitService, lockoutSubject); err != nil {
+		return err
 	}
-	log.Printf("[WEBAUTHN] Successfully parsed credential response")
 
 	// Complete authentication
-	if err := h.webAuthnService.FinishAuthentication(c.Context(), userID, sessionData, response); err != nil {
-		log.Printf("[WEBAUTHN] Failed to finish authentication: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	err = h.webAuthnService.FinishAuthentication(c.UserContext(), userID, sessionData, response)
+	metrics.ObserveAuth(metrics.AuthMethodPasskey, err == nil)
+	if err != nil {
+		slog.InfoContext(c.UserContext(), "passkey authentication failed", "error", err)
+		if lockErr := recordFailedSignIn(c, h.rateLimitService, lockoutSubject); lockErr != nil {
+			return lockErr
+		}
+		return err
 	}
-	log.Printf("[WEBAUTHN] Successfully finished authentication")
+	recordSignIn(c, h.rateLimitService, lockoutSubject)
+	slog.DebugContext(c.UserContext(), "passkey authentication succeeded")
 
 	// Delete the temporary auth session
-	if err := h.sessionService.Delete(c.Context(), session.Token); err != nil {
-		log.Printf("[WEBAUTHN] Failed to delete auth session: %v", err)
+	if err := h.sessionService.Delete(c.UserContext(), session.Token); err != nil {
+		slog.WarnContext(c.UserContext(), "failed to delete passkey auth session", "error", err)
 	}
 
 	// Create a new authenticated session
@@ -380,13 +328,9 @@ func (h *WebAuthnHandler) FinishAuthentication(c *fiber.Ctx) error {
 		ExpiresAt: time.Now().Add(24 * time.Hour), // 24-hour session
 	}
 
-	if err := h.sessionService.Create(c.Context(), authSession); err != nil {
-		log.Printf("[WEBAUTHN] Failed to create authenticated session: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to create authenticated session",
-		})
+	if err := h.sessionService.Create(middleware.ClientContext(c), authSession); err != nil {
+		return fmt.Errorf("failed to create authenticated session: %w", err)
 	}
-	log.Printf("[WEBAUTHN] Created new authenticated session")
 
 	// Set authenticated session cookie
 	c.Cookie(&fiber.Cookie{
@@ -397,9 +341,207 @@ func (h *WebAuthnHandler) FinishAuthentication(c *fiber.Ctx) error {
 		SameSite: "Strict",
 		MaxAge:   86400, // 24 hours
 	})
-	log.Printf("[WEBAUTHN] Set session cookie")
 
 	return c.JSON(fiber.Map{
 		"message": "authenticated successfully",
 	})
 }
+
+// BeginDiscoverableLogin initiates a usernameless passkey login
+func (h *WebAuthnHandler) BeginDiscoverableLogin(c *fiber.Ctx) error {
+	options, sessionData, err := h.webAuthnService.BeginDiscoverableLogin(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	sessionDataJSON, err := json.Marshal(sessionData)
+	if err != nil {
+		return fmt.Errorf("failed to serialize session data: %w", err)
+	}
+
+	// The user is not known until the authenticator returns its userHandle
+	session := &domain.Session{
+		WebAuthnData: string(sessionDataJSON),
+		CreatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(5 * time.Minute), // Short expiry for authentication
+	}
+
+	if err := h.sessionService.Create(middleware.ClientContext(c), session); err != nil {
+		return fmt.Errorf("failed to store session data: %w", err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "auth_session",
+		Value:    session.Token,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Strict",
+		MaxAge:   300, // 5 minutes
+	})
+
+	return c.JSON(options)
+}
+
+// FinishDiscoverableLogin completes a usernameless passkey login
+func (h *WebAuthnHandler) FinishDiscoverableLogin(c *fiber.Ctx) error {
+	session, err := h.sessionService.GetSession(c.UserContext(), c.Cookies("auth_session"))
+	if err != nil || session == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	if session.WebAuthnData == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "no session data found")
+	}
+
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal([]byte(session.WebAuthnData), &sessionData); err != nil {
+		return fmt.Errorf("failed to deserialize session data: %w", err)
+	}
+
+	response, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(c.Body()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "failed to parse response")
+	}
+
+	// The user handle names the account the passkey claims to belong to; without
+	// one the login cannot succeed, so there is no account to lock
+	var lockoutSubject string
+	if handle := response.Response.UserHandle; len(handle) > 0 {
+		lockoutSubject = "user:" + string(handle)
+		if err := checkLockout(c, h.rateLimitService, lockoutSubject); err != nil {
+			return err
+		}
+	}
+
+	user, err := h.webAuthnService.FinishDiscoverableLogin(c.UserContext(), sessionData, response)
+	metrics.ObserveAuth(metrics.AuthMethodPasskey, err == nil)
+	if err != nil {
+		slog.InfoContext(c.UserContext(), "discoverable passkey login failed", "error", err)
+		if lockoutSubject != "" {
+			if lockErr := recordFailedSignIn(c, h.rateLimitService, lockoutSubject); lockErr != nil {
+				return lockErr
+			}
+		}
+		if errors.Is(err, domain.ErrSecondFactorRequired) || errors.Is(err, domain.ErrPasskeyDeactivated) {
+			return err
+		}
+		return fiber.NewError(fiber.StatusUnauthorized, "passkey not recognized")
+	}
+
+	if lockoutSubject != "" {
+		recordSignIn(c, h.rateLimitService, lockoutSubject)
+	}
+
+	// Delete the temporary auth session
+	if err := h.sessionService.Delete(c.UserContext(), session.Token); err != nil {
+		slog.WarnContext(c.UserContext(), "failed to delete passkey auth session", "error", err)
+	}
+
+	// Create a new authenticated session, keeping the wallet address so
+	// wallet-only accounts resolve the same way as after a wallet login
+	authSession := &domain.Session{
+		UserID:    user.ID.Hex(),
+		Address:   user.Address,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(24 * time.Hour), // 24-hour session
+	}
+
+	if err := h.sessionService.Create(middleware.ClientContext(c), authSession); err != nil {
+		return fmt.Errorf("failed to create authenticated session: %w", err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "session",
+		Value:    authSession.Token,
+		Path:     "/",
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Strict",
+		MaxAge:   86400, // 24 hours
+	})
+
+	return c.JSON(fiber.Map{
+		"message":  "authenticated successfully",
+		"redirect": "/feed",
+	})
+}
+
+// passkeyReauthWindow is how recently the user must have logged in to revoke a passkey
+const passkeyReauthWindow = 10 * time.Minute
+
+// ListPasskeys returns the current user's passkeys with device info and last-used time
+func (h *WebAuthnHandler) ListPasskeys(c *fiber.Ctx) error {
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	passkeys, err := h.webAuthnService.ListPasskeys(c.UserContext(), user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	return c.JSON(passkeys)
+}
+
+// RenamePasskey sets a new display name on one of the current user's passkeys
+func (h *WebAuthnHandler) RenamePasskey(c *fiber.Ctx) error {
+	passkeyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid passkey ID")
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 64 {
+		return fiber.NewError(fiber.StatusBadRequest, "name must be between 1 and 64 characters")
+	}
+
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	if err := h.webAuthnService.RenamePasskey(c.UserContext(), user.ID, passkeyID, req.Name); err != nil {
+		return fmt.Errorf("failed to rename passkey: %w", err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "passkey renamed",
+	})
+}
+
+// RevokePasskey deactivates one of the current user's passkeys. The user must
+// have logged in recently, and cannot revoke their last way of logging in.
+func (h *WebAuthnHandler) RevokePasskey(c *fiber.Ctx) error {
+	passkeyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid passkey ID")
+	}
+
+	session, err := h.sessionService.GetSession(c.UserContext(), c.Cookies("session"))
+	if err != nil || session == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
+	}
+	if time.Since(session.CreatedAt) > passkeyReauthWindow {
+		return fiber.NewError(fiber.StatusForbidden, "please log in again to revoke a passkey")
+	}
+
+	user, err := requireUser(c, h.userService)
+	if err != nil {
+		return err
+	}
+
+	if err := h.webAuthnService.RevokePasskey(c.UserContext(), user.ID, passkeyID); err != nil {
+		return fmt.Errorf("failed to revoke passkey: %w", err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "passkey revoked",
+	})
+}
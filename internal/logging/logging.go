@@ -0,0 +1,100 @@
+// Package logging configures the process-wide slog logger: JSON or text
+// output, a minimum level, the request ID and trace context of the current
+// request on every record, and redaction of personal data.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID. Middleware sets
+// it on the Fiber user context, which handlers pass down to services.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Setup installs the default logger. level is debug, info, warn or error
+// (default info); format is json (default) or text. Output from the standard
+// log package is routed through the same handler at info level.
+func Setup(level, format string) {
+	slog.SetDefault(slog.New(NewHandler(os.Stdout, ParseLevel(level), format)))
+}
+
+// NewHandler builds the redacting, request-aware handler Setup installs
+func NewHandler(w io.Writer, level slog.Level, format string) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		AddSource:   true,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "text") {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return &contextHandler{Handler: handler}
+}
+
+// ParseLevel maps a level name to a slog level, defaulting to info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler adds the request ID and trace context from the record's
+// context, so log lines can be joined to the spans of the same request
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if ctx != nil {
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			record.AddAttrs(
+				slog.String("trace_id", span.TraceID().String()),
+				slog.String("span_id", span.SpanID().String()),
+			)
+		}
+	}
+	// Messages still built with fmt from the log package can carry personal data
+	record.Message = redactString(record.Message)
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
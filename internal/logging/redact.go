@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// secretKeys are dropped entirely
+var secretKeys = map[string]bool{
+	"token":    true,
+	"password": true,
+	"secret":   true,
+	"cookie":   true,
+	"session":  true,
+	"nonce":    true,
+	"apikey":   true,
+}
+
+// identifyingKeys are replaced with a short fingerprint, so one user's
+// requests can still be followed through the logs without exposing who they are
+var identifyingKeys = map[string]bool{
+	"email":      true,
+	"address":    true,
+	"user":       true,
+	"identifier": true,
+	"ip":         true,
+	"subject":    true,
+}
+
+var (
+	emailPattern    = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	addressPattern  = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+	apiTokenPattern = regexp.MustCompile(`pop_[A-Za-z0-9_\-]+`)
+)
+
+// redactAttr is the ReplaceAttr hook applied to every attribute
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindGroup {
+		return attr
+	}
+
+	switch {
+	case keyMatches(attr.Key, secretKeys):
+		return slog.String(attr.Key, "[REDACTED]")
+	case keyMatches(attr.Key, identifyingKeys):
+		return slog.String(attr.Key, Fingerprint(attr.Value.String()))
+	}
+
+	if attr.Value.Kind() == slog.KindString || attr.Value.Kind() == slog.KindAny {
+		if s := attr.Value.String(); s != "" {
+			if redacted := redactString(s); redacted != s {
+				return slog.String(attr.Key, redacted)
+			}
+		}
+	}
+	return attr
+}
+
+// keyMatches reports whether key names one of the sensitive fields, either
+// exactly or as its last word, as in user_email or sessionToken. Words are
+// whole, so relationship is not an ip.
+func keyMatches(key string, keys map[string]bool) bool {
+	return keys[strings.ToLower(key)] || keys[lastWord(key)]
+}
+
+// lastWord returns the lower-cased last word of a snake_case, kebab-case,
+// dotted or camelCase key
+func lastWord(key string) string {
+	key = key[strings.LastIndexAny(key, "_-.")+1:]
+	for i := len(key) - 1; i > 0; i-- {
+		if unicode.IsUpper(rune(key[i])) && unicode.IsLower(rune(key[i-1])) {
+			key = key[i:]
+			break
+		}
+	}
+	return strings.ToLower(key)
+}
+
+// redactString masks emails, wallet addresses and API tokens inside free text
+func redactString(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, Fingerprint)
+	s = addressPattern.ReplaceAllStringFunc(s, Fingerprint)
+	return apiTokenPattern.ReplaceAllString(s, "[REDACTED]")
+}
+
+// Fingerprint returns a short, stable, non-reversible stand-in for a value
+func Fingerprint(value string) string {
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(value)))
+	return "redacted:" + hex.EncodeToString(sum[:4])
+}
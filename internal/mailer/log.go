@@ -0,0 +1,19 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+	"proofofpeacemaking/internal/core/ports"
+)
+
+type logMailer struct{}
+
+// NewLogMailer writes each message to the log instead of sending it
+func NewLogMailer() ports.Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, html string) error {
+	slog.InfoContext(ctx, "email not sent, mailer is log", "to", to, "subject", subject, "size", len(html))
+	return nil
+}
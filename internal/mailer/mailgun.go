@@ -0,0 +1,33 @@
+// Package mailer holds the ways the server can send email: through Mailgun,
+// or by only logging the message for local development.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/ports"
+
+	"github.com/mailgun/mailgun-go/v4"
+)
+
+type mailgunMailer struct {
+	client *mailgun.MailgunImpl
+	sender string
+}
+
+// NewMailgun sends through the Mailgun API at apiBase
+func NewMailgun(domain, apiKey, apiBase, sender string) ports.Mailer {
+	client := mailgun.NewMailgun(domain, apiKey)
+	client.SetAPIBase(apiBase)
+	return &mailgunMailer{client: client, sender: sender}
+}
+
+func (m *mailgunMailer) Send(ctx context.Context, to, subject, html string) error {
+	message := m.client.NewMessage(m.sender, subject, "", to)
+	message.SetHtml(html)
+
+	if _, _, err := m.client.Send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package metrics defines the Prometheus collectors the server exposes on /metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pop"
+
+// Registry holds every collector below plus the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// HTTPRequestDuration is labelled by the route pattern, not the raw path, to keep cardinality bounded
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	MongoOperationDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "MongoDB command latency by collection, command and outcome.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"collection", "operation", "outcome"})
+
+	StorageUploadBytes = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_upload_bytes_total",
+		Help:      "Bytes sent to object storage by outcome.",
+	}, []string{"outcome"})
+
+	StorageUploadDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_upload_duration_seconds",
+		Help:      "Object storage upload latency by outcome.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"outcome"})
+
+	AuthAttempts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_attempts_total",
+		Help:      "Sign-in and token authentication attempts by method and outcome.",
+	}, []string{"method", "outcome"})
+
+	NotificationRecipients = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_recipients_total",
+		Help:      "Users a notification was delivered to, by notification type.",
+	}, []string{"type"})
+
+	RelayTransactions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "relay_transactions_total",
+		Help:      "Relayed transaction events by operation: submitted, replaced, confirmed or failed.",
+	}, []string{"operation", "event"})
+)
+
+// Authentication methods used as the method label of AuthAttempts
+const (
+	AuthMethodWallet   = "wallet"
+	AuthMethodEmail    = "email"
+	AuthMethodPasskey  = "passkey"
+	AuthMethodAPIToken = "api_token"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Outcome maps an error to the "success" or "failure" label value
+func Outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// ObserveAuth counts one authentication attempt
+func ObserveAuth(method string, success bool) {
+	outcome := "failure"
+	if success {
+		outcome = "success"
+	}
+	AuthAttempts.WithLabelValues(method, outcome).Inc()
+}
+
+// ObserveRelay counts one event in the life of a relayed transaction
+func ObserveRelay(operation, event string) {
+	RelayTransactions.WithLabelValues(operation, event).Inc()
+}
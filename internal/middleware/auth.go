@@ -1,43 +1,51 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"proofofpeacemaking/internal/metrics"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type AuthMiddleware struct {
-	authService ports.AuthService
+	authService     ports.AuthService
+	apiTokenService ports.APITokenService
 }
 
-func NewAuthMiddleware(authService ports.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService ports.AuthService, apiTokenService ports.APITokenService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:     authService,
+		apiTokenService: apiTokenService,
 	}
 }
 
 func (m *AuthMiddleware) Authenticate() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// Personal access tokens are accepted on API routes only
+		if bearer := bearerToken(c); bearer != "" && strings.HasPrefix(c.Path(), "/api") {
+			return m.authenticateAPIToken(c, bearer)
+		}
+
 		// Get token from cookie
 		token := c.Cookies("session")
 		if token == "" {
-			log.Printf("[AUTH] No token found in cookie")
+			slog.DebugContext(c.UserContext(), "no session cookie", "path", c.Path())
 			// For API routes, return JSON error
 			if strings.HasPrefix(c.Path(), "/api") {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"error": "Not authenticated",
-				})
+				return fiber.NewError(fiber.StatusUnauthorized, "Not authenticated")
 			}
 			// For page routes, redirect to home
 			return c.Redirect("/")
 		}
 
 		// Verify token
-		userAddress, err := m.authService.VerifyToken(c.Context(), token)
+		userAddress, err := m.authService.VerifyToken(ClientContext(c), token)
 		if err != nil {
-			log.Printf("[AUTH] Token verification failed: %v", err)
+			slog.InfoContext(c.UserContext(), "session verification failed", "error", err)
 			// Clear invalid cookie
 			c.Cookie(&fiber.Cookie{
 				Name:     "session",
@@ -50,9 +58,7 @@ func (m *AuthMiddleware) Authenticate() fiber.Handler {
 			})
 			// For API routes, return JSON error
 			if strings.HasPrefix(c.Path(), "/api") {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"error": "Invalid or expired token",
-				})
+				return fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired token")
 			}
 			// For page routes, redirect to home
 			return c.Redirect("/")
@@ -60,7 +66,6 @@ func (m *AuthMiddleware) Authenticate() fiber.Handler {
 
 		// Set user address in context
 		c.Locals("userAddress", userAddress)
-		log.Printf("[AUTH] User address set in context: %s", userAddress)
 		return c.Next()
 	}
 }
@@ -75,7 +80,7 @@ func (m *AuthMiddleware) Optional() fiber.Handler {
 		}
 
 		// Verify session token
-		userIdentifier, err := m.authService.VerifyToken(c.Context(), sessionCookie)
+		userIdentifier, err := m.authService.VerifyToken(ClientContext(c), sessionCookie)
 		if err != nil {
 			// Invalid session, continue without user data
 			return c.Next()
@@ -86,3 +91,105 @@ func (m *AuthMiddleware) Optional() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// authenticateAPIToken verifies a personal access token and checks that its
+// scopes cover the request before handing over to the route
+func (m *AuthMiddleware) authenticateAPIToken(c *fiber.Ctx, bearer string) error {
+	token, userIdentifier, err := m.apiTokenService.Authenticate(c.UserContext(), bearer)
+	metrics.ObserveAuth(metrics.AuthMethodAPIToken, err == nil)
+	if err != nil {
+		slog.InfoContext(c.UserContext(), "API token verification failed", "error", err)
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired token")
+	}
+
+	scope, ok := requiredScope(c.Method(), c.Path())
+	if !ok || !token.HasScope(scope) {
+		return fiber.NewError(fiber.StatusForbidden, "Token does not permit this request")
+	}
+
+	c.Locals("userAddress", userIdentifier)
+	c.Locals("apiTokenID", token.ID.Hex())
+	return c.Next()
+}
+
+// apiTokenRoutes lists every API route a personal access token may call and
+// the scope it needs, in the form the routes are registered in. Anything not
+// listed, such as token, session and passkey management, replies, credential
+// issuance and the moderation and admin routes, is session-only.
+var apiTokenRoutes = []struct {
+	method string
+	path   string
+	scope  domain.APITokenScope
+}{
+	{fiber.MethodGet, "/api/notifications", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/expressions", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/expressions/:id", domain.APITokenScopeRead},
+	{fiber.MethodPost, "/api/expressions", domain.APITokenScopeExpressionsWrite},
+	{fiber.MethodGet, "/api/acknowledgements/expression/:id", domain.APITokenScopeRead},
+	{fiber.MethodPost, "/api/acknowledgements", domain.APITokenScopeAcknowledgementsWrite},
+	{fiber.MethodGet, "/api/invitations/sent", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/invitations/received", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/proofs/user", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/proofs/inclusion/:id", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/relay/domain", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/relay", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/relay/:id", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/credentials", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/credentials/:id/download", domain.APITokenScopeRead},
+	{fiber.MethodGet, "/api/certificates/:source/:id", domain.APITokenScopeRead},
+}
+
+// requiredScope returns the token scope that allows an API request, and false
+// for requests tokens may not make
+func requiredScope(method, path string) (domain.APITokenScope, bool) {
+	if method == fiber.MethodHead {
+		method = fiber.MethodGet
+	}
+	for _, route := range apiTokenRoutes {
+		if route.method == method && matchRoute(route.path, path) {
+			return route.scope, true
+		}
+	}
+	return "", false
+}
+
+// matchRoute reports whether path matches a route pattern whose ":name"
+// segments stand for any single non-empty segment. Case and a trailing slash
+// are ignored, as the router does.
+func matchRoute(pattern, path string) bool {
+	want := strings.Split(pattern, "/")
+	got := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i, segment := range want {
+		if strings.HasPrefix(segment, ":") {
+			if got[i] == "" {
+				return false
+			}
+			continue
+		}
+		if !strings.EqualFold(segment, got[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header
+func bearerToken(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// ClientContext returns the request context annotated with the caller's user
+// agent and IP, so sessions created or refreshed from it record the device
+func ClientContext(c *fiber.Ctx) context.Context {
+	return domain.WithClientInfo(c.UserContext(), domain.ClientInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	})
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// HandleErrors renders errors returned further down the chain with the app's
+// error handler, so the access log, metrics and traces record the status the
+// client actually receives
+func HandleErrors() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return c.App().ErrorHandler(c, err)
+		}
+		return nil
+	}
+}
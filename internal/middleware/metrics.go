@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"proofofpeacemaking/internal/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HTTPMetrics records request latency by method, route pattern and status.
+// The route pattern rather than the raw path keeps label cardinality bounded.
+func HTTPMetrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		// Requests that matched no route only pass through app.Use handlers
+		route := c.Route().Path
+		if c.Route().Method == "USE" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Method(), route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// RequireBearer guards an endpoint with a static bearer token. An empty token
+// leaves the endpoint open, for deployments that restrict it at the network.
+func RequireBearer(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Next()
+		}
+		if subtle.ConstantTimeCompare([]byte(bearerToken(c)), []byte(token)) != 1 {
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		return c.Next()
+	}
+}
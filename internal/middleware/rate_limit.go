@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitMiddleware throttles routes according to a domain.RateLimitPolicy
+type RateLimitMiddleware struct {
+	rateLimitService ports.RateLimitService
+}
+
+func NewRateLimitMiddleware(rateLimitService ports.RateLimitService) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		rateLimitService: rateLimitService,
+	}
+}
+
+// ByIP limits requests per client IP, for routes used before signing in
+func (m *RateLimitMiddleware) ByIP(policy domain.RateLimitPolicy) fiber.Handler {
+	return m.limit(policy, func(c *fiber.Ctx) string {
+		return "ip:" + c.IP()
+	})
+}
+
+// ByUser limits requests per authenticated user. It must run after
+// AuthMiddleware.Authenticate and falls back to the client IP without a user.
+func (m *RateLimitMiddleware) ByUser(policy domain.RateLimitPolicy) fiber.Handler {
+	return m.limit(policy, func(c *fiber.Ctx) string {
+		if userIdentifier, _ := c.Locals("userAddress").(string); userIdentifier != "" {
+			return "user:" + userIdentifier
+		}
+		return "ip:" + c.IP()
+	})
+}
+
+func (m *RateLimitMiddleware) limit(policy domain.RateLimitPolicy, subject func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		decision, err := m.rateLimitService.Allow(c.UserContext(), policy, subject(c))
+		if err != nil {
+			// Fail open: an unavailable store should not take the site down with it
+			slog.ErrorContext(c.UserContext(), "could not apply rate limit", "policy", policy.Name, "error", err)
+			return c.Next()
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Set("X-RateLimit-Reset", strconv.FormatInt(decision.ResetAt.Unix(), 10))
+
+		if !decision.Allowed {
+			slog.WarnContext(c.UserContext(), "rate limit exceeded", "policy", policy.Name, "method", c.Method(), "path", c.Path())
+			return tooManyRequests(c, decision.RetryAfter())
+		}
+		return c.Next()
+	}
+}
+
+// tooManyRequests responds 429 with a Retry-After header rounded up to whole seconds
+func tooManyRequests(c *fiber.Ctx, retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return fiber.NewError(fiber.StatusTooManyRequests, "Too many requests, please try again later")
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"proofofpeacemaking/internal/logging"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// validRequestID limits IDs accepted from upstream proxies to something safe to log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9_\-.]{1,64}$`)
+
+// RequestID tags each request with an ID, reusing a valid X-Request-ID from a
+// proxy. The ID is echoed in the response and carried by the request context,
+// so every log record written while handling the request includes it.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set(fiber.HeaderXRequestID, id)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+		return c.Next()
+	}
+}
+
+// AccessLog writes one record per request once the response is ready
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		switch {
+		case err != nil || status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+		slog.Log(c.UserContext(), level, "request", attrs...)
+		return err
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleMiddleware restricts routes based on the authenticated user's account. It
+// must run after AuthMiddleware.Authenticate, which identifies the user.
+type RoleMiddleware struct {
+	userService ports.UserService
+}
+
+func NewRoleMiddleware(userService ports.UserService) *RoleMiddleware {
+	return &RoleMiddleware{
+		userService: userService,
+	}
+}
+
+// Require allows the request through only if the user has role or a more privileged one
+func (m *RoleMiddleware) Require(role domain.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := m.currentUser(c)
+		if user == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Not authenticated")
+		}
+
+		if !user.HasRole(role) {
+			slog.WarnContext(c.UserContext(), "user lacks required role", "user_id", user.ID.Hex(), "role", role)
+			return fiber.NewError(fiber.StatusForbidden, "Insufficient permissions")
+		}
+
+		c.Locals("userRole", user.EffectiveRole())
+		return c.Next()
+	}
+}
+
+// RequireNotSuspended blocks users a moderator has suspended from posting
+func (m *RoleMiddleware) RequireNotSuspended() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := m.currentUser(c)
+		if user == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Not authenticated")
+		}
+
+		if user.IsSuspended() {
+			return domain.ErrUserSuspended
+		}
+
+		return c.Next()
+	}
+}
+
+// currentUser loads the user identified by the auth middleware, or nil if there is none
+func (m *RoleMiddleware) currentUser(c *fiber.Ctx) *domain.User {
+	userIdentifier, _ := c.Locals("userAddress").(string)
+	if userIdentifier == "" {
+		return nil
+	}
+
+	var user *domain.User
+	var err error
+	if strings.Contains(userIdentifier, "@") {
+		user, err = m.userService.GetUserByEmail(c.UserContext(), userIdentifier)
+	} else {
+		user, err = m.userService.GetUserByAddress(c.UserContext(), userIdentifier)
+	}
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "could not load user", "identifier", userIdentifier, "error", err)
+		return nil
+	}
+	return user
+}
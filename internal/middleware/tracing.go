@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"proofofpeacemaking/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing opens a server span for each request, continuing the trace from an
+// incoming traceparent header. The span rides on the user context, so spans
+// opened by services, repositories and storage nest under it.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		carrier := propagation.HeaderCarrier(http.Header(c.GetReqHeaders()))
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), carrier)
+
+		ctx, span := tracing.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.ClientAddress(c.IP()),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// Name the span after the route pattern once routing has happened
+		route := c.Route().Path
+		if c.Route().Method != "USE" {
+			span.SetName(c.Method() + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			span.RecordError(err)
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		return err
+	}
+}
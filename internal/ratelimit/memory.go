@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"proofofpeacemaking/internal/core/ports"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired counters and locks are dropped
+const sweepInterval = time.Minute
+
+type counter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStore keeps rate limit state in process memory. State is lost on
+// restart and not shared between instances.
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	locks     map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty in-memory rate limit store
+func NewMemoryStore() ports.RateLimitStore {
+	return &MemoryStore{
+		counters:  make(map[string]*counter),
+		locks:     make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	entry, ok := s.counters[key]
+	if !ok || !entry.expiresAt.After(now) {
+		entry = &counter{expiresAt: now.Add(window)}
+		s.counters[key] = entry
+	}
+	entry.count++
+	return entry.count, entry.expiresAt, nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = until
+	return nil
+}
+
+func (s *MemoryStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.locks[key]
+	if !ok || !until.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// sweep drops expired entries so keys from one-off visitors do not pile up.
+// Callers must hold the mutex.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.counters {
+		if !entry.expiresAt.After(now) {
+			delete(s.counters, key)
+		}
+	}
+	for key, until := range s.locks {
+		if !until.After(now) {
+			delete(s.locks, key)
+		}
+	}
+}
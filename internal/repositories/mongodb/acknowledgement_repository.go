@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type acknowledgementRepository struct {
@@ -28,6 +30,23 @@ func (r *acknowledgementRepository) Create(ctx context.Context, acknowledgement
 	return nil
 }
 
+func (r *acknowledgementRepository) FindByID(ctx context.Context, id string) (*domain.Acknowledgement, error) {
+	objectID, err := parseID(id, "acknowledgement")
+	if err != nil {
+		return nil, err
+	}
+
+	var acknowledgement domain.Acknowledgement
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&acknowledgement)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find acknowledgement: %w", err)
+	}
+	return &acknowledgement, nil
+}
+
 func (r *acknowledgementRepository) FindByExpression(ctx context.Context, expressionID string) ([]*domain.Acknowledgement, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{"expressionId": expressionID})
 	if err != nil {
@@ -72,6 +91,23 @@ func (r *acknowledgementRepository) Update(ctx context.Context, acknowledgement
 	return nil
 }
 
+// SetModerationStatus changes whether an acknowledgement is publicly visible
+func (r *acknowledgementRepository) SetModerationStatus(ctx context.Context, id string, status domain.ModerationStatus) error {
+	objectID, err := parseID(id, "acknowledgement")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{
+		"moderationStatus": status,
+		"updatedAt":        time.Now(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update acknowledgement moderation status: %w", err)
+	}
+	return nil
+}
+
 func (r *acknowledgementRepository) FindByStatus(ctx context.Context, status domain.AcknowledgementStatus) ([]*domain.Acknowledgement, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{"status": status})
 	if err != nil {
@@ -86,3 +122,20 @@ func (r *acknowledgementRepository) FindByStatus(ctx context.Context, status dom
 
 	return acknowledgements, nil
 }
+
+func (r *acknowledgementRepository) FindCreatedBetween(ctx context.Context, after, until time.Time) ([]*domain.Acknowledgement, error) {
+	filter := bson.M{"createdAt": bson.M{"$gt": after, "$lte": until}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find acknowledgements by creation time: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var acknowledgements []*domain.Acknowledgement
+	if err := cursor.All(ctx, &acknowledgements); err != nil {
+		return nil, fmt.Errorf("failed to decode acknowledgements: %w", err)
+	}
+
+	return acknowledgements, nil
+}
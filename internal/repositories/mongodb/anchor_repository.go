@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type anchorRepository struct {
+	batches *mongo.Collection
+	proofs  *mongo.Collection
+}
+
+// NewAnchorRepository creates a new MongoDB anchor repository
+func NewAnchorRepository(db *mongo.Database) ports.AnchorRepository {
+	return &anchorRepository{
+		batches: db.Collection("anchor_batches"),
+		proofs:  db.Collection("anchor_proofs"),
+	}
+}
+
+func (r *anchorRepository) CreateBatch(ctx context.Context, batch *domain.AnchorBatch) error {
+	if batch.ID.IsZero() {
+		batch.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	batch.CreatedAt = now
+	batch.UpdatedAt = now
+
+	if _, err := r.batches.InsertOne(ctx, batch); err != nil {
+		return fmt.Errorf("failed to create anchor batch: %w", err)
+	}
+	return nil
+}
+
+func (r *anchorRepository) UpdateBatch(ctx context.Context, batch *domain.AnchorBatch) error {
+	batch.UpdatedAt = time.Now()
+	if _, err := r.batches.ReplaceOne(ctx, bson.M{"_id": batch.ID}, batch); err != nil {
+		return fmt.Errorf("failed to update anchor batch: %w", err)
+	}
+	return nil
+}
+
+func (r *anchorRepository) FindBatchByID(ctx context.Context, id primitive.ObjectID) (*domain.AnchorBatch, error) {
+	return r.findBatch(ctx, bson.M{"_id": id}, nil)
+}
+
+func (r *anchorRepository) LatestBatch(ctx context.Context) (*domain.AnchorBatch, error) {
+	return r.findBatch(ctx, bson.M{}, options.FindOne().SetSort(bson.D{{Key: "windowEnd", Value: -1}}))
+}
+
+func (r *anchorRepository) findBatch(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*domain.AnchorBatch, error) {
+	var batch domain.AnchorBatch
+	err := r.batches.FindOne(ctx, filter, opts).Decode(&batch)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find anchor batch: %w", err)
+	}
+	return &batch, nil
+}
+
+func (r *anchorRepository) FindBatchesByStatus(ctx context.Context, status domain.AnchorStatus) ([]*domain.AnchorBatch, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "windowEnd", Value: 1}})
+	cursor, err := r.batches.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find anchor batches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var batches []*domain.AnchorBatch
+	if err := cursor.All(ctx, &batches); err != nil {
+		return nil, fmt.Errorf("failed to decode anchor batches: %w", err)
+	}
+	return batches, nil
+}
+
+func (r *anchorRepository) SaveProofs(ctx context.Context, proofs []*domain.AnchorProof) error {
+	if len(proofs) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(proofs))
+	for _, proof := range proofs {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"documentId": proof.DocumentID}).
+			SetReplacement(proof).
+			SetUpsert(true))
+	}
+
+	if _, err := r.proofs.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to save anchor proofs: %w", err)
+	}
+	return nil
+}
+
+func (r *anchorRepository) FindProof(ctx context.Context, documentID string) (*domain.AnchorProof, error) {
+	var proof domain.AnchorProof
+	err := r.proofs.FindOne(ctx, bson.M{"documentId": documentID}).Decode(&proof)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find anchor proof: %w", err)
+	}
+	return &proof, nil
+}
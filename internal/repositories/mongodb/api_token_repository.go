@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type apiTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPITokenRepository creates a new MongoDB personal access token repository
+func NewAPITokenRepository(db *mongo.Database) ports.APITokenRepository {
+	return &apiTokenRepository{
+		collection: db.Collection("api_tokens"),
+	}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *domain.APIToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	var token domain.APIToken
+	err := r.collection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []*domain.APIToken
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, userID, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "userId": userID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"lastUsedAt": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("failed to update api token last used: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type chainCursorRepository struct {
+	collection *mongo.Collection
+}
+
+// NewChainCursorRepository creates a new MongoDB chain cursor repository
+func NewChainCursorRepository(db *mongo.Database) ports.ChainCursorRepository {
+	return &chainCursorRepository{
+		collection: db.Collection("chain_cursors"),
+	}
+}
+
+func (r *chainCursorRepository) Get(ctx context.Context, name string) (uint64, bool, error) {
+	var cursor struct {
+		NextBlock int64 `bson:"nextBlock"`
+	}
+	err := r.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&cursor)
+	if err == mongo.ErrNoDocuments {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find chain cursor: %w", err)
+	}
+	return uint64(cursor.NextBlock), true, nil
+}
+
+func (r *chainCursorRepository) Set(ctx context.Context, name string, nextBlock uint64) error {
+	update := bson.M{"$set": bson.M{"nextBlock": int64(nextBlock), "updatedAt": time.Now()}}
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": name}, update, opts); err != nil {
+		return fmt.Errorf("failed to save chain cursor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type credentialRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCredentialRepository creates a new MongoDB credential repository
+func NewCredentialRepository(db *mongo.Database) ports.CredentialRepository {
+	return &credentialRepository{
+		collection: db.Collection("credentials"),
+	}
+}
+
+func (r *credentialRepository) Create(ctx context.Context, credential *domain.Credential) error {
+	if credential.ID.IsZero() {
+		credential.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, credential)
+	if dup := duplicateKeyError(err); dup != nil {
+		return dup
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create credential: %w", err)
+	}
+	return nil
+}
+
+func (r *credentialRepository) FindByID(ctx context.Context, id string) (*domain.Credential, error) {
+	objectID, err := parseID(id, "credential")
+	if err != nil {
+		return nil, err
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+func (r *credentialRepository) FindBySource(ctx context.Context, sourceID string) (*domain.Credential, error) {
+	return r.findOne(ctx, bson.M{"sourceId": sourceID})
+}
+
+func (r *credentialRepository) findOne(ctx context.Context, filter bson.M) (*domain.Credential, error) {
+	var credential domain.Credential
+	err := r.collection.FindOne(ctx, filter).Decode(&credential)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credential: %w", err)
+	}
+	return &credential, nil
+}
+
+func (r *credentialRepository) FindByHolder(ctx context.Context, userID string) ([]*domain.Credential, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "issuedAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"holderIds": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credentials: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var credentials []*domain.Credential
+	if err := cursor.All(ctx, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials: %w", err)
+	}
+	return credentials, nil
+}
@@ -3,8 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -12,44 +11,45 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func Connect() *mongo.Database {
-	log.Printf("[DB] Connecting to MongoDB...")
+// Connect opens the database at uri, checks it answers and ensures indexes.
+// Every command is timed for the metrics endpoint.
+func Connect(uri, database string) (*mongo.Database, error) {
+	slog.Info("connecting to MongoDB")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	uri := os.Getenv("MONGODB_URI")
 	// Connect to MongoDB
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(newCommandMonitor()))
 	if err != nil {
-		log.Fatalf("[DB] Failed to connect to MongoDB: %v", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
 	// Ping the database
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
-		log.Fatalf("[DB] Failed to ping MongoDB: %v", err)
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	log.Printf("[DB] Successfully connected to MongoDB")
-	db := client.Database("proof-of-peacemaking")
+	slog.Info("connected to MongoDB")
+	db := client.Database(database)
 
 	// Drop and recreate indexes
 	if err := dropAndRecreateIndexes(ctx, db); err != nil {
-		log.Fatalf("[DB] Failed to recreate indexes: %v", err)
+		return nil, fmt.Errorf("failed to recreate indexes: %w", err)
 	}
 
-	return db
+	return db, nil
 }
 
 func dropAndRecreateIndexes(ctx context.Context, db *mongo.Database) error {
 	// Drop all indexes from users collection
-	log.Printf("[DB] Dropping all indexes from users collection...")
+	slog.Info("dropping all indexes from users collection")
 	_, err := db.Collection("users").Indexes().DropAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop indexes: %w", err)
 	}
 
 	// Recreate indexes
-	log.Printf("[DB] Recreating indexes...")
+	slog.Info("recreating indexes")
 	if err := createIndexes(ctx, db); err != nil {
 		return fmt.Errorf("failed to recreate indexes: %w", err)
 	}
@@ -74,11 +74,130 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 				{Name: "createdAt", Order: -1},
 			},
 		},
+		{
+			Collection: "sessions",
+			Fields: []IndexField{
+				{Name: "tokenHash", Order: 1, Unique: true, Sparse: true},
+				{Name: "userId", Order: 1},
+				{Name: "expiresAt", Order: 1, TTL: true},
+			},
+		},
+		{
+			Collection: "api_tokens",
+			Fields: []IndexField{
+				{Name: "tokenHash", Order: 1, Unique: true},
+				{Name: "userId", Order: 1},
+			},
+		},
+		{
+			Collection: "relayed_transactions",
+			Fields: []IndexField{
+				{Name: "requestKey", Order: 1, Unique: true},
+				{Name: "userId", Order: 1},
+				{Name: "status", Order: 1},
+				{Name: "request.from", Order: 1},
+			},
+		},
+		{
+			Collection: "anchor_batches",
+			Fields: []IndexField{
+				{Name: "status", Order: 1},
+				{Name: "windowEnd", Order: -1},
+			},
+		},
+		{
+			Collection: "anchor_proofs",
+			Fields: []IndexField{
+				{Name: "documentId", Order: 1, Unique: true},
+				{Name: "batchId", Order: 1},
+			},
+		},
+		{
+			Collection: "expression_acknowledgements",
+			Fields: []IndexField{
+				{Name: "acknowledgementId", Order: 1, Unique: true},
+				{Name: "expressionId", Order: 1},
+				{Name: "creatorId", Order: 1},
+				{Name: "acknowledgerId", Order: 1},
+				{Name: "nftStatus", Order: 1},
+			},
+		},
+		{
+			Collection: "invitations",
+			Fields: []IndexField{
+				{Name: "expressionId", Order: 1},
+				{Name: "creatorId", Order: 1},
+				{Name: "inviteeId", Order: 1},
+				{Name: "invitee", Order: 1},
+				{Name: "expiresAt", Order: 1},
+			},
+		},
+		{
+			Collection: "replies",
+			Fields: []IndexField{
+				{Name: "acknowledgementId", Order: 1},
+				{Name: "authorId", Order: 1},
+			},
+		},
+		{
+			Collection: "proof_requests",
+			Fields: []IndexField{
+				{Name: "expressionAcknowledgementId", Order: 1},
+			},
+		},
+		{
+			Collection: "proofnfts",
+			Fields: []IndexField{
+				{Name: "expressionId", Order: 1},
+				{Name: "acknowledgerId", Order: 1},
+			},
+		},
+		{
+			Collection: "credentials",
+			Fields: []IndexField{
+				{Name: "sourceId", Order: 1, Unique: true},
+				{Name: "holderIds", Order: 1},
+			},
+		},
+		{
+			Collection: "subsidy_changes",
+			Fields: []IndexField{
+				{Name: "status", Order: 1},
+				{Name: "userId", Order: 1},
+			},
+		},
+		{
+			Collection: "reports",
+			Fields: []IndexField{
+				{Name: "caseId", Order: 1},
+				{Name: "reporterId", Order: 1},
+			},
+		},
+		{
+			Collection: "moderation_cases",
+			Fields: []IndexField{
+				{Name: "status", Order: 1},
+				{Name: "contentId", Order: 1},
+			},
+		},
+		{
+			Collection: "moderation_log",
+			Fields: []IndexField{
+				{Name: "caseId", Order: 1},
+			},
+		},
+		{
+			Collection: "rate_limits",
+			Fields: []IndexField{
+				{Name: "expiresAt", Order: 1, TTL: true},
+			},
+		},
 		{
 			Collection: "acknowledgements",
 			Fields: []IndexField{
 				{Name: "expressionId", Order: 1, Compound: true},
 				{Name: "acknowledger", Order: 1, Compound: true},
+				{Name: "createdAt", Order: 1},
 			},
 		},
 	}
@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"errors"
+	"proofofpeacemaking/internal/core/domain"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// parseID converts an ID taken from a request, reporting a malformed one as a
+// validation error. what names the ID in the message, e.g. "expression".
+func parseID(id, what string) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, domain.WrapError(domain.ErrValidation, err, "invalid %s ID format", what)
+	}
+	return objectID, nil
+}
+
+// duplicateIndex matches the index name in a duplicate key error message
+var duplicateIndex = regexp.MustCompile(`index: (\w+)`)
+
+// duplicateKeyError reports a unique index violation as a conflict naming the
+// field, or returns nil if err is something else
+func duplicateKeyError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	field := "record"
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		if m := duplicateIndex.FindStringSubmatch(serverErr.Error()); m != nil {
+			field = strings.TrimSuffix(m[1], "_1")
+		}
+	}
+	return domain.WrapError(domain.ErrConflict, err, "%s already exists", field)
+}
@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type expressionAcknowledgementRepository struct {
+	collection *mongo.Collection
+}
+
+// NewExpressionAcknowledgementRepository creates a new MongoDB pairing repository
+func NewExpressionAcknowledgementRepository(db *mongo.Database) ports.ExpressionAcknowledgementRepository {
+	return &expressionAcknowledgementRepository{
+		collection: db.Collection("expression_acknowledgements"),
+	}
+}
+
+func (r *expressionAcknowledgementRepository) Create(ctx context.Context, pairing *domain.ExpressionAcknowledgement) error {
+	if pairing.ID.IsZero() {
+		pairing.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	pairing.CreatedAt = now
+	pairing.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, pairing)
+	if dup := duplicateKeyError(err); dup != nil {
+		return dup
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create expression acknowledgement: %w", err)
+	}
+	return nil
+}
+
+func (r *expressionAcknowledgementRepository) Update(ctx context.Context, pairing *domain.ExpressionAcknowledgement, from domain.NFTStatus) error {
+	pairing.UpdatedAt = time.Now()
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": pairing.ID, "nftStatus": from}, pairing)
+	if err != nil {
+		return fmt.Errorf("failed to update expression acknowledgement: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrPairingNFTStatus
+	}
+	return nil
+}
+
+func (r *expressionAcknowledgementRepository) FindByID(ctx context.Context, id string) (*domain.ExpressionAcknowledgement, error) {
+	objectID, err := parseID(id, "expression acknowledgement")
+	if err != nil {
+		return nil, err
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+func (r *expressionAcknowledgementRepository) FindByAcknowledgement(ctx context.Context, acknowledgementID string) (*domain.ExpressionAcknowledgement, error) {
+	return r.findOne(ctx, bson.M{"acknowledgementId": acknowledgementID})
+}
+
+func (r *expressionAcknowledgementRepository) findOne(ctx context.Context, filter bson.M) (*domain.ExpressionAcknowledgement, error) {
+	var pairing domain.ExpressionAcknowledgement
+	err := r.collection.FindOne(ctx, filter).Decode(&pairing)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expression acknowledgement: %w", err)
+	}
+	return &pairing, nil
+}
+
+func (r *expressionAcknowledgementRepository) FindByUser(ctx context.Context, userID string) ([]*domain.ExpressionAcknowledgement, error) {
+	filter := bson.M{"$or": []bson.M{{"creatorId": userID}, {"acknowledgerId": userID}}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expression acknowledgements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var pairings []*domain.ExpressionAcknowledgement
+	if err := cursor.All(ctx, &pairings); err != nil {
+		return nil, fmt.Errorf("failed to decode expression acknowledgements: %w", err)
+	}
+	return pairings, nil
+}
+
+func (r *expressionAcknowledgementRepository) FindByNFTStatus(ctx context.Context, status domain.NFTStatus) ([]*domain.ExpressionAcknowledgement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"nftStatus": status}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expression acknowledgements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var pairings []*domain.ExpressionAcknowledgement
+	if err := cursor.All(ctx, &pairings); err != nil {
+		return nil, fmt.Errorf("failed to decode expression acknowledgements: %w", err)
+	}
+	return pairings, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -31,9 +32,9 @@ func (r *expressionRepository) Create(ctx context.Context, expression *domain.Ex
 }
 
 func (r *expressionRepository) FindByID(ctx context.Context, id string) (*domain.Expression, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseID(id, "expression")
 	if err != nil {
-		return nil, fmt.Errorf("invalid expression ID format: %w", err)
+		return nil, err
 	}
 
 	var expression domain.Expression
@@ -82,9 +83,9 @@ func (r *expressionRepository) FindByIDs(ctx context.Context, ids []string) ([]*
 	// Convert string IDs to ObjectIDs
 	objectIDs := make([]primitive.ObjectID, 0, len(ids))
 	for _, id := range ids {
-		objectID, err := primitive.ObjectIDFromHex(id)
+		objectID, err := parseID(id, "expression")
 		if err != nil {
-			return nil, fmt.Errorf("invalid expression ID format: %w", err)
+			return nil, err
 		}
 		objectIDs = append(objectIDs, objectID)
 	}
@@ -104,11 +105,28 @@ func (r *expressionRepository) FindByIDs(ctx context.Context, ids []string) ([]*
 	return expressions, nil
 }
 
+// SetModerationStatus changes whether an expression is publicly visible
+func (r *expressionRepository) SetModerationStatus(ctx context.Context, id string, status domain.ModerationStatus) error {
+	objectID, err := parseID(id, "expression")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{
+		"moderationStatus": status,
+		"updatedAt":        time.Now(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update expression moderation status: %w", err)
+	}
+	return nil
+}
+
 // Delete removes an expression by its ID
 func (r *expressionRepository) Delete(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseID(id, "expression")
 	if err != nil {
-		return fmt.Errorf("invalid expression ID format: %w", err)
+		return err
 	}
 
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
@@ -117,7 +135,7 @@ func (r *expressionRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("expression not found")
+		return domain.NotFound("expression not found")
 	}
 
 	return nil
@@ -125,9 +143,9 @@ func (r *expressionRepository) Delete(ctx context.Context, id string) error {
 
 // GetByUserID returns all expressions created by a specific user
 func (r *expressionRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Expression, error) {
-	objectID, err := primitive.ObjectIDFromHex(userID)
+	objectID, err := parseID(userID, "user")
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID format: %w", err)
+		return nil, err
 	}
 
 	cursor, err := r.collection.Find(ctx, bson.M{"creator": objectID})
@@ -246,8 +264,25 @@ func (r *expressionRepository) Update(ctx context.Context, expression *domain.Ex
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("expression not found")
+		return domain.NotFound("expression not found")
 	}
 
 	return nil
 }
+
+func (r *expressionRepository) FindCreatedBetween(ctx context.Context, after, until time.Time) ([]*domain.Expression, error) {
+	filter := bson.M{"createdAt": bson.M{"$gt": after, "$lte": until}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expressions by creation time: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var expressions []*domain.Expression
+	if err := cursor.All(ctx, &expressions); err != nil {
+		return nil, fmt.Errorf("failed to decode expressions: %w", err)
+	}
+
+	return expressions, nil
+}
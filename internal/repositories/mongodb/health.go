@@ -0,0 +1,26 @@
+package mongodb
+
+import (
+	"context"
+	"proofofpeacemaking/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type healthCheck struct {
+	client *mongo.Client
+}
+
+// NewHealthCheck reports whether the primary answers a ping
+func NewHealthCheck(db *mongo.Database) ports.HealthCheck {
+	return &healthCheck{client: db.Client()}
+}
+
+func (h *healthCheck) Name() string {
+	return "mongo"
+}
+
+func (h *healthCheck) Check(ctx context.Context) error {
+	return h.client.Ping(ctx, readpref.Primary())
+}
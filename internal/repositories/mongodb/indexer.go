@@ -3,7 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -21,6 +21,7 @@ type IndexField struct {
 	Unique   bool
 	Compound bool // true if this field is part of a compound index
 	Sparse   bool // true if this index should ignore null values
+	TTL      bool // true if documents should expire at the time stored in this field
 }
 
 func EnsureIndexes(ctx context.Context, db *mongo.Database, configs []IndexConfig) error {
@@ -42,7 +43,7 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database, configs []IndexConfi
 
 		// Create compound indexes
 		for _, fields := range compoundFields {
-			if err := createIndex(ctx, indexView, fields, false, false, config.Collection); err != nil {
+			if err := createIndex(ctx, indexView, fields, false, false, false, config.Collection); err != nil {
 				return err
 			}
 		}
@@ -50,7 +51,7 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database, configs []IndexConfi
 		// Create single field indexes
 		for _, field := range singleFields {
 			keys := bson.D{{Key: field.Name, Value: field.Order}}
-			if err := createIndex(ctx, indexView, keys, field.Unique, field.Sparse, config.Collection); err != nil {
+			if err := createIndex(ctx, indexView, keys, field.Unique, field.Sparse, field.TTL, config.Collection); err != nil {
 				return err
 			}
 		}
@@ -59,7 +60,7 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database, configs []IndexConfi
 	return nil
 }
 
-func createIndex(ctx context.Context, indexView mongo.IndexView, keys bson.D, unique bool, sparse bool, collection string) error {
+func createIndex(ctx context.Context, indexView mongo.IndexView, keys bson.D, unique bool, sparse bool, ttl bool, collection string) error {
 	// Check if index already exists
 	cursor, err := indexView.List(ctx)
 	if err != nil {
@@ -83,9 +84,13 @@ func createIndex(ctx context.Context, indexView mongo.IndexView, keys bson.D, un
 	}
 
 	// Create index
+	indexOptions := options.Index().SetUnique(unique).SetSparse(sparse)
+	if ttl {
+		indexOptions.SetExpireAfterSeconds(0)
+	}
 	_, err = indexView.CreateOne(ctx, mongo.IndexModel{
 		Keys:    keys,
-		Options: options.Index().SetUnique(unique).SetSparse(sparse),
+		Options: indexOptions,
 	})
 	if err != nil {
 		return err
@@ -104,7 +109,7 @@ func createIndex(ctx context.Context, indexView mongo.IndexView, keys bson.D, un
 		keyInfo += fmt.Sprintf("%s:%s", key.Key, order)
 	}
 
-	log.Printf("[DB] Created new index '%s' on collection '%s' (%s)", indexName, collection, keyInfo)
+	slog.Info("created index", "index", indexName, "collection", collection, "keys", keyInfo)
 	return nil
 }
 
@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type invitationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewInvitationRepository creates a new MongoDB invitation repository
+func NewInvitationRepository(db *mongo.Database) ports.InvitationRepository {
+	return &invitationRepository{
+		collection: db.Collection("invitations"),
+	}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	if invitation.ID.IsZero() {
+		invitation.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	invitation.CreatedAt = now
+	invitation.UpdatedAt = now
+
+	if _, err := r.collection.InsertOne(ctx, invitation); err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *invitationRepository) Update(ctx context.Context, invitation *domain.Invitation, from domain.InvitationStatus) error {
+	invitation.UpdatedAt = time.Now()
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": invitation.ID, "status": from}, invitation)
+	if err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvitationNotPending
+	}
+	return nil
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id string) (*domain.Invitation, error) {
+	objectID, err := parseID(id, "invitation")
+	if err != nil {
+		return nil, err
+	}
+
+	var invitation domain.Invitation
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&invitation)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByCreator(ctx context.Context, userID string) ([]*domain.Invitation, error) {
+	return r.find(ctx, bson.M{"creatorId": userID})
+}
+
+func (r *invitationRepository) FindByInviteeID(ctx context.Context, userID string) ([]*domain.Invitation, error) {
+	return r.find(ctx, bson.M{"inviteeId": userID})
+}
+
+func (r *invitationRepository) FindPending(ctx context.Context, expressionID string) ([]*domain.Invitation, error) {
+	return r.find(ctx, bson.M{"expressionId": expressionID, "status": domain.InvitationPending})
+}
+
+func (r *invitationRepository) FindUnlinked(ctx context.Context, kind domain.InviteeKind, invitee string) ([]*domain.Invitation, error) {
+	return r.find(ctx, bson.M{
+		"inviteeKind": kind,
+		"invitee":     invitee,
+		"status":      domain.InvitationPending,
+		"inviteeId":   bson.M{"$exists": false},
+	})
+}
+
+func (r *invitationRepository) ExpireLapsed(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"status": domain.InvitationPending, "expiresAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"status": domain.InvitationExpired, "updatedAt": now}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire invitations: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *invitationRepository) find(ctx context.Context, filter bson.M) ([]*domain.Invitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []*domain.Invitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %w", err)
+	}
+	return invitations, nil
+}
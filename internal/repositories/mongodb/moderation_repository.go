@@ -0,0 +1,197 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type moderationRepository struct {
+	reportsColl *mongo.Collection
+	casesColl   *mongo.Collection
+	logColl     *mongo.Collection
+}
+
+// NewModerationRepository creates a new MongoDB moderation repository
+func NewModerationRepository(db *mongo.Database) ports.ModerationRepository {
+	return &moderationRepository{
+		reportsColl: db.Collection("reports"),
+		casesColl:   db.Collection("moderation_cases"),
+		logColl:     db.Collection("moderation_log"),
+	}
+}
+
+func (r *moderationRepository) CreateReport(ctx context.Context, report *domain.Report) error {
+	if report.ID.IsZero() {
+		report.ID = primitive.NewObjectID()
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now()
+	}
+
+	_, err := r.reportsColl.InsertOne(ctx, report)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	return nil
+}
+
+func (r *moderationRepository) HasReported(ctx context.Context, reporterID primitive.ObjectID, contentType domain.ContentType, contentID string) (bool, error) {
+	count, err := r.reportsColl.CountDocuments(ctx, bson.M{
+		"reporterId":  reporterID,
+		"contentType": contentType,
+		"contentId":   contentID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing reports: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *moderationRepository) FindReportsByCase(ctx context.Context, caseID primitive.ObjectID) ([]*domain.Report, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.reportsColl.Find(ctx, bson.M{"caseId": caseID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reports []*domain.Report
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode reports: %w", err)
+	}
+	return reports, nil
+}
+
+func (r *moderationRepository) CreateCase(ctx context.Context, moderationCase *domain.ModerationCase) error {
+	if moderationCase.ID.IsZero() {
+		moderationCase.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	moderationCase.CreatedAt = now
+	moderationCase.UpdatedAt = now
+
+	_, err := r.casesColl.InsertOne(ctx, moderationCase)
+	if err != nil {
+		return fmt.Errorf("failed to create moderation case: %w", err)
+	}
+	return nil
+}
+
+func (r *moderationRepository) FindCaseByID(ctx context.Context, id primitive.ObjectID) (*domain.ModerationCase, error) {
+	var moderationCase domain.ModerationCase
+	err := r.casesColl.FindOne(ctx, bson.M{"_id": id}).Decode(&moderationCase)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find moderation case: %w", err)
+	}
+	return &moderationCase, nil
+}
+
+func (r *moderationRepository) FindActiveCase(ctx context.Context, contentType domain.ContentType, contentID string) (*domain.ModerationCase, error) {
+	var moderationCase domain.ModerationCase
+	err := r.casesColl.FindOne(ctx, bson.M{
+		"contentType": contentType,
+		"contentId":   contentID,
+		"status":      bson.M{"$ne": domain.CaseStatusResolved},
+	}).Decode(&moderationCase)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active moderation case: %w", err)
+	}
+	return &moderationCase, nil
+}
+
+func (r *moderationRepository) FindCasesByStatus(ctx context.Context, statuses []domain.CaseStatus) ([]*domain.ModerationCase, error) {
+	// Most-reported cases first, then oldest, so the queue surfaces the worst content
+	opts := options.Find().SetSort(bson.D{
+		{Key: "reportCount", Value: -1},
+		{Key: "createdAt", Value: 1},
+	})
+	cursor, err := r.casesColl.Find(ctx, bson.M{"status": bson.M{"$in": statuses}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find moderation cases: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cases []*domain.ModerationCase
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation cases: %w", err)
+	}
+	return cases, nil
+}
+
+func (r *moderationRepository) AddReportToCase(ctx context.Context, caseID primitive.ObjectID, reason domain.ReportReason) error {
+	_, err := r.casesColl.UpdateOne(ctx, bson.M{"_id": caseID}, bson.M{
+		"$inc":      bson.M{"reportCount": 1},
+		"$addToSet": bson.M{"reasons": reason},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add report to case: %w", err)
+	}
+	return nil
+}
+
+func (r *moderationRepository) UpdateCase(ctx context.Context, moderationCase *domain.ModerationCase, from domain.CaseStatus, claimedBy *primitive.ObjectID) error {
+	moderationCase.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"status":     moderationCase.Status,
+		"claimedBy":  moderationCase.ClaimedBy,
+		"resolution": moderationCase.Resolution,
+		"resolvedAt": moderationCase.ResolvedAt,
+		"updatedAt":  moderationCase.UpdatedAt,
+	}}
+
+	// A nil claimant matches cases where claimedBy is null or missing
+	filter := bson.M{"_id": moderationCase.ID, "status": from, "claimedBy": claimedBy}
+	result, err := r.casesColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update moderation case: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidCaseTransition
+	}
+	return nil
+}
+
+func (r *moderationRepository) AppendLog(ctx context.Context, entry *domain.ModerationLogEntry) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := r.logColl.InsertOne(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("failed to append moderation log: %w", err)
+	}
+	return nil
+}
+
+func (r *moderationRepository) FindLogByCase(ctx context.Context, caseID primitive.ObjectID) ([]*domain.ModerationLogEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.logColl.Find(ctx, bson.M{"caseId": caseID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find moderation log: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.ModerationLogEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation log: %w", err)
+	}
+	return entries, nil
+}
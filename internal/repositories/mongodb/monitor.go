@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"proofofpeacemaking/internal/metrics"
+	"proofofpeacemaking/internal/tracing"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// inflightCommand is what the started event knows that later events do not
+type inflightCommand struct {
+	collection string
+	span       trace.Span
+}
+
+// newCommandMonitor times and traces every command the repositories send,
+// labelled by collection and command name. Spans are children of the span in
+// the context the repository passed to the driver.
+func newCommandMonitor() *event.CommandMonitor {
+	var inflight sync.Map
+
+	finish := func(requestID int64, command string, seconds float64, failure string) {
+		collection := "admin"
+		if value, ok := inflight.LoadAndDelete(requestID); ok {
+			cmd := value.(*inflightCommand)
+			if cmd.collection != "" {
+				collection = cmd.collection
+			}
+			if failure != "" {
+				cmd.span.SetStatus(codes.Error, failure)
+			}
+			cmd.span.End()
+		}
+
+		outcome := "success"
+		if failure != "" {
+			outcome = "failure"
+		}
+		metrics.MongoOperationDuration.WithLabelValues(collection, command, outcome).Observe(seconds)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			// Collection names are only present on the started event
+			field := e.CommandName
+			if field == "getMore" {
+				field = "collection"
+			}
+			collection, _ := e.Command.Lookup(field).StringValueOK()
+
+			name := e.CommandName
+			if collection != "" {
+				name = collection + "." + e.CommandName
+			}
+			_, span := tracing.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNamespace(e.DatabaseName),
+					semconv.DBOperationName(e.CommandName),
+					semconv.DBCollectionName(collection),
+					attribute.Int64("db.mongodb.request_id", e.RequestID),
+				),
+			)
+			inflight.Store(e.RequestID, &inflightCommand{collection: collection, span: span})
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			finish(e.RequestID, e.CommandName, e.Duration.Seconds(), "")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			finish(e.RequestID, e.CommandName, e.Duration.Seconds(), e.Failure)
+		},
+	}
+}
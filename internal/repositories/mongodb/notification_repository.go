@@ -20,6 +20,9 @@ func NewNotificationRepository(db *mongo.Database) *notificationRepository {
 }
 
 func (r *notificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
 	_, err := r.db.Collection("notifications").InsertOne(ctx, notification)
 	return err
 }
@@ -31,17 +34,17 @@ func (r *notificationRepository) CreateUserNotification(ctx context.Context, un
 
 func (r *notificationRepository) GetUserUnreadNotifications(ctx context.Context, userID primitive.ObjectID) ([]*domain.Notification, error) {
 	pipeline := mongo.Pipeline{
-		{{"$match", bson.M{
+		{{Key: "$match", Value: bson.M{
 			"userId": userID,
 			"read":   false,
 		}}},
-		{{"$lookup", bson.M{
+		{{Key: "$lookup", Value: bson.M{
 			"from":         "notifications",
 			"localField":   "notificationId",
 			"foreignField": "_id",
 			"as":           "notification",
 		}}},
-		{{"$unwind", "$notification"}},
+		{{Key: "$unwind", Value: "$notification"}},
 	}
 
 	cursor, err := r.db.Collection("user_notifications").Aggregate(ctx, pipeline)
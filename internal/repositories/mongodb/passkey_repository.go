@@ -114,6 +114,19 @@ func (r *passkeyRepository) AssignCredentialToUser(ctx context.Context, userPass
 	return nil
 }
 
+func (r *passkeyRepository) GetUserPasskeyByID(ctx context.Context, id primitive.ObjectID) (*domain.UserPasskey, error) {
+	var userPasskey domain.UserPasskey
+	err := r.userPasskeysColl.FindOne(ctx, bson.M{"_id": id}).Decode(&userPasskey)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user passkey: %w", err)
+	}
+
+	return &userPasskey, nil
+}
+
 func (r *passkeyRepository) GetUserPasskeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.UserPasskey, error) {
 	cursor, err := r.userPasskeysColl.Find(ctx, bson.M{"userId": userID})
 	if err != nil {
@@ -179,3 +192,19 @@ func (r *passkeyRepository) UpdateUserPasskeyLastUsed(ctx context.Context, id pr
 
 	return nil
 }
+
+func (r *passkeyRepository) RenameUserPasskey(ctx context.Context, id primitive.ObjectID, name string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":      name,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	_, err := r.userPasskeysColl.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to rename user passkey: %w", err)
+	}
+
+	return nil
+}
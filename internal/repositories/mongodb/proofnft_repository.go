@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -41,9 +42,9 @@ func (r *proofNFTRepository) Update(ctx context.Context, proofNFT *domain.ProofN
 }
 
 func (r *proofNFTRepository) FindByID(ctx context.Context, id string) (*domain.ProofNFT, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseID(id, "proof NFT")
 	if err != nil {
-		return nil, fmt.Errorf("invalid ID format: %w", err)
+		return nil, err
 	}
 
 	var proofNFT domain.ProofNFT
@@ -70,3 +71,88 @@ func (r *proofNFTRepository) FindByAcknowledger(ctx context.Context, acknowledge
 	}
 	return proofNFTs, nil
 }
+
+func (r *proofNFTRepository) FindByExpression(ctx context.Context, expressionID string) ([]*domain.ProofNFT, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"expressionId": expressionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find proof NFTs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var proofNFTs []*domain.ProofNFT
+	if err := cursor.All(ctx, &proofNFTs); err != nil {
+		return nil, fmt.Errorf("failed to decode proof NFTs: %w", err)
+	}
+	return proofNFTs, nil
+}
+
+type proofRequestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewProofRequestRepository(db *mongo.Database) ports.ProofRequestRepository {
+	return &proofRequestRepository{
+		collection: db.Collection("proof_requests"),
+	}
+}
+
+func (r *proofRequestRepository) Create(ctx context.Context, request *domain.ProofRequest) error {
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	request.CreatedAt = now
+	request.UpdatedAt = now
+
+	if _, err := r.collection.InsertOne(ctx, request); err != nil {
+		return fmt.Errorf("failed to create proof request: %w", err)
+	}
+	return nil
+}
+
+func (r *proofRequestRepository) Update(ctx context.Context, request *domain.ProofRequest, from domain.ProofRequestStatus) error {
+	request.UpdatedAt = time.Now()
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": request.ID, "status": from}, request)
+	if err != nil {
+		return fmt.Errorf("failed to update proof request: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrProofRequestNotPending
+	}
+	return nil
+}
+
+func (r *proofRequestRepository) FindByID(ctx context.Context, id string) (*domain.ProofRequest, error) {
+	objectID, err := parseID(id, "proof request")
+	if err != nil {
+		return nil, err
+	}
+
+	var request domain.ProofRequest
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find proof request: %w", err)
+	}
+	return &request, nil
+}
+
+func (r *proofRequestRepository) FindUnpaired(ctx context.Context) ([]*domain.ProofRequest, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"expressionAcknowledgementId": bson.M{"$exists": false}},
+		{"expressionAcknowledgementId": ""},
+	}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unpaired proof requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var requests []*domain.ProofRequest
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, fmt.Errorf("failed to decode proof requests: %w", err)
+	}
+	return requests, nil
+}
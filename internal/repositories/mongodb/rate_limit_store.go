@@ -0,0 +1,87 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// lockKeyPrefix separates lock documents from counters sharing the same key
+const lockKeyPrefix = "lock:"
+
+type rateLimitStore struct {
+	collection *mongo.Collection
+}
+
+// NewRateLimitStore creates a rate limit store shared by every server instance.
+// Expired counters and locks are removed by a TTL index on expiresAt.
+func NewRateLimitStore(db *mongo.Database) ports.RateLimitStore {
+	return &rateLimitStore{
+		collection: db.Collection("rate_limits"),
+	}
+}
+
+func (s *rateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now()
+	// The TTL monitor only runs once a minute, so an expired window is
+	// restarted here rather than relying on the document being gone
+	active := bson.M{"$gt": bson.A{"$expiresAt", now}}
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"count":     bson.M{"$cond": bson.A{active, bson.M{"$add": bson.A{"$count", 1}}, 1}},
+			"expiresAt": bson.M{"$cond": bson.A{active, "$expiresAt", now.Add(window)}},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result struct {
+		Count     int       `bson:"count"`
+		ExpiresAt time.Time `bson:"expiresAt"`
+	}
+	if err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&result); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+	return result.Count, result.ExpiresAt, nil
+}
+
+func (s *rateLimitStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": lockKeyPrefix + key},
+		bson.M{"$set": bson.M{"expiresAt": until}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *rateLimitStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var lock struct {
+		ExpiresAt time.Time `bson:"expiresAt"`
+	}
+	err := s.collection.FindOne(ctx, bson.M{"_id": lockKeyPrefix + key}).Decode(&lock)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find lock: %w", err)
+	}
+	if !lock.ExpiresAt.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return lock.ExpiresAt, nil
+}
+
+func (s *rateLimitStore) Reset(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": bson.A{key, lockKeyPrefix + key}}})
+	if err != nil {
+		return fmt.Errorf("failed to reset rate limit state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type relayedTransactionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRelayedTransactionRepository creates a new MongoDB relayed transaction repository
+func NewRelayedTransactionRepository(db *mongo.Database) ports.RelayedTransactionRepository {
+	return &relayedTransactionRepository{
+		collection: db.Collection("relayed_transactions"),
+	}
+}
+
+func (r *relayedTransactionRepository) Create(ctx context.Context, tx *domain.RelayedTransaction) error {
+	if tx.ID.IsZero() {
+		tx.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	tx.CreatedAt = now
+	tx.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, tx)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrRelayNonceUsed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create relayed transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *relayedTransactionRepository) Update(ctx context.Context, tx *domain.RelayedTransaction) error {
+	tx.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": tx.ID}, tx)
+	if err != nil {
+		return fmt.Errorf("failed to update relayed transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *relayedTransactionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.RelayedTransaction, error) {
+	var tx domain.RelayedTransaction
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tx)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find relayed transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+func (r *relayedTransactionRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.RelayedTransaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	return r.find(ctx, bson.M{"userId": userID}, opts)
+}
+
+func (r *relayedTransactionRepository) FindByStatus(ctx context.Context, statuses ...domain.RelayStatus) ([]*domain.RelayedTransaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	return r.find(ctx, bson.M{"status": bson.M{"$in": statuses}}, opts)
+}
+
+func (r *relayedTransactionRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.RelayedTransaction, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find relayed transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var txs []*domain.RelayedTransaction
+	if err := cursor.All(ctx, &txs); err != nil {
+		return nil, fmt.Errorf("failed to decode relayed transactions: %w", err)
+	}
+	return txs, nil
+}
+
+func (r *relayedTransactionRepository) NextNonce(ctx context.Context, from string) (uint64, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "request.nonce", Value: -1}})
+	var last domain.RelayedTransaction
+	err := r.collection.FindOne(ctx, bson.M{"request.from": from}, opts).Decode(&last)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find last relay nonce: %w", err)
+	}
+	return last.Request.Nonce + 1, nil
+}
+
+func (r *relayedTransactionRepository) Usage(ctx context.Context, operator string, since time.Time) (map[domain.RelayOperation]domain.OperationUsage, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"operator":  operator,
+			"createdAt": bson.M{"$gte": since},
+			"feeGwei":   bson.M{"$gt": 0},
+		}},
+		{"$group": bson.M{
+			"_id":   "$request.operation",
+			"count": bson.M{"$sum": 1},
+			"spent": bson.M{"$sum": "$feeGwei"},
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum relay usage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Operation domain.RelayOperation `bson:"_id"`
+		Count     int                   `bson:"count"`
+		Spent     int64                 `bson:"spent"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode relay usage: %w", err)
+	}
+
+	usage := make(map[domain.RelayOperation]domain.OperationUsage)
+	for _, result := range results {
+		usage[result.Operation] = domain.OperationUsage{Transactions: result.Count, SpentGwei: result.Spent}
+	}
+	return usage, nil
+}
@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type replyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReplyRepository creates a new MongoDB reply repository
+func NewReplyRepository(db *mongo.Database) ports.ReplyRepository {
+	return &replyRepository{
+		collection: db.Collection("replies"),
+	}
+}
+
+func (r *replyRepository) Create(ctx context.Context, reply *domain.Reply) error {
+	if reply.ID.IsZero() {
+		reply.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	reply.CreatedAt = now
+	reply.UpdatedAt = now
+
+	if _, err := r.collection.InsertOne(ctx, reply); err != nil {
+		return fmt.Errorf("failed to create reply: %w", err)
+	}
+	return nil
+}
+
+func (r *replyRepository) FindByID(ctx context.Context, id string) (*domain.Reply, error) {
+	objectID, err := parseID(id, "reply")
+	if err != nil {
+		return nil, err
+	}
+
+	var reply domain.Reply
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&reply)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reply: %w", err)
+	}
+	return &reply, nil
+}
+
+// FindByAcknowledgement pages by ID, which orders replies by creation
+func (r *replyRepository) FindByAcknowledgement(ctx context.Context, acknowledgementID string, after string, limit int) ([]*domain.Reply, error) {
+	filter := bson.M{"acknowledgementId": acknowledgementID}
+	if after != "" {
+		afterID, err := parseID(after, "cursor")
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find replies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var replies []*domain.Reply
+	if err := cursor.All(ctx, &replies); err != nil {
+		return nil, fmt.Errorf("failed to decode replies: %w", err)
+	}
+	return replies, nil
+}
+
+// SetModerationStatus changes whether a reply is visible in its dialogue
+func (r *replyRepository) SetModerationStatus(ctx context.Context, id string, status domain.ModerationStatus) error {
+	objectID, err := parseID(id, "reply")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{
+		"moderationStatus": status,
+		"updatedAt":        time.Now(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update reply moderation status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type securityEventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSecurityEventRepository creates a new MongoDB security event repository
+func NewSecurityEventRepository(db *mongo.Database) ports.SecurityEventRepository {
+	return &securityEventRepository{
+		collection: db.Collection("security_events"),
+	}
+}
+
+func (r *securityEventRepository) Create(ctx context.Context, event *domain.SecurityEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+	return nil
+}
+
+func (r *securityEventRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.SecurityEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find security events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*domain.SecurityEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode security events: %w", err)
+	}
+	return events, nil
+}
@@ -9,8 +9,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Sessions are stored under the hash of their token, so a leaked database
+// does not hand out usable cookies. Expired sessions are removed by the TTL
+// index on expiresAt.
 type sessionRepository struct {
 	db *mongo.Database
 }
@@ -20,6 +24,10 @@ func NewSessionRepository(db *mongo.Database) *sessionRepository {
 }
 
 func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	session.TokenHash = domain.HashToken(session.Token)
 	_, err := r.db.Collection("sessions").InsertOne(ctx, session)
 	return err
 }
@@ -27,7 +35,7 @@ func (r *sessionRepository) Create(ctx context.Context, session *domain.Session)
 func (r *sessionRepository) FindByToken(ctx context.Context, token string) (*domain.Session, error) {
 	var session domain.Session
 	err := r.db.Collection("sessions").FindOne(ctx, bson.M{
-		"token": token,
+		"tokenHash": domain.HashToken(token),
 		"expiresAt": bson.M{
 			"$gt": time.Now(),
 		},
@@ -35,19 +43,42 @@ func (r *sessionRepository) FindByToken(ctx context.Context, token string) (*dom
 	if err == mongo.ErrNoDocuments {
 		return nil, nil
 	}
-	return &session, err
+	if err != nil {
+		return nil, err
+	}
+	session.Token = token
+	return &session, nil
+}
+
+func (r *sessionRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.Session, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "lastSeenAt", Value: -1}})
+	cursor, err := r.db.Collection("sessions").Find(ctx, bson.M{
+		"userId":         userID,
+		"isRegistration": false,
+		"webauthnData":   "",
+		"expiresAt":      bson.M{"$gt": time.Now()},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*domain.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode user sessions: %w", err)
+	}
+	return sessions, nil
 }
 
 func (r *sessionRepository) DeleteByToken(ctx context.Context, token string) error {
-	_, err := r.db.Collection("sessions").DeleteOne(ctx, bson.M{"token": token})
+	_, err := r.db.Collection("sessions").DeleteOne(ctx, bson.M{"tokenHash": domain.HashToken(token)})
 	return err
 }
 
-func (r *sessionRepository) DeleteExpired(ctx context.Context) error {
-	filter := bson.M{"expiresAt": bson.M{"$lt": time.Now()}}
-	_, err := r.db.Collection("sessions").DeleteMany(ctx, filter)
+func (r *sessionRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.db.Collection("sessions").DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
-		return fmt.Errorf("failed to delete expired sessions: %w", err)
+		return fmt.Errorf("failed to delete session: %w", err)
 	}
 	return nil
 }
@@ -67,14 +98,50 @@ func (r *sessionRepository) Update(ctx context.Context, session *domain.Session)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("session not found")
+		return domain.ErrSessionNotFound
 	}
 
 	return nil
 }
 
+func (r *sessionRepository) UpdateToken(ctx context.Context, id primitive.ObjectID, token string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"tokenHash": domain.HashToken(token),
+			"updatedAt": time.Now(),
+		},
+	}
+
+	result, err := r.db.Collection("sessions").UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to rotate session token: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id primitive.ObjectID, info domain.ClientInfo) error {
+	set := bson.M{"lastSeenAt": time.Now()}
+	if info.IPAddress != "" {
+		set["ipAddress"] = info.IPAddress
+	}
+	if info.UserAgent != "" {
+		set["userAgent"] = info.UserAgent
+	}
+
+	_, err := r.db.Collection("sessions").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
 func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID primitive.ObjectID) error {
-	_, err := r.db.Collection("sessions").DeleteMany(ctx, bson.M{"userID": userID})
+	_, err := r.db.Collection("sessions").DeleteMany(ctx, bson.M{"userId": userID.Hex()})
 	if err != nil {
 		return fmt.Errorf("failed to delete user sessions: %w", err)
 	}
@@ -2,7 +2,7 @@ package mongodb
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"proofofpeacemaking/internal/core/ports"
 	"time"
@@ -23,8 +23,6 @@ func NewStatisticsRepository(db *mongo.Database) ports.StatisticsRepository {
 }
 
 func (r *StatisticsRepository) GetLatest(ctx context.Context) (*domain.Statistics, error) {
-	log.Printf("[STATISTICS_REPO] Getting latest statistics")
-
 	// Explicitly sort by createdAt in descending order (-1)
 	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
 
@@ -37,7 +35,6 @@ func (r *StatisticsRepository) GetLatest(ctx context.Context) (*domain.Statistic
 
 	err := r.collection.FindOne(ctx, filter, opts).Decode(&stats)
 	if err == mongo.ErrNoDocuments {
-		log.Printf("[STATISTICS_REPO] No statistics records found")
 		// Return empty statistics if no records exist
 		return &domain.Statistics{
 			CitizenshipStats: make(map[string]int),
@@ -45,7 +42,7 @@ func (r *StatisticsRepository) GetLatest(ctx context.Context) (*domain.Statistic
 		}, nil
 	}
 	if err != nil {
-		log.Printf("[STATISTICS_REPO] Error retrieving statistics: %v", err)
+		slog.ErrorContext(ctx, "failed to retrieve statistics", "error", err)
 		return nil, err
 	}
 
@@ -57,31 +54,22 @@ func (r *StatisticsRepository) GetLatest(ctx context.Context) (*domain.Statistic
 		stats.MediaStats = make(map[string]int)
 	}
 
-	log.Printf("[STATISTICS_REPO] Retrieved statistics: Users=%d, Expressions=%d, Acks=%d",
-		stats.TotalUsers, stats.TotalExpressions, stats.TotalAcknowledgements)
-	log.Printf("[STATISTICS_REPO] CitizenshipStats: %v", stats.CitizenshipStats)
-	log.Printf("[STATISTICS_REPO] MediaStats: %v", stats.MediaStats)
 	return &stats, nil
 }
 
 func (r *StatisticsRepository) Create(ctx context.Context, stats *domain.Statistics) error {
-	log.Printf("[STATISTICS_REPO] Creating new statistics record")
-
 	// Ensure createdAt is set to current time
 	stats.CreatedAt = time.Now()
 
 	_, err := r.collection.InsertOne(ctx, stats)
 	if err != nil {
-		log.Printf("[STATISTICS_REPO] Error creating statistics record: %v", err)
+		slog.ErrorContext(ctx, "failed to create statistics record", "error", err)
 		return err
 	}
-	log.Printf("[STATISTICS_REPO] Statistics record created successfully")
 	return nil
 }
 
 func (r *StatisticsRepository) GetCountryList(ctx context.Context) ([]domain.CountryInfo, error) {
-	log.Printf("[STATISTICS_REPO] Getting country list")
 	countries := domain.GetCountryList()
-	log.Printf("[STATISTICS_REPO] Retrieved %d countries", len(countries))
 	return countries, nil
 }
@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// unconfirmedSubsidyChanges are the statuses of changes not yet seen on-chain
+var unconfirmedSubsidyChanges = []domain.SubsidyChangeStatus{domain.SubsidyChangeQueued, domain.SubsidyChangeSent}
+
+type subsidyChangeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSubsidyChangeRepository creates a new MongoDB subsidy change repository
+func NewSubsidyChangeRepository(db *mongo.Database) ports.SubsidyChangeRepository {
+	return &subsidyChangeRepository{
+		collection: db.Collection("subsidy_changes"),
+	}
+}
+
+func (r *subsidyChangeRepository) Create(ctx context.Context, change *domain.SubsidyChange) error {
+	if change.ID.IsZero() {
+		change.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	change.CreatedAt = now
+	change.UpdatedAt = now
+
+	if _, err := r.collection.InsertOne(ctx, change); err != nil {
+		return fmt.Errorf("failed to create subsidy change: %w", err)
+	}
+	return nil
+}
+
+func (r *subsidyChangeRepository) Update(ctx context.Context, change *domain.SubsidyChange) error {
+	change.UpdatedAt = time.Now()
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": change.ID}, change); err != nil {
+		return fmt.Errorf("failed to update subsidy change: %w", err)
+	}
+	return nil
+}
+
+func (r *subsidyChangeRepository) FindByStatus(ctx context.Context, status domain.SubsidyChangeStatus, limit int) ([]*domain.SubsidyChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"status": status}, opts)
+}
+
+func (r *subsidyChangeRepository) FindUnconfirmed(ctx context.Context, userID primitive.ObjectID, operation string) ([]*domain.SubsidyChange, error) {
+	filter := bson.M{
+		"userId":    userID,
+		"operation": operation,
+		"status":    bson.M{"$in": unconfirmedSubsidyChanges},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	return r.find(ctx, filter, opts)
+}
+
+func (r *subsidyChangeRepository) List(ctx context.Context, status domain.SubsidyChangeStatus, limit int) ([]*domain.SubsidyChange, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(int64(limit))
+	return r.find(ctx, filter, opts)
+}
+
+func (r *subsidyChangeRepository) CountUnconfirmed(ctx context.Context) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"status": bson.M{"$in": unconfirmedSubsidyChanges}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count subsidy changes: %w", err)
+	}
+	return int(count), nil
+}
+
+func (r *subsidyChangeRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.SubsidyChange, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subsidy changes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var changes []*domain.SubsidyChange
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("failed to decode subsidy changes: %w", err)
+	}
+	return changes, nil
+}
+
+type operatorBudgetRepository struct {
+	collection *mongo.Collection
+}
+
+// NewOperatorBudgetRepository creates a new MongoDB operator budget repository
+func NewOperatorBudgetRepository(db *mongo.Database) ports.OperatorBudgetRepository {
+	return &operatorBudgetRepository{
+		collection: db.Collection("operator_budgets"),
+	}
+}
+
+func (r *operatorBudgetRepository) Get(ctx context.Context, operator string) (*domain.OperatorBudget, error) {
+	var budget domain.OperatorBudget
+	err := r.collection.FindOne(ctx, bson.M{"_id": operator}).Decode(&budget)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find operator budget: %w", err)
+	}
+	return &budget, nil
+}
+
+func (r *operatorBudgetRepository) Set(ctx context.Context, budget *domain.OperatorBudget) error {
+	budget.UpdatedAt = time.Now()
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": budget.Operator}, budget, opts); err != nil {
+		return fmt.Errorf("failed to set operator budget: %w", err)
+	}
+	return nil
+}
+
+func (r *operatorBudgetRepository) Delete(ctx context.Context, operator string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": operator})
+	if err != nil {
+		return fmt.Errorf("failed to delete operator budget: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.NotFound("operator budget not found")
+	}
+	return nil
+}
+
+func (r *operatorBudgetRepository) List(ctx context.Context) ([]*domain.OperatorBudget, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find operator budgets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var budgets []*domain.OperatorBudget
+	if err := cursor.All(ctx, &budgets); err != nil {
+		return nil, fmt.Errorf("failed to decode operator budgets: %w", err)
+	}
+	return budgets, nil
+}
@@ -2,9 +2,8 @@ package mongodb
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"proofofpeacemaking/internal/core/domain"
 	"regexp"
 	"strings"
@@ -32,7 +31,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 			return err
 		}
 		if exists {
-			return errors.New("email already exists")
+			return domain.Conflict("email already exists")
 		}
 	}
 
@@ -43,7 +42,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 			return err
 		}
 		if exists {
-			return errors.New("username already exists")
+			return domain.Conflict("username already exists")
 		}
 	}
 
@@ -54,7 +53,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 			return err
 		}
 		if exists {
-			return errors.New("address already exists")
+			return domain.Conflict("address already exists")
 		}
 	}
 
@@ -63,6 +62,10 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 	result, err := r.db.Collection("users").InsertOne(ctx, user)
 	if err != nil {
+		// A parallel sign-up can pass the checks above and lose on the unique index
+		if conflict := duplicateKeyError(err); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -80,7 +83,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 			return err
 		}
 		if exists {
-			return errors.New("email already exists")
+			return domain.Conflict("email already exists")
 		}
 		updateFields["email"] = user.Email
 	}
@@ -92,7 +95,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 			return err
 		}
 		if exists {
-			return errors.New("username already exists")
+			return domain.Conflict("username already exists")
 		}
 		updateFields["username"] = user.Username
 	}
@@ -116,18 +119,21 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 
 	result, err := r.db.Collection("users").UpdateOne(ctx, filter, update)
 	if err != nil {
+		if conflict := duplicateKeyError(err); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	return nil
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseID(id, "user")
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +241,7 @@ func (r *UserRepository) ConnectWallet(ctx context.Context, userID primitive.Obj
 		return err
 	}
 	if exists > 0 {
-		return errors.New("wallet already connected to another account")
+		return domain.Conflict("wallet already connected to another account")
 	}
 
 	// Update user with wallet address
@@ -253,12 +259,122 @@ func (r *UserRepository) ConnectWallet(ctx context.Context, userID primitive.Obj
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return domain.NotFound("user not found")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, userID primitive.ObjectID, role domain.Role) error {
+	update := bson.M{
+		"$set": bson.M{
+			"role":      role,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	result, err := r.db.Collection("users").UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.NotFound("user not found")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) SetSuspendedUntil(ctx context.Context, userID primitive.ObjectID, until *time.Time) error {
+	update := bson.M{"$set": bson.M{"suspendedUntil": until, "updatedAt": time.Now()}}
+	if until == nil {
+		update = bson.M{
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$unset": bson.M{"suspendedUntil": ""},
+		}
+	}
+
+	result, err := r.db.Collection("users").UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update suspension: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.NotFound("user not found")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetByAddressIgnoreCase(ctx context.Context, address string) (*domain.User, error) {
+	var user domain.User
+	filter := bson.M{"address": bson.M{"$regex": "^" + regexp.QuoteMeta(address) + "$", "$options": "i"}}
+	err := r.db.Collection("users").FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user by address: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) SetSubsidy(ctx context.Context, userID primitive.ObjectID, operation string, granted bool) error {
+	change := "$pull"
+	if granted {
+		change = "$addToSet"
+	}
+	update := bson.M{
+		change: bson.M{"subsidizedOperations": operation},
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := r.db.Collection("users").UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update subsidies: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.NotFound("user not found")
 	}
 
 	return nil
 }
 
+// GetSubsidyCounts returns how many users hold each subsidized operation
+func (r *UserRepository) GetSubsidyCounts(ctx context.Context) (map[string]int, error) {
+	pipeline := []bson.M{
+		{"$unwind": "$subsidizedOperations"},
+		{
+			"$group": bson.M{
+				"_id":   "$subsidizedOperations",
+				"count": bson.M{"$sum": 1},
+			},
+		},
+	}
+
+	cursor, err := r.db.Collection("users").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subsidies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID    string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode subsidy counts: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.ID] = result.Count
+	}
+	return counts, nil
+}
+
 func (r *UserRepository) UpdateNonce(ctx context.Context, id primitive.ObjectID, nonce int) error {
 	filter := bson.M{"_id": id}
 	update := bson.M{
@@ -274,7 +390,7 @@ func (r *UserRepository) UpdateNonce(ctx context.Context, id primitive.ObjectID,
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	return nil
@@ -286,7 +402,7 @@ func (r *UserRepository) Delete(ctx context.Context, id primitive.ObjectID) erro
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("user not found")
+		return domain.NotFound("user not found")
 	}
 	return nil
 }
@@ -352,7 +468,7 @@ func (r *UserRepository) cleanupDuplicates(ctx context.Context) error {
 	for cursor.Next(ctx) {
 		var user domain.User
 		if err := cursor.Decode(&user); err != nil {
-			log.Printf("[WARN] Failed to decode user: %v", err)
+			slog.WarnContext(ctx, "failed to decode user", "error", err)
 			continue
 		}
 
@@ -362,14 +478,14 @@ func (r *UserRepository) cleanupDuplicates(ctx context.Context) error {
 			if existingID, exists := seenEmails[emailLower]; exists {
 				// Keep the older record (smaller ObjectID), clear email from newer one
 				if user.ID.Hex() > existingID.Hex() {
-					log.Printf("[INFO] Clearing duplicate email %s from user %s", user.Email, user.ID.Hex())
+					slog.InfoContext(ctx, "clearing duplicate email", "user_id", user.ID.Hex())
 					if err := r.clearEmail(ctx, user.ID); err != nil {
-						log.Printf("[ERROR] Failed to clear email: %v", err)
+						slog.ErrorContext(ctx, "failed to clear email", "error", err)
 					}
 				} else {
-					log.Printf("[INFO] Clearing duplicate email %s from user %s", user.Email, existingID.Hex())
+					slog.InfoContext(ctx, "clearing duplicate email", "user_id", existingID.Hex())
 					if err := r.clearEmail(ctx, existingID); err != nil {
-						log.Printf("[ERROR] Failed to clear email: %v", err)
+						slog.ErrorContext(ctx, "failed to clear email", "error", err)
 					}
 					seenEmails[emailLower] = user.ID
 				}
@@ -384,14 +500,14 @@ func (r *UserRepository) cleanupDuplicates(ctx context.Context) error {
 			if existingID, exists := seenUsernames[usernameLower]; exists {
 				// Keep the older record (smaller ObjectID), clear username from newer one
 				if user.ID.Hex() > existingID.Hex() {
-					log.Printf("[INFO] Clearing duplicate username %s from user %s", user.Username, user.ID.Hex())
+					slog.InfoContext(ctx, "clearing duplicate username", "user_id", user.ID.Hex())
 					if err := r.clearUsername(ctx, user.ID); err != nil {
-						log.Printf("[ERROR] Failed to clear username: %v", err)
+						slog.ErrorContext(ctx, "failed to clear username", "error", err)
 					}
 				} else {
-					log.Printf("[INFO] Clearing duplicate username %s from user %s", user.Username, existingID.Hex())
+					slog.InfoContext(ctx, "clearing duplicate username", "user_id", existingID.Hex())
 					if err := r.clearUsername(ctx, existingID); err != nil {
-						log.Printf("[ERROR] Failed to clear username: %v", err)
+						slog.ErrorContext(ctx, "failed to clear username", "error", err)
 					}
 					seenUsernames[usernameLower] = user.ID
 				}
@@ -427,18 +543,18 @@ func (r *UserRepository) clearUsername(ctx context.Context, userID primitive.Obj
 }
 
 func (r *UserRepository) EnsureIndexes(ctx context.Context) error {
-	log.Printf("[DB] Dropping all indexes from users collection...")
+	slog.InfoContext(ctx, "dropping all indexes from users collection")
 	_, err := r.db.Collection("users").Indexes().DropAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to drop indexes: %w", err)
 	}
 
-	log.Printf("[DB] Cleaning up duplicate entries...")
+	slog.InfoContext(ctx, "cleaning up duplicate users")
 	if err := r.cleanupDuplicates(ctx); err != nil {
 		return fmt.Errorf("failed to cleanup duplicates: %w", err)
 	}
 
-	log.Printf("[DB] Recreating indexes...")
+	slog.InfoContext(ctx, "recreating user indexes")
 	// Create a unique index for non-null addresses
 	addressIndex := mongo.IndexModel{
 		Keys:    bson.D{{"address", 1}},
@@ -467,17 +583,17 @@ func (r *UserRepository) EnsureIndexes(ctx context.Context) error {
 	if _, err := collection.Indexes().CreateOne(ctx, addressIndex); err != nil {
 		return fmt.Errorf("failed to create address index: %w", err)
 	}
-	log.Printf("[DB] Created new index 'address_1' on collection 'users' (address:ASC)")
+	slog.InfoContext(ctx, "created index", "index", "address_1", "collection", "users")
 
 	if _, err := collection.Indexes().CreateOne(ctx, emailIndex); err != nil {
 		return fmt.Errorf("failed to create email index: %w", err)
 	}
-	log.Printf("[DB] Created new index 'email_1' on collection 'users' (email:ASC)")
+	slog.InfoContext(ctx, "created index", "index", "email_1", "collection", "users")
 
 	if _, err := collection.Indexes().CreateOne(ctx, usernameIndex); err != nil {
 		return fmt.Errorf("failed to create username index: %w", err)
 	}
-	log.Printf("[DB] Created new index 'username_1' on collection 'users' (username:ASC)")
+	slog.InfoContext(ctx, "created index", "index", "username_1", "collection", "users")
 
 	return nil
 }
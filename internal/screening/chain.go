@@ -0,0 +1,43 @@
+package screening
+
+import (
+	"context"
+	"log/slog"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+)
+
+// Chain runs screeners in order and stops at the first one that flags the content
+type Chain struct {
+	screeners []ports.ContentScreener
+}
+
+// NewChain combines screeners into a single ContentScreener
+func NewChain(screeners ...ports.ContentScreener) ports.ContentScreener {
+	return &Chain{screeners: screeners}
+}
+
+func (c *Chain) Name() string {
+	return "chain"
+}
+
+// Screen returns the first flagging result. A screener that fails flags the
+// content for review rather than letting it through unchecked.
+func (c *Chain) Screen(ctx context.Context, input *domain.ScreeningInput) (*domain.ScreeningResult, error) {
+	for _, screener := range c.screeners {
+		result, err := screener.Screen(ctx, input)
+		if err != nil {
+			slog.ErrorContext(ctx, "screener failed", "screener", screener.Name(), "content_type", input.ContentType, "content_id", input.ContentID, "error", err)
+			return &domain.ScreeningResult{
+				Flagged:  true,
+				Screener: screener.Name(),
+				Reason:   domain.ReportReasonOther,
+				Matches:  []string{"screening unavailable"},
+			}, nil
+		}
+		if result.Flagged {
+			return result, nil
+		}
+	}
+	return &domain.ScreeningResult{Screener: c.Name()}, nil
+}
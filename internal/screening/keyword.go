@@ -0,0 +1,129 @@
+package screening
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"regexp"
+	"strings"
+)
+
+// globalLocale is the list applied to content in every language
+const globalLocale = "global"
+
+// KeywordScreener flags text that matches per-locale keyword and regex lists
+type KeywordScreener struct {
+	lists map[string][]*regexp.Regexp
+}
+
+// NewKeywordScreener builds a screener from entries keyed by locale. An entry
+// prefixed with "re:" is a regular expression; anything else matches as a
+// whole word or phrase, case-insensitively.
+func NewKeywordScreener(entries map[string][]string) (ports.ContentScreener, error) {
+	lists := make(map[string][]*regexp.Regexp, len(entries))
+	for locale, words := range entries {
+		for _, word := range words {
+			pattern, err := compileEntry(word)
+			if err != nil {
+				return nil, fmt.Errorf("invalid screening entry %q for locale %s: %w", word, locale, err)
+			}
+			lists[strings.ToLower(locale)] = append(lists[strings.ToLower(locale)], pattern)
+		}
+	}
+	return &KeywordScreener{lists: lists}, nil
+}
+
+// LoadKeywordScreener reads one list per locale from <dir>/<locale>.txt.
+// Blank lines and lines starting with # are ignored.
+func LoadKeywordScreener(dir string) (ports.ContentScreener, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list screening lists: %w", err)
+	}
+
+	entries := make(map[string][]string, len(files))
+	for _, file := range files {
+		locale := strings.TrimSuffix(filepath.Base(file), ".txt")
+		words, err := readList(file)
+		if err != nil {
+			return nil, err
+		}
+		entries[locale] = words
+	}
+
+	return NewKeywordScreener(entries)
+}
+
+func (s *KeywordScreener) Name() string {
+	return "keyword"
+}
+
+// Screen checks the text against the global list and the list for the author's
+// language. When the language is unknown or has no list, every list is checked.
+func (s *KeywordScreener) Screen(ctx context.Context, input *domain.ScreeningInput) (*domain.ScreeningResult, error) {
+	result := &domain.ScreeningResult{Screener: s.Name()}
+	if input.Text == "" {
+		return result, nil
+	}
+
+	for _, pattern := range s.patternsFor(input.Locale) {
+		if match := pattern.FindString(input.Text); match != "" {
+			result.Matches = append(result.Matches, match)
+		}
+	}
+
+	if len(result.Matches) > 0 {
+		result.Flagged = true
+		result.Reason = domain.ReportReasonHateSpeech
+	}
+	return result, nil
+}
+
+func (s *KeywordScreener) patternsFor(locale string) []*regexp.Regexp {
+	// Match on the primary language subtag so "en-GB" uses the "en" list
+	language := strings.ToLower(strings.SplitN(locale, "-", 2)[0])
+	if localized, ok := s.lists[language]; ok && language != "" {
+		patterns := make([]*regexp.Regexp, 0, len(localized)+len(s.lists[globalLocale]))
+		patterns = append(patterns, localized...)
+		return append(patterns, s.lists[globalLocale]...)
+	}
+
+	var all []*regexp.Regexp
+	for _, patterns := range s.lists {
+		all = append(all, patterns...)
+	}
+	return all
+}
+
+func compileEntry(entry string) (*regexp.Regexp, error) {
+	if pattern, ok := strings.CutPrefix(entry, "re:"); ok {
+		return regexp.Compile("(?i)" + pattern)
+	}
+	return regexp.Compile(`(?i)\b` + regexp.QuoteMeta(entry) + `\b`)
+}
+
+func readList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open screening list: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read screening list %s: %w", path, err)
+	}
+	return words, nil
+}
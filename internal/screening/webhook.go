@@ -0,0 +1,97 @@
+package screening
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"proofofpeacemaking/internal/core/domain"
+	"proofofpeacemaking/internal/core/ports"
+	"time"
+)
+
+// WebhookScreener sends content to an external HTTP service for a verdict. The
+// service receives a JSON body with contentType, contentId, text, media and
+// locale, and answers with {"flagged": bool, "reason": string, "matches": [string]}.
+type WebhookScreener struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookScreener creates a screener that posts to url. If secret is set it
+// is sent as a bearer token so the service can reject other callers.
+func NewWebhookScreener(url, secret string, timeout time.Duration) ports.ContentScreener {
+	return &WebhookScreener{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookScreener) Name() string {
+	return "webhook"
+}
+
+type webhookRequest struct {
+	ContentType domain.ContentType `json:"contentType"`
+	ContentID   string             `json:"contentId"`
+	Text        string             `json:"text"`
+	Media       map[string]string  `json:"media"`
+	Locale      string             `json:"locale"`
+}
+
+type webhookResponse struct {
+	Flagged bool                `json:"flagged"`
+	Reason  domain.ReportReason `json:"reason"`
+	Matches []string            `json:"matches"`
+}
+
+func (s *WebhookScreener) Screen(ctx context.Context, input *domain.ScreeningInput) (*domain.ScreeningResult, error) {
+	body, err := json.Marshal(webhookRequest{
+		ContentType: input.ContentType,
+		ContentID:   input.ContentID,
+		Text:        input.Text,
+		Media:       input.Media,
+		Locale:      input.Locale,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode screening request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build screening request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		req.Header.Set("Authorization", "Bearer "+s.secret)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("screening webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("screening webhook returned status %d", resp.StatusCode)
+	}
+
+	var verdict webhookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode screening response: %w", err)
+	}
+
+	result := &domain.ScreeningResult{
+		Flagged:  verdict.Flagged,
+		Screener: s.Name(),
+		Reason:   verdict.Reason,
+		Matches:  verdict.Matches,
+	}
+	if result.Flagged && !domain.ValidReportReason(result.Reason) {
+		result.Reason = domain.ReportReasonOther
+	}
+	return result, nil
+}
@@ -35,7 +35,8 @@ async function deployDiamond() {
         'ExpressionFacet',
         'AcknowledgementFacet',
         'POPNFTFacet',
-        'PermissionsFacet'
+        'PermissionsFacet',
+        'AnchorFacet'
     ];
     
     const cut = [];
@@ -114,6 +114,278 @@ document.addEventListener('DOMContentLoaded', function() {
         }
     });
 
+    // Passkey management
+    const passkeyList = document.getElementById('passkeyList');
+    const passkeyError = document.getElementById('passkeyError');
+
+    function showPasskeyError(message) {
+        passkeyError.textContent = message;
+        passkeyError.style.display = 'block';
+    }
+
+    async function loadPasskeys() {
+        if (!passkeyList) return;
+        passkeyError.style.display = 'none';
+
+        try {
+            const response = await fetch('/api/passkeys');
+            if (!response.ok) {
+                throw new Error('Failed to load passkeys');
+            }
+            const passkeys = await response.json();
+
+            passkeyList.innerHTML = '';
+            if (passkeys.length === 0) {
+                const empty = document.createElement('li');
+                empty.className = 'passkey-meta';
+                empty.textContent = 'No passkeys registered.';
+                passkeyList.appendChild(empty);
+                return;
+            }
+
+            passkeys.forEach(passkey => {
+                const item = document.createElement('li');
+                item.className = 'passkey-item' + (passkey.isActive ? '' : ' revoked');
+
+                const info = document.createElement('div');
+                const name = document.createElement('div');
+                name.textContent = passkey.name;
+                const meta = document.createElement('div');
+                meta.className = 'passkey-meta';
+                const lastUsed = new Date(passkey.lastUsedAt).toLocaleString();
+                meta.textContent = (passkey.deviceInfo ? passkey.deviceInfo + ' · ' : '') +
+                    (passkey.isActive ? 'Last used ' + lastUsed : 'Revoked');
+                info.appendChild(name);
+                info.appendChild(meta);
+                item.appendChild(info);
+
+                if (passkey.isActive) {
+                    const actions = document.createElement('div');
+                    actions.className = 'passkey-actions';
+
+                    const renameBtn = document.createElement('button');
+                    renameBtn.type = 'button';
+                    renameBtn.className = 'btn-secondary';
+                    renameBtn.textContent = 'Rename';
+                    renameBtn.addEventListener('click', () => renamePasskey(passkey));
+
+                    const revokeBtn = document.createElement('button');
+                    revokeBtn.type = 'button';
+                    revokeBtn.className = 'btn-secondary';
+                    revokeBtn.textContent = 'Revoke';
+                    revokeBtn.addEventListener('click', () => revokePasskey(passkey));
+
+                    actions.appendChild(renameBtn);
+                    actions.appendChild(revokeBtn);
+                    item.appendChild(actions);
+                }
+
+                passkeyList.appendChild(item);
+            });
+        } catch (error) {
+            console.error('Error loading passkeys:', error);
+            showPasskeyError('Could not load your passkeys.');
+        }
+    }
+
+    async function renamePasskey(passkey) {
+        const name = prompt('New name for this passkey', passkey.name);
+        if (!name) return;
+
+        const response = await fetch(`/api/passkeys/${passkey.id}`, {
+            method: 'PUT',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ name }),
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            showPasskeyError(data.error || 'Failed to rename passkey');
+            return;
+        }
+        loadPasskeys();
+    }
+
+    async function revokePasskey(passkey) {
+        if (!confirm(`Revoke "${passkey.name}"? You will no longer be able to sign in with it.`)) return;
+
+        const response = await fetch(`/api/passkeys/${passkey.id}`, { method: 'DELETE' });
+        if (!response.ok) {
+            const data = await response.json();
+            showPasskeyError(data.error || 'Failed to revoke passkey');
+            return;
+        }
+        loadPasskeys();
+    }
+
+    loadPasskeys();
+
+    // Session management
+    const sessionList = document.getElementById('sessionList');
+    const sessionError = document.getElementById('sessionError');
+
+    async function loadSessions() {
+        if (!sessionList) return;
+        sessionError.style.display = 'none';
+
+        try {
+            const response = await fetch('/api/sessions');
+            if (!response.ok) {
+                throw new Error('Failed to load sessions');
+            }
+            const sessions = await response.json();
+
+            sessionList.innerHTML = '';
+            sessions.forEach(session => {
+                const item = document.createElement('li');
+                item.className = 'passkey-item';
+
+                const info = document.createElement('div');
+                const device = document.createElement('div');
+                device.textContent = session.userAgent || 'Unknown device';
+                const meta = document.createElement('div');
+                meta.className = 'passkey-meta';
+                const lastSeen = new Date(session.lastSeenAt).toLocaleString();
+                meta.textContent = (session.ipAddress ? session.ipAddress + ' · ' : '') +
+                    (session.current ? 'This device' : 'Last active ' + lastSeen);
+                info.appendChild(device);
+                info.appendChild(meta);
+                item.appendChild(info);
+
+                if (!session.current) {
+                    const revokeBtn = document.createElement('button');
+                    revokeBtn.type = 'button';
+                    revokeBtn.className = 'btn-secondary';
+                    revokeBtn.textContent = 'Sign out';
+                    revokeBtn.addEventListener('click', () => revokeSession(session));
+                    item.appendChild(revokeBtn);
+                }
+
+                sessionList.appendChild(item);
+            });
+        } catch (error) {
+            console.error('Error loading sessions:', error);
+            sessionError.textContent = 'Could not load your sessions.';
+            sessionError.style.display = 'block';
+        }
+    }
+
+    async function revokeSession(session) {
+        const response = await fetch(`/api/sessions/${session.id}`, { method: 'DELETE' });
+        if (!response.ok) {
+            const data = await response.json();
+            sessionError.textContent = data.error || 'Failed to sign out session';
+            sessionError.style.display = 'block';
+            return;
+        }
+        loadSessions();
+    }
+
+    loadSessions();
+
+    // API token management
+    const apiTokenForm = document.getElementById('apiTokenForm');
+    const apiTokenList = document.getElementById('apiTokenList');
+    const apiTokenError = document.getElementById('apiTokenError');
+    const apiTokenCreated = document.getElementById('apiTokenCreated');
+
+    function showApiTokenError(message) {
+        apiTokenError.textContent = message;
+        apiTokenError.style.display = 'block';
+    }
+
+    async function loadApiTokens() {
+        if (!apiTokenList) return;
+        apiTokenError.style.display = 'none';
+
+        try {
+            const response = await fetch('/api/tokens');
+            if (!response.ok) {
+                throw new Error('Failed to load tokens');
+            }
+            const tokens = await response.json();
+
+            apiTokenList.innerHTML = '';
+            tokens.forEach(token => {
+                const active = !token.revokedAt && new Date(token.expiresAt) > new Date();
+                const item = document.createElement('li');
+                item.className = 'passkey-item' + (active ? '' : ' revoked');
+
+                const info = document.createElement('div');
+                const name = document.createElement('div');
+                name.textContent = `${token.name} (…${token.hint})`;
+                const meta = document.createElement('div');
+                meta.className = 'passkey-meta';
+                const status = token.revokedAt ? 'Revoked' :
+                    (active ? 'Expires ' + new Date(token.expiresAt).toLocaleDateString() : 'Expired');
+                meta.textContent = token.scopes.join(', ') + ' · ' + status;
+                info.appendChild(name);
+                info.appendChild(meta);
+                item.appendChild(info);
+
+                if (active) {
+                    const revokeBtn = document.createElement('button');
+                    revokeBtn.type = 'button';
+                    revokeBtn.className = 'btn-secondary';
+                    revokeBtn.textContent = 'Revoke';
+                    revokeBtn.addEventListener('click', () => revokeApiToken(token));
+                    item.appendChild(revokeBtn);
+                }
+
+                apiTokenList.appendChild(item);
+            });
+        } catch (error) {
+            console.error('Error loading API tokens:', error);
+            showApiTokenError('Could not load your API tokens.');
+        }
+    }
+
+    async function revokeApiToken(token) {
+        if (!confirm(`Revoke "${token.name}"? Tools using it will stop working.`)) return;
+
+        const response = await fetch(`/api/tokens/${token.id}`, { method: 'DELETE' });
+        if (!response.ok) {
+            const data = await response.json();
+            showApiTokenError(data.error || 'Failed to revoke token');
+            return;
+        }
+        loadApiTokens();
+    }
+
+    if (apiTokenForm) {
+        apiTokenForm.addEventListener('submit', async function(e) {
+            e.preventDefault();
+            apiTokenError.style.display = 'none';
+
+            const scopes = Array.from(apiTokenForm.querySelectorAll('input[name="scopes"]:checked'))
+                .map(input => input.value);
+            const response = await fetch('/api/tokens', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({
+                    name: document.getElementById('apiTokenName').value,
+                    scopes,
+                    expiresInDays: parseInt(document.getElementById('apiTokenExpiry').value, 10),
+                }),
+            });
+            const data = await response.json();
+            if (!response.ok) {
+                showApiTokenError(data.error || 'Failed to create token');
+                return;
+            }
+
+            apiTokenCreated.textContent = `Copy this token now, it will not be shown again: ${data.token}`;
+            apiTokenCreated.style.display = 'block';
+            apiTokenForm.reset();
+            loadApiTokens();
+        });
+    }
+
+    loadApiTokens();
+
     // Handle wallet connection
     window.startWalletConnection = async function() {
         try {
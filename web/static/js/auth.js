@@ -779,19 +779,18 @@ async function registerWithPasskey() {
 
 async function loginWithPasskey() {
     try {
-        // Get user email first
+        // Without an email, fall back to a usernameless login where the
+        // authenticator picks one of its stored (discoverable) passkeys
         const email = document.getElementById('loginEmail').value;
-        if (!email) {
-            throw new Error('Please enter your email to authenticate with passkey');
-        }
+        const endpoint = email ? '/auth/passkey/auth' : '/auth/passkey/login';
         
-        // Get authentication options from server with email
-        const response = await fetch('/auth/passkey/auth/begin', {
+        // Get authentication options from server
+        const response = await fetch(`${endpoint}/begin`, {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json',
             },
-            body: JSON.stringify({ email }),
+            body: JSON.stringify(email ? { email } : {}),
             credentials: 'include'
         });
         
@@ -838,7 +837,7 @@ async function loginWithPasskey() {
         console.log('Sending verification to server:', credentialResponse);
         
         // Send credential to server
-        const verifyResponse = await fetch('/auth/passkey/auth/finish', {
+        const verifyResponse = await fetch(`${endpoint}/finish`, {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json',
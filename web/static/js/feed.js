@@ -60,6 +60,43 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     });
 
+    // Report expressions to the moderators
+    const reportReasons = ['hate_speech', 'harassment', 'violence', 'misinformation', 'spam', 'privacy', 'other'];
+    document.querySelectorAll('.report-button').forEach(button => {
+        button.addEventListener('click', async function(e) {
+            e.preventDefault();
+            const reason = prompt(`Why are you reporting this expression?\n(${reportReasons.join(', ')})`, 'other');
+            if (!reason) return;
+            if (!reportReasons.includes(reason.trim())) {
+                alert('Please choose one of: ' + reportReasons.join(', '));
+                return;
+            }
+
+            try {
+                const response = await fetch('/api/reports', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json'
+                    },
+                    body: JSON.stringify({
+                        contentType: 'expression',
+                        contentId: this.dataset.expressionId,
+                        reason: reason.trim()
+                    })
+                });
+                const data = await response.json();
+                if (!response.ok) {
+                    alert(data.error || 'Failed to report expression');
+                    return;
+                }
+                this.disabled = true;
+                alert('Thank you. Our moderators will review this expression.');
+            } catch (error) {
+                console.error('Error reporting expression:', error);
+            }
+        });
+    });
+
     // Format time in MM:SS
     const formatTime = time => {
         if (!isFinite(time)) return '0:00';
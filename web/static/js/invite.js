@@ -0,0 +1,43 @@
+// Invite links: walk new users through registration, then accept
+
+function inviteCard() {
+    return document.querySelector('.invite');
+}
+
+// Opens the auth modal on the register form, with the invited email filled in
+async function openInviteRegistration() {
+    await openAuthModal();
+    switchAuthMode('register');
+
+    const email = inviteCard().dataset.inviteeEmail;
+    const emailInput = document.getElementById('registerEmail');
+    if (email && emailInput) {
+        emailInput.value = email;
+    }
+}
+
+async function acceptInvitation() {
+    const errorElement = document.getElementById('inviteError');
+    errorElement.hidden = true;
+
+    try {
+        const response = await fetch('/api/invitations/accept', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ token: inviteCard().dataset.token }),
+            credentials: 'include'
+        });
+        const data = await response.json();
+        if (!response.ok) {
+            throw new Error(data.detail || data.error || 'Failed to accept the invitation');
+        }
+        window.location.href = data.redirect || '/feed';
+    } catch (error) {
+        console.error('Error accepting invitation:', error);
+        errorElement.textContent = error.message;
+        errorElement.hidden = false;
+    }
+}
+
+window.openInviteRegistration = openInviteRegistration;
+window.acceptInvitation = acceptInvitation;
@@ -513,6 +513,7 @@ class ExpressionForm {
         // Get text content from contenteditable div
         const textContent = this.form.querySelector('.text-input').innerText.trim();
         formData.append('textContent', textContent);
+        formData.append('locale', navigator.language || '');
         
         // Add image if exists
         const imageInput = document.getElementById('imageInput');
@@ -566,6 +567,10 @@ class ExpressionForm {
             }
 
             console.log('Expression created successfully');
+            const created = await response.json();
+            if (created.Status === 'pending_review') {
+                alert('Thanks for sharing. Your expression will appear once a moderator has reviewed it.');
+            }
             
             // Clear form and close modal
             this.form.reset();